@@ -5,6 +5,7 @@ import (
 	"finance-hub-api/internal/handlers"
 	"finance-hub-api/internal/repositories"
 	"finance-hub-api/internal/services"
+	"finance-hub-api/internal/utils"
 	"finance-hub-api/pkg/database"
 	"finance-hub-api/pkg/logger"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func main() {
@@ -37,6 +39,42 @@ func main() {
 	}
 	defer db.Close()
 
+	// Assign data created before workspaces existed to each owner's personal workspace
+	if err := repositories.BackfillWorkspaceIDs(db.Database); err != nil {
+		logger.Log.Warn.Printf("Failed to backfill workspace IDs: %v", err)
+	}
+
+	// List and search indexes, plus search fields and status for older transactions
+	if err := repositories.EnsureTransactionIndexes(db.Database); err != nil {
+		logger.Log.Warn.Printf("Failed to prepare transaction indexes: %v", err)
+	}
+
+	// Version numbering index for the audit log
+	if err := repositories.EnsureAuditIndexes(db.Database); err != nil {
+		logger.Log.Warn.Printf("Failed to prepare audit log indexes: %v", err)
+	}
+
+	// Duplicate detection index for ingested bank notifications
+	if err := repositories.EnsureBankNotificationIndexes(db.Database); err != nil {
+		logger.Log.Warn.Printf("Failed to prepare bank notification indexes: %v", err)
+	}
+
+	// One settings document per tag name
+	if err := repositories.EnsureTagIndexes(db.Database); err != nil {
+		logger.Log.Warn.Printf("Failed to prepare tag indexes: %v", err)
+	}
+
+	// Attachments are listed per transaction and matched to stored objects by key
+	if err := repositories.EnsureAttachmentIndexes(db.Database); err != nil {
+		logger.Log.Warn.Printf("Failed to prepare attachment indexes: %v", err)
+	}
+
+	// Initialize file storage
+	storage, err := utils.NewStorage(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize %s storage: %v", cfg.Storage.Backend, err)
+	}
+
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(db.Database)
 	tokenRepo := repositories.NewVerificationTokenRepository(db.Database)
@@ -44,14 +82,43 @@ func main() {
 	transactionRepo := repositories.NewTransactionRepository(db.Database)
 	categoryRepo := repositories.NewCategoryRepository(db.Database)
 	budgetRepo := repositories.NewBudgetRepository(db.Database)
+	goalRepo := repositories.NewGoalRepository(db.Database)
+	loanRepo := repositories.NewLoanRepository(db.Database)
+	contactRepo := repositories.NewContactRepository(db.Database)
+	sharedExpenseRepo := repositories.NewSharedExpenseRepository(db.Database)
+	workspaceRepo := repositories.NewWorkspaceRepository(db.Database)
+	securityRepo := repositories.NewSecurityRepository(db.Database)
+	investmentRepo := repositories.NewInvestmentRepository(db.Database)
+	auditRepo := repositories.NewAuditRepository(db.Database)
+	reconciliationRepo := repositories.NewReconciliationRepository(db.Database)
+	bankNotificationRepo := repositories.NewBankNotificationRepository(db.Database)
+	payeeRepo := repositories.NewPayeeRepository(db.Database)
+	installmentRepo := repositories.NewInstallmentRepository(db.Database)
+	templateRepo := repositories.NewTemplateRepository(db.Database)
+	tagRepo := repositories.NewTagRepository(db.Database)
+	attachmentRepo := repositories.NewAttachmentRepository(db.Database)
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, tokenRepo, cfg)
-	accountService := services.NewAccountService(accountRepo)
-	transactionService := services.NewTransactionService(transactionRepo, accountRepo, categoryRepo)
-	categoryService := services.NewCategoryService(categoryRepo, transactionRepo)
-	budgetService := services.NewBudgetService(budgetRepo, transactionRepo, categoryRepo)
-	reportService := services.NewReportService(transactionRepo, categoryRepo)
+	auditService := services.NewAuditService(auditRepo)
+	accountService := services.NewAccountService(accountRepo, transactionRepo, investmentRepo, securityRepo, auditService)
+	attachmentService := services.NewAttachmentService(attachmentRepo, transactionRepo, storage, cfg)
+	transactionService := services.NewTransactionService(transactionRepo, accountRepo, categoryRepo, payeeRepo, auditService, attachmentService)
+	categoryService := services.NewCategoryService(categoryRepo, transactionRepo, budgetRepo, auditService)
+	authService := services.NewAuthService(userRepo, tokenRepo, storage, categoryService, cfg)
+	budgetService := services.NewBudgetService(budgetRepo, transactionRepo, categoryRepo, auditService)
+	reportService := services.NewReportService(transactionRepo, categoryRepo, payeeRepo, tagRepo)
+	goalService := services.NewGoalService(goalRepo, accountRepo, transactionService, reportService)
+	loanService := services.NewLoanService(loanRepo, accountRepo, transactionService)
+	contactService := services.NewContactService(contactRepo, sharedExpenseRepo)
+	sharedExpenseService := services.NewSharedExpenseService(sharedExpenseRepo, contactRepo, transactionRepo, transactionService)
+	workspaceService := services.NewWorkspaceService(workspaceRepo, userRepo, accountRepo, categoryRepo, budgetRepo, transactionRepo, cfg)
+	investmentService := services.NewInvestmentService(investmentRepo, securityRepo, accountRepo, transactionService)
+	reconciliationService := services.NewReconciliationService(reconciliationRepo, transactionRepo, accountRepo, auditService)
+	bankNotificationService := services.NewBankNotificationService(bankNotificationRepo, accountRepo, categoryRepo, transactionService)
+	payeeService := services.NewPayeeService(payeeRepo, transactionRepo, categoryRepo, auditService)
+	installmentService := services.NewInstallmentService(installmentRepo, accountRepo, categoryRepo, transactionService)
+	templateService := services.NewTemplateService(templateRepo, accountRepo, categoryRepo, transactionRepo, transactionService)
+	tagService := services.NewTagService(tagRepo, transactionRepo, auditService)
 
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler()
@@ -61,12 +128,21 @@ func main() {
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	budgetHandler := handlers.NewBudgetHandler(budgetService)
 	reportHandler := handlers.NewReportHandler(reportService)
-	
-	// Initialize upload handler
-	uploadHandler, err := handlers.NewUploadHandler(cfg)
-	if err != nil {
-		log.Fatalf("Failed to initialize upload handler: %v", err)
-	}
+	goalHandler := handlers.NewGoalHandler(goalService)
+	loanHandler := handlers.NewLoanHandler(loanService)
+	contactHandler := handlers.NewContactHandler(contactService)
+	sharedExpenseHandler := handlers.NewSharedExpenseHandler(sharedExpenseService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
+	investmentHandler := handlers.NewInvestmentHandler(investmentService)
+	reconciliationHandler := handlers.NewReconciliationHandler(reconciliationService)
+	bankNotificationHandler := handlers.NewBankNotificationHandler(bankNotificationService)
+	payeeHandler := handlers.NewPayeeHandler(payeeService)
+	installmentHandler := handlers.NewInstallmentHandler(installmentService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, cfg)
+	uploadHandler := handlers.NewUploadHandler(cfg, storage, attachmentService, authService)
+	fileHandler := handlers.NewFileHandler(storage)
 
 	// Setup router
 	router := handlers.NewRouter(
@@ -79,8 +155,32 @@ func main() {
 		budgetHandler,
 		reportHandler,
 		uploadHandler,
+		goalHandler,
+		loanHandler,
+		contactHandler,
+		sharedExpenseHandler,
+		workspaceHandler,
+		investmentHandler,
+		reconciliationHandler,
+		bankNotificationHandler,
+		payeeHandler,
+		installmentHandler,
+		templateHandler,
+		tagHandler,
+		attachmentHandler,
+		fileHandler,
 	)
 
+	// Permanently remove transactions that outlived the trash retention period
+	trashRetention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+	go transactionService.RunTrashPurge(trashRetention, cfg.Trash.PurgeInterval)
+
+	// Post credit card installments as their statement dates pass
+	go installmentService.RunInstallmentCharges(cfg.Installment.ChargeInterval)
+
+	// Delete stored files that no transaction refers to any more
+	go attachmentService.RunGarbageCollector(cfg.Attachment.GracePeriod, cfg.Attachment.GCInterval)
+
 	engine := router.Setup()
 
 	// Start server
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +21,9 @@ type Config struct {
 	Storage     StorageConfig
 	R2          R2Config
 	Logging     LoggingConfig
+	Trash       TrashConfig
+	Installment InstallmentConfig
+	Attachment  AttachmentConfig
 }
 
 // ServerConfig holds server configuration
@@ -64,10 +68,24 @@ type CORSConfig struct {
 	AllowedOrigins []string
 }
 
+// Storage backends
+const (
+	StorageBackendR2     = "r2"
+	StorageBackendLocal  = "local"
+	StorageBackendMemory = "memory"
+)
+
 // StorageConfig holds storage configuration
 type StorageConfig struct {
-	MaxUploadSize    int64
-	AllowedFileTypes []string
+	Backend             string        // r2, local or memory; local when R2 is not configured
+	LocalDir            string        // Directory the local backend keeps files in
+	FileBaseURL         string        // Where the API serves files of the local and memory backends
+	SignedURLExpiry     time.Duration // How long signed download and upload URLs stay valid
+	MaxUploadSize       int64
+	MaxDirectUploadSize int64 // Limit for files uploaded straight to storage through a signed URL
+	MaxImageDimension   int   // Longest side uploaded images are scaled down to
+	MaxImagePixels      int   // Images declaring more pixels are rejected before they are decoded
+	AllowedFileTypes    []string
 }
 
 // R2Config holds Cloudflare R2 configuration
@@ -85,6 +103,23 @@ type LoggingConfig struct {
 	Level string
 }
 
+// TrashConfig holds configuration for deleted transactions
+type TrashConfig struct {
+	RetentionDays int64         // Days a deleted transaction stays restorable
+	PurgeInterval time.Duration // How often expired transactions are purged
+}
+
+// InstallmentConfig holds configuration for credit card installment plans
+type InstallmentConfig struct {
+	ChargeInterval time.Duration // How often due installments are posted to their accounts
+}
+
+// AttachmentConfig holds configuration for transaction attachments
+type AttachmentConfig struct {
+	GCInterval  time.Duration // How often unreferenced attachments are deleted
+	GracePeriod time.Duration // How long an upload may wait for its transaction before it counts as unreferenced
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if exists
@@ -122,8 +157,15 @@ func Load() (*Config, error) {
 			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"*"}),
 		},
 		Storage: StorageConfig{
-			MaxUploadSize:    getEnvAsInt64("MAX_UPLOAD_SIZE", 5242880), // 5MB
-			AllowedFileTypes: getEnvAsSlice("ALLOWED_FILE_TYPES", []string{"image/jpeg", "image/png"}),
+			Backend:             getEnv("STORAGE_BACKEND", defaultStorageBackend()),
+			LocalDir:            getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			FileBaseURL:         getEnv("STORAGE_FILE_BASE_URL", ""),
+			SignedURLExpiry:     getEnvAsDuration("SIGNED_URL_EXPIRY", 15*time.Minute),
+			MaxUploadSize:       getEnvAsInt64("MAX_UPLOAD_SIZE", 5242880),         // 5MB
+			MaxDirectUploadSize: getEnvAsInt64("MAX_DIRECT_UPLOAD_SIZE", 26214400), // 25MB
+			MaxImageDimension:   int(getEnvAsInt64("IMAGE_MAX_DIMENSION", 2560)),
+			MaxImagePixels:      int(getEnvAsInt64("IMAGE_MAX_PIXELS", 40000000)), // 40MP
+			AllowedFileTypes:    getEnvAsSlice("ALLOWED_FILE_TYPES", []string{"image/jpeg", "image/png", "image/webp", "application/pdf"}),
 		},
 		R2: R2Config{
 			AccountID:       getEnv("CF_R2_ACCOUNT_ID", ""),
@@ -136,6 +178,21 @@ func Load() (*Config, error) {
 		Logging: LoggingConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
+		Trash: TrashConfig{
+			RetentionDays: getEnvAsInt64("TRASH_RETENTION_DAYS", 30),
+			PurgeInterval: getEnvAsDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		},
+		Installment: InstallmentConfig{
+			ChargeInterval: getEnvAsDuration("INSTALLMENT_CHARGE_INTERVAL", time.Hour),
+		},
+		Attachment: AttachmentConfig{
+			GCInterval:  getEnvAsDuration("ATTACHMENT_GC_INTERVAL", 6*time.Hour),
+			GracePeriod: getEnvAsDuration("ATTACHMENT_GRACE_PERIOD", 24*time.Hour),
+		},
+	}
+
+	if cfg.Storage.FileBaseURL == "" {
+		cfg.Storage.FileBaseURL = fmt.Sprintf("http://localhost:%s/api/%s/files", cfg.Server.Port, cfg.Server.APIVersion)
 	}
 
 	// Validate required fields
@@ -154,11 +211,28 @@ func (c *Config) Validate() error {
 	if c.Database.Database == "" {
 		return fmt.Errorf("MONGODB_DATABASE is required")
 	}
+	switch c.Storage.Backend {
+	case StorageBackendR2:
+		if c.R2.Endpoint == "" || c.R2.BucketName == "" {
+			return fmt.Errorf("CF_R2_ENDPOINT and CF_R2_BUCKET are required for the r2 storage backend")
+		}
+	case StorageBackendLocal, StorageBackendMemory:
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be r2, local or memory")
+	}
 	return nil
 }
 
 // Helper functions
 
+// defaultStorageBackend keeps files in R2 when a bucket is configured and on local disk otherwise
+func defaultStorageBackend() string {
+	if os.Getenv("CF_R2_BUCKET") != "" {
+		return StorageBackendR2
+	}
+	return StorageBackendLocal
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -174,6 +248,14 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if value, err := time.ParseDuration(valueStr); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	valueStr := getEnv(key, "")
 	if valueStr == "" {
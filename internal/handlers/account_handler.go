@@ -35,8 +35,10 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	}
 
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	account, err := h.service.CreateAccount(userID, req)
+	account, err := h.service.CreateAccount(workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create account", err.Error())
 		return
@@ -49,10 +51,10 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 func (h *AccountHandler) GetAccount(c *gin.Context) {
 	id := c.Param("id")
 
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	account, err := h.service.GetAccount(id, userID)
+	account, err := h.service.GetAccount(id, workspaceID)
 	if err != nil {
 		response.NotFoundResponse(c, "Account")
 		return
@@ -69,10 +71,10 @@ func (h *AccountHandler) GetAllAccounts(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	result, err := h.service.GetAllAccounts(userID, pagination)
+	result, err := h.service.GetAllAccounts(workspaceID, pagination)
 	if err != nil {
 		response.InternalErrorResponse(c, err)
 		return
@@ -91,10 +93,17 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	account, err := h.service.UpdateAccount(id, userID, req)
+	account, err := h.service.UpdateAccount(id, workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update account", err.Error())
 		return
@@ -107,10 +116,17 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 func (h *AccountHandler) DeleteAccount(c *gin.Context) {
 	id := c.Param("id")
 
-	userIDStr, _ := c.Get("user_id")
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	if err := h.service.DeleteAccount(id, userID); err != nil {
+	if err := h.service.DeleteAccount(id, workspaceID, userID); err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete account", err.Error())
 		return
 	}
@@ -120,10 +136,10 @@ func (h *AccountHandler) DeleteAccount(c *gin.Context) {
 
 // GetAccountSummary handles GET /accounts/summary
 func (h *AccountHandler) GetAccountSummary(c *gin.Context) {
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	summary, err := h.service.GetAccountSummary(userID)
+	summary, err := h.service.GetAccountSummary(workspaceID)
 	if err != nil {
 		response.InternalErrorResponse(c, err)
 		return
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/config"
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/internal/utils"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentHandler handles transaction attachment HTTP requests
+type AttachmentHandler struct {
+	service *services.AttachmentService
+	config  *config.Config
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(service *services.AttachmentService, cfg *config.Config) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: service,
+		config:  cfg,
+	}
+}
+
+// UploadAttachment handles POST /transactions/:id/attachments
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	transactionID := c.Param("id")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "No file uploaded", err.Error())
+		return
+	}
+	defer file.Close()
+
+	content, contentType, err := utils.ReadUpload(file, header, h.config.Storage.MaxUploadSize, h.config.Storage.AllowedFileTypes)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Invalid file", err.Error())
+		return
+	}
+
+	attachment, err := h.service.Upload(workspaceID, userID, &transactionID, header.Filename, contentType, content)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to upload attachment", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Attachment uploaded successfully", attachment)
+}
+
+// PresignAttachment handles POST /transactions/:id/attachments/presign
+func (h *AttachmentHandler) PresignAttachment(c *gin.Context) {
+	transactionID := c.Param("id")
+
+	var req models.PresignAttachmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := utils.ValidateUpload(req.Size, req.ContentType, h.config.Storage.MaxDirectUploadSize, h.config.Storage.AllowedFileTypes); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Invalid file", err.Error())
+		return
+	}
+
+	upload, err := h.service.PresignUpload(workspaceID, userID, &transactionID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to prepare upload", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Upload URL created successfully", upload)
+}
+
+// GetAttachments handles GET /transactions/:id/attachments
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	transactionID := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	attachments, err := h.service.GetAttachments(workspaceID, transactionID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Attachments retrieved successfully", attachments)
+}
+
+// DeleteAttachment handles DELETE /transactions/:id/attachments/:attachmentId
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	transactionID := c.Param("id")
+	attachmentID := c.Param("attachmentId")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteAttachment(attachmentID, workspaceID, transactionID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete attachment", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Attachment deleted successfully", nil)
+}
@@ -234,6 +234,38 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", profile)
 }
 
+// UpdateProfile updates current user profile
+// PUT /api/v1/auth/profile
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	// Get user ID from JWT middleware
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err)
+		return
+	}
+
+	profile, err := h.authService.UpdateProfile(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		switch err {
+		case services.ErrUserNotFound:
+			response.NotFoundResponse(c, "User")
+		case services.ErrEmptyFullName, services.ErrInvalidTimezone, services.ErrInvalidAvatarURL:
+			response.BadRequestResponse(c, err.Error())
+		default:
+			response.InternalErrorResponse(c, err)
+		}
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Profile updated successfully", profile)
+}
+
 // ChangePassword changes user password
 // POST /api/v1/auth/change-password
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BankNotificationHandler handles bank SMS and push-notification HTTP requests
+type BankNotificationHandler struct {
+	service *services.BankNotificationService
+}
+
+// NewBankNotificationHandler creates a new bank notification handler
+func NewBankNotificationHandler(service *services.BankNotificationService) *BankNotificationHandler {
+	return &BankNotificationHandler{service: service}
+}
+
+// IngestNotification handles POST /bank-notifications
+func (h *BankNotificationHandler) IngestNotification(c *gin.Context) {
+	var req models.IngestBankNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	notification, err := h.service.IngestNotification(workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to ingest notification", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Notification ingested successfully", notification)
+}
+
+// GetNotifications handles GET /bank-notifications
+func (h *BankNotificationHandler) GetNotifications(c *gin.Context) {
+	var query models.BankNotificationFilterQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	notifications, err := h.service.GetNotifications(workspaceID, query)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Notifications retrieved successfully", notifications)
+}
+
+// GetSupportedBanks handles GET /bank-notifications/banks
+func (h *BankNotificationHandler) GetSupportedBanks(c *gin.Context) {
+	response.SuccessResponse(c, http.StatusOK, "Supported banks retrieved successfully", h.service.GetSupportedBanks())
+}
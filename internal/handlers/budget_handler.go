@@ -34,8 +34,10 @@ func (h *BudgetHandler) CreateOrUpdateBudget(c *gin.Context) {
 	}
 
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	budget, err := h.service.CreateOrUpdateBudget(userID, req)
+	budget, err := h.service.CreateOrUpdateBudget(workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create or update budget", err.Error())
 		return
@@ -48,15 +50,15 @@ func (h *BudgetHandler) CreateOrUpdateBudget(c *gin.Context) {
 func (h *BudgetHandler) GetBudget(c *gin.Context) {
 	id := c.Param("id")
 
-	userIDStr, exists := c.Get("user_id")
+	workspaceIDStr, exists := c.Get("workspace_id")
 	if !exists {
-		response.UnauthorizedResponse(c, "User not authenticated")
+		response.UnauthorizedResponse(c, "Workspace not selected")
 		return
 	}
 
-	userID := userIDStr.(string)
+	workspaceID := workspaceIDStr.(string)
 
-	budget, err := h.service.GetBudget(id, userID)
+	budget, err := h.service.GetBudget(id, workspaceID)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusNotFound, "Budget not found", err.Error())
 		return
@@ -73,15 +75,15 @@ func (h *BudgetHandler) GetBudgetsByMonth(c *gin.Context) {
 		return
 	}
 
-	userIDStr, exists := c.Get("user_id")
+	workspaceIDStr, exists := c.Get("workspace_id")
 	if !exists {
-		response.UnauthorizedResponse(c, "User not authenticated")
+		response.UnauthorizedResponse(c, "Workspace not selected")
 		return
 	}
 
-	userID := userIDStr.(string)
+	workspaceID := workspaceIDStr.(string)
 
-	budgets, err := h.service.GetBudgetsByMonth(userID, month)
+	budgets, err := h.service.GetBudgetsByMonth(workspaceID, month)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve budgets", err.Error())
 		return
@@ -107,8 +109,15 @@ func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
 	}
 
 	userID := userIDStr.(string)
+	workspaceIDStr, exists := c.Get("workspace_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "Workspace not selected")
+		return
+	}
 
-	budget, err := h.service.UpdateBudget(id, userID, req)
+	workspaceID := workspaceIDStr.(string)
+
+	budget, err := h.service.UpdateBudget(id, workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update budget", err.Error())
 		return
@@ -128,8 +137,15 @@ func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
 	}
 
 	userID := userIDStr.(string)
+	workspaceIDStr, exists := c.Get("workspace_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "Workspace not selected")
+		return
+	}
+
+	workspaceID := workspaceIDStr.(string)
 
-	if err := h.service.DeleteBudget(id, userID); err != nil {
+	if err := h.service.DeleteBudget(id, workspaceID, userID); err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete budget", err.Error())
 		return
 	}
@@ -34,8 +34,10 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	}
 
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	category, err := h.service.CreateCategory(userID, req)
+	category, err := h.service.CreateCategory(workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create category", err.Error())
 		return
@@ -48,10 +50,10 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 func (h *CategoryHandler) GetCategory(c *gin.Context) {
 	id := c.Param("id")
 
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	category, err := h.service.GetCategory(id, userID)
+	category, err := h.service.GetCategory(id, workspaceID)
 	if err != nil {
 		response.NotFoundResponse(c, "Category")
 		return
@@ -62,8 +64,8 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 
 // GetAllCategories handles GET /categories
 func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
 	// Check query parameters
 	categoryType := c.Query("type")
@@ -76,16 +78,16 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 	// Handle different filtering options
 	if filter == "parent" {
 		// Get only parent categories (no parent_id)
-		categories, err = h.service.GetParentCategories(userID)
+		categories, err = h.service.GetParentCategories(workspaceID)
 	} else if filter == "children" && parentID != "" {
 		// Get children of specific parent
-		categories, err = h.service.GetChildCategories(userID, parentID)
+		categories, err = h.service.GetChildCategories(workspaceID, parentID)
 	} else if categoryType != "" {
 		// Filter by type
-		categories, err = h.service.GetCategoriesByType(userID, categoryType)
+		categories, err = h.service.GetCategoriesByType(workspaceID, categoryType)
 	} else {
 		// Get all categories
-		categories, err = h.service.GetAllCategories(userID)
+		categories, err = h.service.GetAllCategories(workspaceID)
 	}
 
 	if err != nil {
@@ -106,10 +108,17 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	category, err := h.service.UpdateCategory(id, userID, req)
+	category, err := h.service.UpdateCategory(id, workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update category", err.Error())
 		return
@@ -119,13 +128,33 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 }
 
 // DeleteCategory handles DELETE /categories/:id
+// Supports ?reassign_to=<category id> to move what uses the category there first
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	id := c.Param("id")
 
-	userIDStr, _ := c.Get("user_id")
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	// With reassign_to, a category in use is merged into that category instead of being refused
+	if reassignTo := c.Query("reassign_to"); reassignTo != "" {
+		result, err := h.service.MergeCategory(id, reassignTo, workspaceID, userID)
+		if err != nil {
+			response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete category", err.Error())
+			return
+		}
+
+		response.SuccessResponse(c, http.StatusOK, "Category deleted successfully", result)
+		return
+	}
 
-	if err := h.service.DeleteCategory(id, userID); err != nil {
+	if err := h.service.DeleteCategory(id, workspaceID, userID); err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete category", err.Error())
 		return
 	}
@@ -133,14 +162,38 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusOK, "Category deleted successfully", nil)
 }
 
+// MergeCategory handles POST /categories/:id/merge-into/:target
+func (h *CategoryHandler) MergeCategory(c *gin.Context) {
+	id := c.Param("id")
+	targetID := c.Param("target")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.MergeCategory(id, targetID, workspaceID, userID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to merge category", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Category merged successfully", result)
+}
+
 // CheckCategoryUsage handles GET /categories/:id/usage
 func (h *CategoryHandler) CheckCategoryUsage(c *gin.Context) {
 	id := c.Param("id")
 
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	usage, err := h.service.IsCategoryInUse(id, userID)
+	usage, err := h.service.IsCategoryInUse(id, workspaceID)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to check category usage", err.Error())
 		return
@@ -148,3 +201,60 @@ func (h *CategoryHandler) CheckCategoryUsage(c *gin.Context) {
 
 	response.SuccessResponse(c, http.StatusOK, "Category usage retrieved successfully", usage)
 }
+
+// GetCategoryTemplates handles GET /categories/templates
+func (h *CategoryHandler) GetCategoryTemplates(c *gin.Context) {
+	response.SuccessResponse(c, http.StatusOK, "Category templates retrieved successfully", h.service.GetCategoryTemplates())
+}
+
+// ImportCategoryTemplate handles POST /categories/templates/:locale/import
+func (h *CategoryHandler) ImportCategoryTemplate(c *gin.Context) {
+	locale := c.Param("locale")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.ImportTemplate(workspaceID, userID, locale)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to import category template", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Category template imported successfully", result)
+}
+
+// ResetCategories handles POST /categories/reset
+func (h *CategoryHandler) ResetCategories(c *gin.Context) {
+	var req models.ResetCategoriesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationErrorResponse(c, err.Error())
+			return
+		}
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.ResetToDefaults(workspaceID, userID, req.Locale)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to reset categories", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Categories reset to defaults successfully", result)
+}
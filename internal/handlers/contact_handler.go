@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContactHandler handles contact and contact group HTTP requests
+type ContactHandler struct {
+	service *services.ContactService
+}
+
+// NewContactHandler creates a new contact handler
+func NewContactHandler(service *services.ContactService) *ContactHandler {
+	return &ContactHandler{service: service}
+}
+
+// CreateContact handles POST /contacts
+func (h *ContactHandler) CreateContact(c *gin.Context) {
+	var req models.CreateContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	contact, err := h.service.CreateContact(workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create contact", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Contact created successfully", contact)
+}
+
+// GetAllContacts handles GET /contacts
+func (h *ContactHandler) GetAllContacts(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	contacts, err := h.service.GetAllContacts(workspaceID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contacts retrieved successfully", contacts)
+}
+
+// GetContact handles GET /contacts/:id
+func (h *ContactHandler) GetContact(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	contact, err := h.service.GetContact(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Contact")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contact retrieved successfully", contact)
+}
+
+// UpdateContact handles PUT /contacts/:id
+func (h *ContactHandler) UpdateContact(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	contact, err := h.service.UpdateContact(id, workspaceID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update contact", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contact updated successfully", contact)
+}
+
+// DeleteContact handles DELETE /contacts/:id
+func (h *ContactHandler) DeleteContact(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteContact(id, workspaceID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete contact", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contact deleted successfully", nil)
+}
+
+// CreateGroup handles POST /contact-groups
+func (h *ContactHandler) CreateGroup(c *gin.Context) {
+	var req models.CreateContactGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	group, err := h.service.CreateGroup(workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create contact group", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Contact group created successfully", group)
+}
+
+// GetAllGroups handles GET /contact-groups
+func (h *ContactHandler) GetAllGroups(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	groups, err := h.service.GetAllGroups(workspaceID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contact groups retrieved successfully", groups)
+}
+
+// GetGroup handles GET /contact-groups/:id
+func (h *ContactHandler) GetGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	group, err := h.service.GetGroup(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Contact group")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contact group retrieved successfully", group)
+}
+
+// UpdateGroup handles PUT /contact-groups/:id
+func (h *ContactHandler) UpdateGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateContactGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	group, err := h.service.UpdateGroup(id, workspaceID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update contact group", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contact group updated successfully", group)
+}
+
+// DeleteGroup handles DELETE /contact-groups/:id
+func (h *ContactHandler) DeleteGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteGroup(id, workspaceID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete contact group", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contact group deleted successfully", nil)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"finance-hub-api/internal/utils"
+	"finance-hub-api/pkg/response"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inlineFileTypes are the content types files are served with for display in the browser
+var inlineFileTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"image/gif":       true,
+	"application/pdf": true,
+}
+
+// FileHandler serves files of the local and memory storage backends through signed URLs
+// Requests are authenticated by the URL signature instead of a token, so links work in <img> tags.
+// With R2 files are served by the bucket itself and every request here is answered with 404.
+type FileHandler struct {
+	storage  utils.Storage
+	verifier utils.SignedURLVerifier
+}
+
+// NewFileHandler creates a new file handler
+func NewFileHandler(storage utils.Storage) *FileHandler {
+	verifier, _ := storage.(utils.SignedURLVerifier)
+	return &FileHandler{
+		storage:  storage,
+		verifier: verifier,
+	}
+}
+
+// GetFile handles GET /files/*key
+func (h *FileHandler) GetFile(c *gin.Context) {
+	if h.verifier == nil {
+		response.NotFoundResponse(c, "File")
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := h.verifier.VerifySignature(http.MethodGet, key, c.Request.URL.Query(), "", 0); err != nil {
+		response.ErrorResponse(c, http.StatusForbidden, "Access denied", err.Error())
+		return
+	}
+
+	content, contentType, err := h.storage.GetObject(c.Request.Context(), key)
+	if errors.Is(err, utils.ErrObjectNotFound) {
+		response.NotFoundResponse(c, "File")
+		return
+	}
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	// Only images and PDFs are shown inline; anything else is downloaded as opaque bytes, so a stored
+	// file can never be rendered as a page of the API origin
+	disposition := "inline"
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || !inlineFileTypes[mediaType] {
+		contentType = "application/octet-stream"
+		disposition = "attachment"
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": path.Base(key)}))
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// PutFile handles PUT /files/*key, the target of presigned direct uploads
+func (h *FileHandler) PutFile(c *gin.Context) {
+	if h.verifier == nil {
+		response.NotFoundResponse(c, "File")
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	contentType := c.GetHeader("Content-Type")
+	size := c.Request.ContentLength
+	if err := h.verifier.VerifySignature(http.MethodPut, key, c.Request.URL.Query(), contentType, size); err != nil {
+		response.ErrorResponse(c, http.StatusForbidden, "Access denied", err.Error())
+		return
+	}
+
+	content, err := io.ReadAll(io.LimitReader(c.Request.Body, size+1))
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to read file", err.Error())
+		return
+	}
+	if int64(len(content)) != size {
+		response.ErrorResponse(c, http.StatusBadRequest, "Invalid file", "body does not match Content-Length")
+		return
+	}
+
+	if _, err := h.storage.PutObject(c.Request.Context(), key, content, contentType); err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GoalHandler handles savings goal HTTP requests
+type GoalHandler struct {
+	service *services.GoalService
+}
+
+// NewGoalHandler creates a new goal handler
+func NewGoalHandler(service *services.GoalService) *GoalHandler {
+	return &GoalHandler{service: service}
+}
+
+// CreateGoal handles POST /goals
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	var req models.CreateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	goal, err := h.service.CreateGoal(workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create goal", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Goal created successfully", goal)
+}
+
+// GetAllGoals handles GET /goals?status=active|completed|archived
+func (h *GoalHandler) GetAllGoals(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	goals, err := h.service.GetAllGoals(workspaceID, c.Query("status"))
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Goals retrieved successfully", goals)
+}
+
+// GetGoal handles GET /goals/:id
+func (h *GoalHandler) GetGoal(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	goal, err := h.service.GetGoal(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Goal")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Goal retrieved successfully", goal)
+}
+
+// UpdateGoal handles PUT /goals/:id
+func (h *GoalHandler) UpdateGoal(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	goal, err := h.service.UpdateGoal(id, workspaceID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update goal", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Goal updated successfully", goal)
+}
+
+// DeleteGoal handles DELETE /goals/:id
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteGoal(id, workspaceID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete goal", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Goal deleted successfully", nil)
+}
+
+// AddContribution handles POST /goals/:id/contributions
+func (h *GoalHandler) AddContribution(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.CreateGoalContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	contribution, err := h.service.AddContribution(id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to add contribution", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Contribution added successfully", contribution)
+}
+
+// GetContributions handles GET /goals/:id/contributions
+func (h *GoalHandler) GetContributions(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	contributions, err := h.service.GetContributions(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Goal")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contributions retrieved successfully", contributions)
+}
+
+// DeleteContribution handles DELETE /goals/:id/contributions/:contributionId
+func (h *GoalHandler) DeleteContribution(c *gin.Context) {
+	id := c.Param("id")
+	contributionID := c.Param("contributionId")
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteContribution(id, contributionID, workspaceID, userID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete contribution", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Contribution deleted successfully", nil)
+}
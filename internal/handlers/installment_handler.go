@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InstallmentHandler handles credit card installment plan HTTP requests
+type InstallmentHandler struct {
+	service *services.InstallmentService
+}
+
+// NewInstallmentHandler creates a new installment handler
+func NewInstallmentHandler(service *services.InstallmentService) *InstallmentHandler {
+	return &InstallmentHandler{service: service}
+}
+
+// CreatePlan handles POST /accounts/:id/installments
+func (h *InstallmentHandler) CreatePlan(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.CreateInstallmentPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	plan, err := h.service.CreatePlan(id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create installment plan", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Installment plan created successfully", plan)
+}
+
+// GetPlans handles GET /accounts/:id/installments
+func (h *InstallmentHandler) GetPlans(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	plans, err := h.service.GetPlans(id, workspaceID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve installment plans", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Installment plans retrieved successfully", plans)
+}
+
+// GetPlan handles GET /accounts/:id/installments/:installmentId
+func (h *InstallmentHandler) GetPlan(c *gin.Context) {
+	id := c.Param("id")
+	installmentID := c.Param("installmentId")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	plan, err := h.service.GetPlan(installmentID, id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Installment plan")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Installment plan retrieved successfully", plan)
+}
+
+// PayOff handles POST /accounts/:id/installments/:installmentId/payoff
+func (h *InstallmentHandler) PayOff(c *gin.Context) {
+	id := c.Param("id")
+	installmentID := c.Param("installmentId")
+
+	var req models.PayoffInstallmentPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	plan, err := h.service.PayOff(installmentID, id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to pay off installment plan", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Installment plan paid off successfully", plan)
+}
+
+// DeletePlan handles DELETE /accounts/:id/installments/:installmentId
+func (h *InstallmentHandler) DeletePlan(c *gin.Context) {
+	id := c.Param("id")
+	installmentID := c.Param("installmentId")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeletePlan(installmentID, id, workspaceID, userID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete installment plan", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Installment plan deleted successfully", nil)
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvestmentHandler handles security, price and investment account HTTP requests
+type InvestmentHandler struct {
+	service *services.InvestmentService
+}
+
+// NewInvestmentHandler creates a new investment handler
+func NewInvestmentHandler(service *services.InvestmentService) *InvestmentHandler {
+	return &InvestmentHandler{service: service}
+}
+
+// CreateSecurity handles POST /securities
+func (h *InvestmentHandler) CreateSecurity(c *gin.Context) {
+	var req models.CreateSecurityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	security, err := h.service.CreateSecurity(workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create security", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Security created successfully", security)
+}
+
+// GetSecurities handles GET /securities
+func (h *InvestmentHandler) GetSecurities(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	securities, err := h.service.GetSecurities(workspaceID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Securities retrieved successfully", securities)
+}
+
+// GetSecurity handles GET /securities/:id
+func (h *InvestmentHandler) GetSecurity(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	security, err := h.service.GetSecurity(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Security")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Security retrieved successfully", security)
+}
+
+// UpdateSecurity handles PUT /securities/:id
+func (h *InvestmentHandler) UpdateSecurity(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateSecurityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	security, err := h.service.UpdateSecurity(id, workspaceID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update security", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Security updated successfully", security)
+}
+
+// DeleteSecurity handles DELETE /securities/:id
+func (h *InvestmentHandler) DeleteSecurity(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteSecurity(id, workspaceID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete security", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Security deleted successfully", nil)
+}
+
+// UpdatePrice handles POST /securities/:id/prices
+func (h *InvestmentHandler) UpdatePrice(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateSecurityPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	security, err := h.service.UpdatePrice(id, workspaceID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update price", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Price updated successfully", security)
+}
+
+// GetPrices handles GET /securities/:id/prices
+func (h *InvestmentHandler) GetPrices(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	prices, err := h.service.GetPrices(id, workspaceID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve prices", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Prices retrieved successfully", prices)
+}
+
+// ImportPrices handles POST /securities/prices/import
+func (h *InvestmentHandler) ImportPrices(c *gin.Context) {
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "No file uploaded", err.Error())
+		return
+	}
+	defer file.Close()
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.ImportPrices(workspaceID, file)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to import prices", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Prices imported successfully", result)
+}
+
+// GetPortfolio handles GET /accounts/:id/holdings
+func (h *InvestmentHandler) GetPortfolio(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	portfolio, err := h.service.GetPortfolio(id, workspaceID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve holdings", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Holdings retrieved successfully", portfolio)
+}
+
+// RecordTransaction handles POST /accounts/:id/investment-transactions
+func (h *InvestmentHandler) RecordTransaction(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.CreateInvestmentTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	transaction, err := h.service.RecordTransaction(id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to record investment transaction", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Investment transaction recorded successfully", transaction)
+}
+
+// GetTransactions handles GET /accounts/:id/investment-transactions
+func (h *InvestmentHandler) GetTransactions(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	transactions, err := h.service.GetTransactions(id, workspaceID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve investment transactions", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Investment transactions retrieved successfully", transactions)
+}
+
+// DeleteTransaction handles DELETE /accounts/:id/investment-transactions/:transactionId
+func (h *InvestmentHandler) DeleteTransaction(c *gin.Context) {
+	id := c.Param("id")
+	transactionID := c.Param("transactionId")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteTransaction(id, transactionID, workspaceID, userID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete investment transaction", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Investment transaction deleted successfully", nil)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoanHandler handles loan and debt HTTP requests
+type LoanHandler struct {
+	service *services.LoanService
+}
+
+// NewLoanHandler creates a new loan handler
+func NewLoanHandler(service *services.LoanService) *LoanHandler {
+	return &LoanHandler{service: service}
+}
+
+// GetSchedule handles GET /accounts/:id/amortization
+func (h *LoanHandler) GetSchedule(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	schedule, err := h.service.GetSchedule(id, workspaceID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to build amortization schedule", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Amortization schedule retrieved successfully", schedule)
+}
+
+// RecordPayment handles POST /accounts/:id/loan-payments
+func (h *LoanHandler) RecordPayment(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.CreateLoanPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	payment, err := h.service.RecordPayment(id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to record loan payment", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Loan payment recorded successfully", payment)
+}
+
+// GetPayments handles GET /accounts/:id/loan-payments
+func (h *LoanHandler) GetPayments(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	payments, err := h.service.GetPayments(id, workspaceID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve loan payments", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Loan payments retrieved successfully", payments)
+}
+
+// DeletePayment handles DELETE /accounts/:id/loan-payments/:paymentId
+func (h *LoanHandler) DeletePayment(c *gin.Context) {
+	id := c.Param("id")
+	paymentID := c.Param("paymentId")
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeletePayment(id, paymentID, workspaceID, userID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete loan payment", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Loan payment deleted successfully", nil)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PayeeHandler handles payee-related HTTP requests
+type PayeeHandler struct {
+	service *services.PayeeService
+}
+
+// NewPayeeHandler creates a new payee handler
+func NewPayeeHandler(service *services.PayeeService) *PayeeHandler {
+	return &PayeeHandler{service: service}
+}
+
+// CreatePayee handles POST /payees
+func (h *PayeeHandler) CreatePayee(c *gin.Context) {
+	var req models.CreatePayeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	payee, err := h.service.CreatePayee(workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create payee", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Payee created successfully", payee)
+}
+
+// GetAllPayees handles GET /payees
+func (h *PayeeHandler) GetAllPayees(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	payees, err := h.service.GetAllPayees(workspaceID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Payees retrieved successfully", payees)
+}
+
+// GetPayee handles GET /payees/:id
+func (h *PayeeHandler) GetPayee(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	payee, err := h.service.GetPayee(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Payee")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Payee retrieved successfully", payee)
+}
+
+// UpdatePayee handles PUT /payees/:id
+func (h *PayeeHandler) UpdatePayee(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdatePayeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	payee, err := h.service.UpdatePayee(id, workspaceID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update payee", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Payee updated successfully", payee)
+}
+
+// DeletePayee handles DELETE /payees/:id
+func (h *PayeeHandler) DeletePayee(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeletePayee(id, workspaceID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete payee", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Payee deleted successfully", nil)
+}
+
+// MergePayees handles POST /payees/:id/merge
+func (h *PayeeHandler) MergePayees(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.MergePayeesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.MergePayees(id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to merge payees", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Payees merged successfully", result)
+}
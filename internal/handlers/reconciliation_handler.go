@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReconciliationHandler handles statement reconciliation HTTP requests
+type ReconciliationHandler struct {
+	service *services.ReconciliationService
+}
+
+// NewReconciliationHandler creates a new reconciliation handler
+func NewReconciliationHandler(service *services.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{service: service}
+}
+
+// StartReconciliation handles POST /accounts/:id/reconciliations
+func (h *ReconciliationHandler) StartReconciliation(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.CreateReconciliationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	progress, err := h.service.StartReconciliation(id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to start reconciliation", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Reconciliation started successfully", progress)
+}
+
+// GetReconciliations handles GET /accounts/:id/reconciliations
+func (h *ReconciliationHandler) GetReconciliations(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	reconciliations, err := h.service.GetReconciliations(id, workspaceID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve reconciliations", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Reconciliations retrieved successfully", reconciliations)
+}
+
+// GetReconciliation handles GET /accounts/:id/reconciliations/:reconciliationId
+func (h *ReconciliationHandler) GetReconciliation(c *gin.Context) {
+	id := c.Param("id")
+	reconciliationID := c.Param("reconciliationId")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	progress, err := h.service.GetReconciliation(reconciliationID, id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Reconciliation")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Reconciliation retrieved successfully", progress)
+}
+
+// ClearTransactions handles PUT /accounts/:id/reconciliations/:reconciliationId/transactions
+func (h *ReconciliationHandler) ClearTransactions(c *gin.Context) {
+	id := c.Param("id")
+	reconciliationID := c.Param("reconciliationId")
+
+	var req models.ClearTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	progress, err := h.service.ClearTransactions(reconciliationID, id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update transactions", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Transactions updated successfully", progress)
+}
+
+// FinishReconciliation handles POST /accounts/:id/reconciliations/:reconciliationId/finish
+func (h *ReconciliationHandler) FinishReconciliation(c *gin.Context) {
+	id := c.Param("id")
+	reconciliationID := c.Param("reconciliationId")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	reconciliation, err := h.service.FinishReconciliation(reconciliationID, id, workspaceID, userID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to finish reconciliation", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Reconciliation finished successfully", reconciliation)
+}
+
+// CancelReconciliation handles DELETE /accounts/:id/reconciliations/:reconciliationId
+func (h *ReconciliationHandler) CancelReconciliation(c *gin.Context) {
+	id := c.Param("id")
+	reconciliationID := c.Param("reconciliationId")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.CancelReconciliation(reconciliationID, id, workspaceID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to cancel reconciliation", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Reconciliation cancelled successfully", nil)
+}
@@ -28,12 +28,12 @@ func (h *ReportHandler) GetOverview(c *gin.Context) {
 		return
 	}
 
-	userIDStr, exists := c.Get("user_id")
+	workspaceIDStr, exists := c.Get("workspace_id")
 	if !exists {
-		response.UnauthorizedResponse(c, "User not authenticated")
+		response.UnauthorizedResponse(c, "Workspace not selected")
 		return
 	}
-	userID := userIDStr.(string)
+	workspaceID := workspaceIDStr.(string)
 
 	// Parse dates
 	startDate, err := time.Parse("2006-01-02", query.StartDate)
@@ -51,7 +51,7 @@ func (h *ReportHandler) GetOverview(c *gin.Context) {
 	// Set time to end of day for endDate
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, endDate.Location())
 
-	report, err := h.service.GetOverview(userID, startDate, endDate)
+	report, err := h.service.GetOverview(workspaceID, startDate, endDate)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate overview report", err.Error())
 		return
@@ -68,12 +68,12 @@ func (h *ReportHandler) GetByCategory(c *gin.Context) {
 		return
 	}
 
-	userIDStr, exists := c.Get("user_id")
+	workspaceIDStr, exists := c.Get("workspace_id")
 	if !exists {
-		response.UnauthorizedResponse(c, "User not authenticated")
+		response.UnauthorizedResponse(c, "Workspace not selected")
 		return
 	}
-	userID := userIDStr.(string)
+	workspaceID := workspaceIDStr.(string)
 
 	// Parse dates
 	startDate, err := time.Parse("2006-01-02", query.StartDate)
@@ -90,7 +90,7 @@ func (h *ReportHandler) GetByCategory(c *gin.Context) {
 
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, endDate.Location())
 
-	report, err := h.service.GetByCategory(userID, startDate, endDate)
+	report, err := h.service.GetByCategory(workspaceID, startDate, endDate)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate category report", err.Error())
 		return
@@ -107,12 +107,12 @@ func (h *ReportHandler) GetByMerchant(c *gin.Context) {
 		return
 	}
 
-	userIDStr, exists := c.Get("user_id")
+	workspaceIDStr, exists := c.Get("workspace_id")
 	if !exists {
-		response.UnauthorizedResponse(c, "User not authenticated")
+		response.UnauthorizedResponse(c, "Workspace not selected")
 		return
 	}
-	userID := userIDStr.(string)
+	workspaceID := workspaceIDStr.(string)
 
 	// Parse dates
 	startDate, err := time.Parse("2006-01-02", query.StartDate)
@@ -129,7 +129,7 @@ func (h *ReportHandler) GetByMerchant(c *gin.Context) {
 
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, endDate.Location())
 
-	report, err := h.service.GetByMerchant(userID, startDate, endDate)
+	report, err := h.service.GetByMerchant(workspaceID, startDate, endDate)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate merchant report", err.Error())
 		return
@@ -138,6 +138,45 @@ func (h *ReportHandler) GetByMerchant(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusOK, "Merchant report retrieved successfully", report)
 }
 
+// GetByTag handles GET /reports/by-tag?start_date=YYYY-MM-DD&end_date=YYYY-MM-DD
+func (h *ReportHandler) GetByTag(c *gin.Context) {
+	var query models.DateRangeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, exists := c.Get("workspace_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "Workspace not selected")
+		return
+	}
+	workspaceID := workspaceIDStr.(string)
+
+	// Parse dates
+	startDate, err := time.Parse("2006-01-02", query.StartDate)
+	if err != nil {
+		response.ValidationErrorResponse(c, "Invalid start_date format, expected YYYY-MM-DD")
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", query.EndDate)
+	if err != nil {
+		response.ValidationErrorResponse(c, "Invalid end_date format, expected YYYY-MM-DD")
+		return
+	}
+
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, endDate.Location())
+
+	report, err := h.service.GetByTag(workspaceID, startDate, endDate)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate tag report", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Tag report retrieved successfully", report)
+}
+
 // GetWeeklySpending handles GET /reports/weekly-spending?month=YYYY-MM&category_id=xxx
 func (h *ReportHandler) GetWeeklySpending(c *gin.Context) {
 	month := c.Query("month")
@@ -152,14 +191,14 @@ func (h *ReportHandler) GetWeeklySpending(c *gin.Context) {
 		categoryIDPtr = &categoryID
 	}
 
-	userIDStr, exists := c.Get("user_id")
+	workspaceIDStr, exists := c.Get("workspace_id")
 	if !exists {
-		response.UnauthorizedResponse(c, "User not authenticated")
+		response.UnauthorizedResponse(c, "Workspace not selected")
 		return
 	}
-	userID := userIDStr.(string)
+	workspaceID := workspaceIDStr.(string)
 
-	report, err := h.service.GetWeeklySpending(userID, month, categoryIDPtr)
+	report, err := h.service.GetWeeklySpending(workspaceID, month, categoryIDPtr)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate weekly spending report", err.Error())
 		return
@@ -176,14 +215,14 @@ func (h *ReportHandler) GetWeeklyCashflow(c *gin.Context) {
 		return
 	}
 
-	userIDStr, exists := c.Get("user_id")
+	workspaceIDStr, exists := c.Get("workspace_id")
 	if !exists {
-		response.UnauthorizedResponse(c, "User not authenticated")
+		response.UnauthorizedResponse(c, "Workspace not selected")
 		return
 	}
-	userID := userIDStr.(string)
+	workspaceID := workspaceIDStr.(string)
 
-	report, err := h.service.GetWeeklyCashflow(userID, month)
+	report, err := h.service.GetWeeklyCashflow(workspaceID, month)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate weekly cashflow report", err.Error())
 		return
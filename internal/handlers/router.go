@@ -9,15 +9,29 @@ import (
 
 // Router sets up all routes
 type Router struct {
-	cfg                *config.Config
-	healthHandler      *HealthHandler
-	authHandler        *AuthHandler
-	accountHandler     *AccountHandler
-	transactionHandler *TransactionHandler
-	categoryHandler    *CategoryHandler
-	budgetHandler      *BudgetHandler
-	reportHandler      *ReportHandler
-	uploadHandler      *UploadHandler
+	cfg                     *config.Config
+	healthHandler           *HealthHandler
+	authHandler             *AuthHandler
+	accountHandler          *AccountHandler
+	transactionHandler      *TransactionHandler
+	categoryHandler         *CategoryHandler
+	budgetHandler           *BudgetHandler
+	reportHandler           *ReportHandler
+	uploadHandler           *UploadHandler
+	goalHandler             *GoalHandler
+	loanHandler             *LoanHandler
+	contactHandler          *ContactHandler
+	sharedExpenseHandler    *SharedExpenseHandler
+	workspaceHandler        *WorkspaceHandler
+	investmentHandler       *InvestmentHandler
+	reconciliationHandler   *ReconciliationHandler
+	bankNotificationHandler *BankNotificationHandler
+	payeeHandler            *PayeeHandler
+	installmentHandler      *InstallmentHandler
+	templateHandler         *TemplateHandler
+	tagHandler              *TagHandler
+	attachmentHandler       *AttachmentHandler
+	fileHandler             *FileHandler
 }
 
 // NewRouter creates a new router
@@ -31,17 +45,45 @@ func NewRouter(
 	budgetHandler *BudgetHandler,
 	reportHandler *ReportHandler,
 	uploadHandler *UploadHandler,
+	goalHandler *GoalHandler,
+	loanHandler *LoanHandler,
+	contactHandler *ContactHandler,
+	sharedExpenseHandler *SharedExpenseHandler,
+	workspaceHandler *WorkspaceHandler,
+	investmentHandler *InvestmentHandler,
+	reconciliationHandler *ReconciliationHandler,
+	bankNotificationHandler *BankNotificationHandler,
+	payeeHandler *PayeeHandler,
+	installmentHandler *InstallmentHandler,
+	templateHandler *TemplateHandler,
+	tagHandler *TagHandler,
+	attachmentHandler *AttachmentHandler,
+	fileHandler *FileHandler,
 ) *Router {
 	return &Router{
-		cfg:                cfg,
-		healthHandler:      healthHandler,
-		authHandler:        authHandler,
-		accountHandler:     accountHandler,
-		transactionHandler: transactionHandler,
-		categoryHandler:    categoryHandler,
-		budgetHandler:      budgetHandler,
-		reportHandler:      reportHandler,
-		uploadHandler:      uploadHandler,
+		cfg:                     cfg,
+		healthHandler:           healthHandler,
+		authHandler:             authHandler,
+		accountHandler:          accountHandler,
+		transactionHandler:      transactionHandler,
+		categoryHandler:         categoryHandler,
+		budgetHandler:           budgetHandler,
+		reportHandler:           reportHandler,
+		uploadHandler:           uploadHandler,
+		goalHandler:             goalHandler,
+		loanHandler:             loanHandler,
+		contactHandler:          contactHandler,
+		sharedExpenseHandler:    sharedExpenseHandler,
+		workspaceHandler:        workspaceHandler,
+		investmentHandler:       investmentHandler,
+		reconciliationHandler:   reconciliationHandler,
+		bankNotificationHandler: bankNotificationHandler,
+		payeeHandler:            payeeHandler,
+		installmentHandler:      installmentHandler,
+		templateHandler:         templateHandler,
+		tagHandler:              tagHandler,
+		attachmentHandler:       attachmentHandler,
+		fileHandler:             fileHandler,
 	}
 }
 
@@ -88,19 +130,53 @@ func (r *Router) Setup() *gin.Engine {
 			authProtected.Use(middleware.AuthMiddleware(r.cfg.JWT.Secret))
 			{
 				authProtected.GET("/profile", r.authHandler.GetProfile)
+				authProtected.PUT("/profile", r.authHandler.UpdateProfile)
 				authProtected.POST("/change-password", r.authHandler.ChangePassword)
 				authProtected.POST("/logout", r.authHandler.Logout)
 				authProtected.POST("/send-verification-email", r.authHandler.SendVerificationEmail)
 			}
 		}
 
+		// File routes of the local and memory storage backends (authenticated by URL signature)
+		files := api.Group("/files")
+		{
+			files.GET("/*key", r.fileHandler.GetFile)
+			files.PUT("/*key", r.fileHandler.PutFile) // Target of presigned direct uploads
+		}
+
 		// Protected routes (require authentication)
 		protected := api.Group("")
 		protected.Use(middleware.AuthMiddleware(r.cfg.JWT.Secret))
 		protected.Use(middleware.ModerateRateLimitMiddleware()) // Rate limit for all API endpoints
 		{
+			// Accounts, transactions, categories, budgets and reports are scoped to the workspace
+			// selected by the X-Workspace-ID header (personal workspace by default).
+			// Parsing a transaction only returns a draft, so viewers may use it
+			workspaceScope := middleware.WorkspaceMiddleware(
+				r.workspaceHandler.service.ResolveRole,
+				protected.BasePath()+"/transactions/parse",
+			)
+
+			// Workspace routes
+			workspaces := protected.Group("/workspaces")
+			{
+				workspaces.GET("/invitations", r.workspaceHandler.GetInvitations) // Must be before /:id
+				workspaces.POST("/invitations/:id/accept", r.workspaceHandler.AcceptInvitation)
+				workspaces.POST("/invitations/:id/decline", r.workspaceHandler.DeclineInvitation)
+				workspaces.POST("", r.workspaceHandler.CreateWorkspace)
+				workspaces.GET("", r.workspaceHandler.GetWorkspaces)
+				workspaces.GET("/:id", r.workspaceHandler.GetWorkspace)
+				workspaces.PUT("/:id", r.workspaceHandler.UpdateWorkspace)
+				workspaces.DELETE("/:id", r.workspaceHandler.DeleteWorkspace)
+				workspaces.GET("/:id/members", r.workspaceHandler.GetMembers)
+				workspaces.POST("/:id/members", r.workspaceHandler.InviteMember)
+				workspaces.PUT("/:id/members/:memberId", r.workspaceHandler.UpdateMemberRole)
+				workspaces.DELETE("/:id/members/:memberId", r.workspaceHandler.RemoveMember)
+			}
+
 			// Account routes
 			accounts := protected.Group("/accounts")
+			accounts.Use(workspaceScope)
 			{
 				accounts.GET("/summary", r.accountHandler.GetAccountSummary) // Must be before /:id
 				accounts.GET("/banks", r.accountHandler.GetBanks)            // Must be before /:id
@@ -109,16 +185,50 @@ func (r *Router) Setup() *gin.Engine {
 				accounts.GET("/:id", r.accountHandler.GetAccount)
 				accounts.PUT("/:id", r.accountHandler.UpdateAccount)
 				accounts.DELETE("/:id", r.accountHandler.DeleteAccount)
+
+				// Loan account routes
+				accounts.GET("/:id/amortization", r.loanHandler.GetSchedule)
+				accounts.POST("/:id/loan-payments", r.loanHandler.RecordPayment)
+				accounts.GET("/:id/loan-payments", r.loanHandler.GetPayments)
+				accounts.DELETE("/:id/loan-payments/:paymentId", r.loanHandler.DeletePayment)
+
+				// Investment account routes
+				accounts.GET("/:id/holdings", r.investmentHandler.GetPortfolio)
+				accounts.POST("/:id/investment-transactions", r.investmentHandler.RecordTransaction)
+				accounts.GET("/:id/investment-transactions", r.investmentHandler.GetTransactions)
+				accounts.DELETE("/:id/investment-transactions/:transactionId", r.investmentHandler.DeleteTransaction)
+
+				// Statement reconciliation routes
+				accounts.POST("/:id/reconciliations", r.reconciliationHandler.StartReconciliation)
+				accounts.GET("/:id/reconciliations", r.reconciliationHandler.GetReconciliations)
+				accounts.GET("/:id/reconciliations/:reconciliationId", r.reconciliationHandler.GetReconciliation)
+				accounts.DELETE("/:id/reconciliations/:reconciliationId", r.reconciliationHandler.CancelReconciliation)
+				accounts.PUT("/:id/reconciliations/:reconciliationId/transactions", r.reconciliationHandler.ClearTransactions)
+				accounts.POST("/:id/reconciliations/:reconciliationId/finish", r.reconciliationHandler.FinishReconciliation)
+
+				// Credit card installment plan routes
+				accounts.POST("/:id/installments", r.installmentHandler.CreatePlan)
+				accounts.GET("/:id/installments", r.installmentHandler.GetPlans)
+				accounts.GET("/:id/installments/:installmentId", r.installmentHandler.GetPlan)
+				accounts.POST("/:id/installments/:installmentId/payoff", r.installmentHandler.PayOff)
+				accounts.DELETE("/:id/installments/:installmentId", r.installmentHandler.DeletePlan)
 			}
 
 			// Transaction routes
 			transactions := protected.Group("/transactions")
+			transactions.Use(workspaceScope)
 			{
 				// Special routes first (before /:id to avoid conflicts)
 				transactions.GET("/recent", r.transactionHandler.GetRecentTransactions)
 				transactions.GET("/summary", r.transactionHandler.GetTransactionSummary)
+				transactions.POST("/parse", r.transactionHandler.ParseTransaction)
 				transactions.PUT("/bulk/category", r.transactionHandler.BulkUpdateCategory)
+				transactions.POST("/bulk", r.transactionHandler.BulkEdit) // Picks transaction_ids, or the list filters in the query string
 				transactions.DELETE("/bulk", r.transactionHandler.BulkDelete)
+				transactions.GET("/trash", r.transactionHandler.GetTrash)
+				transactions.POST("/trash/:id/restore", r.transactionHandler.RestoreTransaction)
+				transactions.DELETE("/trash/:id", r.transactionHandler.DeleteTransactionPermanently)
+				transactions.POST("/from-template/:id", r.templateHandler.CreateFromTemplate) // Body fields override the template
 				
 				// Standard CRUD routes
 				transactions.POST("", r.transactionHandler.CreateTransaction)
@@ -126,21 +236,76 @@ func (r *Router) Setup() *gin.Engine {
 				transactions.GET("/:id", r.transactionHandler.GetTransaction)
 				transactions.PUT("/:id", r.transactionHandler.UpdateTransaction)
 				transactions.DELETE("/:id", r.transactionHandler.DeleteTransaction)
+				transactions.GET("/:id/history", r.transactionHandler.GetTransactionHistory)
+				transactions.POST("/:id/revert", r.transactionHandler.RevertTransaction)
+				transactions.POST("/:id/unlock", r.transactionHandler.UnlockTransaction)
+				transactions.POST("/:id/attachments", r.attachmentHandler.UploadAttachment)
+				transactions.POST("/:id/attachments/presign", r.attachmentHandler.PresignAttachment) // Signed URL to PUT the file straight to storage
+				transactions.GET("/:id/attachments", r.attachmentHandler.GetAttachments)
+				transactions.DELETE("/:id/attachments/:attachmentId", r.attachmentHandler.DeleteAttachment)
+			}
+
+			// Transaction template routes
+			templates := protected.Group("/transaction-templates")
+			templates.Use(workspaceScope)
+			{
+				templates.GET("/suggestions", r.templateHandler.GetSuggestedTemplates)
+				templates.POST("", r.templateHandler.CreateTemplate)
+				templates.GET("", r.templateHandler.GetAllTemplates)
+				templates.GET("/:id", r.templateHandler.GetTemplate)
+				templates.PUT("/:id", r.templateHandler.UpdateTemplate)
+				templates.DELETE("/:id", r.templateHandler.DeleteTemplate)
+			}
+
+			// Tag routes; names are path-escaped
+			tags := protected.Group("/tags")
+			tags.Use(workspaceScope)
+			{
+				tags.GET("", r.tagHandler.GetTags)                // Usage counts and colors
+				tags.PUT("/:name", r.tagHandler.UpdateTag)        // Rename on every transaction and set color
+				tags.POST("/:name/merge", r.tagHandler.MergeTags) // Folds source_tags into this tag
+			}
+
+			// Bank SMS and push-notification routes
+			bankNotifications := protected.Group("/bank-notifications")
+			bankNotifications.Use(workspaceScope)
+			{
+				bankNotifications.GET("/banks", r.bankNotificationHandler.GetSupportedBanks)
+				bankNotifications.POST("", r.bankNotificationHandler.IngestNotification)
+				bankNotifications.GET("", r.bankNotificationHandler.GetNotifications) // Supports ?account_id=xxx&mismatched=true
+			}
+
+			// Payee routes
+			payees := protected.Group("/payees")
+			payees.Use(workspaceScope)
+			{
+				payees.POST("", r.payeeHandler.CreatePayee)
+				payees.GET("", r.payeeHandler.GetAllPayees)
+				payees.GET("/:id", r.payeeHandler.GetPayee)
+				payees.PUT("/:id", r.payeeHandler.UpdatePayee)
+				payees.DELETE("/:id", r.payeeHandler.DeletePayee)
+				payees.POST("/:id/merge", r.payeeHandler.MergePayees) // Folds source_ids into this payee
 			}
 
 			// Category routes
 			categories := protected.Group("/categories")
+			categories.Use(workspaceScope)
 			{
 				categories.POST("", r.categoryHandler.CreateCategory)
 				categories.GET("", r.categoryHandler.GetAllCategories) // Supports ?type=income/expense/both, ?filter=parent, ?parent_id=xxx&filter=children
+				categories.POST("/templates/:locale/import", r.categoryHandler.ImportCategoryTemplate)
+				categories.GET("/templates", r.categoryHandler.GetCategoryTemplates) // Must be before /:id
+				categories.POST("/reset", r.categoryHandler.ResetCategories)         // Delete custom categories and restore missing defaults
 				categories.GET("/:id", r.categoryHandler.GetCategory)
 				categories.PUT("/:id", r.categoryHandler.UpdateCategory)
-				categories.DELETE("/:id", r.categoryHandler.DeleteCategory)
+				categories.DELETE("/:id", r.categoryHandler.DeleteCategory) // Supports ?reassign_to=xxx to merge a category in use
 				categories.GET("/:id/usage", r.categoryHandler.CheckCategoryUsage)
+				categories.POST("/:id/merge-into/:target", r.categoryHandler.MergeCategory) // Moves transactions, sub-categories and budgets, then deletes
 			}
 
 			// Budget routes
 			budgets := protected.Group("/budgets")
+			budgets.Use(workspaceScope)
 			{
 				budgets.POST("", r.budgetHandler.CreateOrUpdateBudget)    // Create or update budget (upsert)
 				budgets.GET("", r.budgetHandler.GetBudgetsByMonth)        // Get budgets by month (?month=YYYY-MM)
@@ -151,20 +316,89 @@ func (r *Router) Setup() *gin.Engine {
 
 			// Report routes
 			reports := protected.Group("/reports")
+			reports.Use(workspaceScope)
 			{
 				reports.GET("/overview", r.reportHandler.GetOverview)             // Get overview report
 				reports.GET("/by-category", r.reportHandler.GetByCategory)        // Get category breakdown
 				reports.GET("/by-merchant", r.reportHandler.GetByMerchant)        // Get merchant breakdown
+				reports.GET("/by-tag", r.reportHandler.GetByTag)                  // Get tag breakdown
 				reports.GET("/weekly-spending", r.reportHandler.GetWeeklySpending) // Get weekly spending
 				reports.GET("/weekly-cashflow", r.reportHandler.GetWeeklyCashflow) // Get weekly cashflow
 			}
 
+			// Security and price routes
+			securities := protected.Group("/securities")
+			securities.Use(workspaceScope)
+			{
+				securities.POST("/prices/import", r.investmentHandler.ImportPrices) // CSV with symbol,price,date columns
+				securities.POST("", r.investmentHandler.CreateSecurity)
+				securities.GET("", r.investmentHandler.GetSecurities)
+				securities.GET("/:id", r.investmentHandler.GetSecurity)
+				securities.PUT("/:id", r.investmentHandler.UpdateSecurity)
+				securities.DELETE("/:id", r.investmentHandler.DeleteSecurity)
+				securities.POST("/:id/prices", r.investmentHandler.UpdatePrice)
+				securities.GET("/:id/prices", r.investmentHandler.GetPrices)
+			}
+
+			// Goal routes
+			goals := protected.Group("/goals")
+			goals.Use(workspaceScope)
+			{
+				goals.POST("", r.goalHandler.CreateGoal)
+				goals.GET("", r.goalHandler.GetAllGoals) // Supports ?status=active/completed/archived
+				goals.GET("/:id", r.goalHandler.GetGoal)
+				goals.PUT("/:id", r.goalHandler.UpdateGoal)
+				goals.DELETE("/:id", r.goalHandler.DeleteGoal)
+				goals.POST("/:id/contributions", r.goalHandler.AddContribution)
+				goals.GET("/:id/contributions", r.goalHandler.GetContributions)
+				goals.DELETE("/:id/contributions/:contributionId", r.goalHandler.DeleteContribution)
+			}
+
+			// Contact routes
+			contacts := protected.Group("/contacts")
+			contacts.Use(workspaceScope)
+			{
+				contacts.GET("/balances", r.sharedExpenseHandler.GetBalances) // Must be before /:id
+				contacts.POST("", r.contactHandler.CreateContact)
+				contacts.GET("", r.contactHandler.GetAllContacts)
+				contacts.GET("/:id", r.contactHandler.GetContact)
+				contacts.PUT("/:id", r.contactHandler.UpdateContact)
+				contacts.DELETE("/:id", r.contactHandler.DeleteContact)
+				contacts.GET("/:id/balance", r.sharedExpenseHandler.GetContactLedger)
+				contacts.POST("/:id/settle", r.sharedExpenseHandler.SettleUp)
+			}
+
+			// Contact group routes
+			contactGroups := protected.Group("/contact-groups")
+			contactGroups.Use(workspaceScope)
+			{
+				contactGroups.POST("", r.contactHandler.CreateGroup)
+				contactGroups.GET("", r.contactHandler.GetAllGroups)
+				contactGroups.GET("/:id", r.contactHandler.GetGroup)
+				contactGroups.PUT("/:id", r.contactHandler.UpdateGroup)
+				contactGroups.DELETE("/:id", r.contactHandler.DeleteGroup)
+				contactGroups.GET("/:id/simplify", r.sharedExpenseHandler.SimplifyGroupDebts)
+			}
+
+			// Shared expense routes
+			sharedExpenses := protected.Group("/shared-expenses")
+			sharedExpenses.Use(workspaceScope)
+			{
+				sharedExpenses.POST("", r.sharedExpenseHandler.CreateSharedExpense)
+				sharedExpenses.GET("", r.sharedExpenseHandler.GetSharedExpenses) // Supports ?contact_id=xxx&group_id=xxx
+				sharedExpenses.GET("/:id", r.sharedExpenseHandler.GetSharedExpense)
+				sharedExpenses.DELETE("/:id", r.sharedExpenseHandler.DeleteSharedExpense)
+			}
+
 			// Upload routes
 			uploads := protected.Group("/uploads")
 			{
-				uploads.POST("/attachment", r.uploadHandler.UploadAttachment) // Upload transaction attachment
-				uploads.POST("/avatar", r.uploadHandler.UploadAvatar)         // Upload user avatar
-				uploads.DELETE("/attachment", r.uploadHandler.DeleteAttachment) // Delete attachment
+				uploads.POST("/attachment", workspaceScope, r.uploadHandler.UploadAttachment) // Upload an attachment to link through attachment_ids
+				uploads.POST("/attachment/presign", workspaceScope, r.uploadHandler.PresignAttachment) // Signed URL to PUT a large attachment straight to storage
+				uploads.POST("/attachment/:id/complete", workspaceScope, r.uploadHandler.CompleteAttachment) // Check and process a presigned upload
+				uploads.POST("/avatar", r.uploadHandler.UploadAvatar)         // Upload user avatar, replacing the previous one
+				uploads.DELETE("/avatar", r.uploadHandler.DeleteAvatar)       // Back to the Google picture or initials
+				uploads.DELETE("/attachment", workspaceScope, r.uploadHandler.DeleteAttachment) // Delete an attachment of the workspace
 			}
 		}
 	}
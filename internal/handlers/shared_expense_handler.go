@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SharedExpenseHandler handles shared expense and settle-up HTTP requests
+type SharedExpenseHandler struct {
+	service *services.SharedExpenseService
+}
+
+// NewSharedExpenseHandler creates a new shared expense handler
+func NewSharedExpenseHandler(service *services.SharedExpenseService) *SharedExpenseHandler {
+	return &SharedExpenseHandler{service: service}
+}
+
+// CreateSharedExpense handles POST /shared-expenses
+func (h *SharedExpenseHandler) CreateSharedExpense(c *gin.Context) {
+	var req models.CreateSharedExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	expense, err := h.service.CreateSharedExpense(workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create shared expense", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Shared expense created successfully", expense)
+}
+
+// GetSharedExpenses handles GET /shared-expenses?contact_id=xxx&group_id=xxx
+func (h *SharedExpenseHandler) GetSharedExpenses(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	expenses, err := h.service.GetSharedExpenses(workspaceID, c.Query("contact_id"), c.Query("group_id"))
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Shared expenses retrieved successfully", expenses)
+}
+
+// GetSharedExpense handles GET /shared-expenses/:id
+func (h *SharedExpenseHandler) GetSharedExpense(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	expense, err := h.service.GetSharedExpense(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Shared expense")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Shared expense retrieved successfully", expense)
+}
+
+// DeleteSharedExpense handles DELETE /shared-expenses/:id
+func (h *SharedExpenseHandler) DeleteSharedExpense(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteSharedExpense(id, workspaceID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete shared expense", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Shared expense deleted successfully", nil)
+}
+
+// GetBalances handles GET /contacts/balances
+func (h *SharedExpenseHandler) GetBalances(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	balances, err := h.service.GetBalances(workspaceID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Balances retrieved successfully", balances)
+}
+
+// GetContactLedger handles GET /contacts/:id/balance
+func (h *SharedExpenseHandler) GetContactLedger(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	ledger, err := h.service.GetContactLedger(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Contact")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Balance retrieved successfully", ledger)
+}
+
+// SettleUp handles POST /contacts/:id/settle
+func (h *SharedExpenseHandler) SettleUp(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.SettleUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	settlement, err := h.service.SettleUp(id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to settle up", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Settled up successfully", settlement)
+}
+
+// SimplifyGroupDebts handles GET /contact-groups/:id/simplify
+func (h *SharedExpenseHandler) SimplifyGroupDebts(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	debts, err := h.service.SimplifyGroupDebts(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Contact group")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Simplified debts retrieved successfully", debts)
+}
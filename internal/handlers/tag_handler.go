@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler handles tag-related HTTP requests
+type TagHandler struct {
+	service *services.TagService
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(service *services.TagService) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+// GetTags handles GET /tags
+func (h *TagHandler) GetTags(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	tags, err := h.service.GetTags(workspaceID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Tags retrieved successfully", tags)
+}
+
+// UpdateTag handles PUT /tags/:name
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	name := c.Param("name")
+
+	var req models.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.UpdateTag(workspaceID, userID, name, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update tag", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Tag updated successfully", result)
+}
+
+// MergeTags handles POST /tags/:name/merge
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	name := c.Param("name")
+
+	var req models.MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.MergeTags(workspaceID, userID, name, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to merge tags", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Tags merged successfully", result)
+}
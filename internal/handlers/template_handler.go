@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateHandler handles transaction template HTTP requests
+type TemplateHandler struct {
+	service *services.TemplateService
+}
+
+// NewTemplateHandler creates a new transaction template handler
+func NewTemplateHandler(service *services.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+// CreateTemplate handles POST /transaction-templates
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req models.CreateTransactionTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	template, err := h.service.CreateTemplate(workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create template", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Template created successfully", template)
+}
+
+// GetAllTemplates handles GET /transaction-templates
+func (h *TemplateHandler) GetAllTemplates(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	templates, err := h.service.GetAllTemplates(workspaceID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Templates retrieved successfully", templates)
+}
+
+// GetSuggestedTemplates handles GET /transaction-templates/suggestions
+func (h *TemplateHandler) GetSuggestedTemplates(c *gin.Context) {
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	suggestions, err := h.service.GetSuggestedTemplates(workspaceID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Suggested templates retrieved successfully", suggestions)
+}
+
+// GetTemplate handles GET /transaction-templates/:id
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	template, err := h.service.GetTemplate(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Template")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Template retrieved successfully", template)
+}
+
+// UpdateTemplate handles PUT /transaction-templates/:id
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateTransactionTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	template, err := h.service.UpdateTemplate(id, workspaceID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update template", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Template updated successfully", template)
+}
+
+// DeleteTemplate handles DELETE /transaction-templates/:id
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteTemplate(id, workspaceID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete template", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Template deleted successfully", nil)
+}
+
+// CreateFromTemplate handles POST /transactions/from-template/:id
+func (h *TemplateHandler) CreateFromTemplate(c *gin.Context) {
+	id := c.Param("id")
+
+	// The body is optional; without it the template is used as saved
+	var req models.CreateFromTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationErrorResponse(c, err.Error())
+			return
+		}
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	transaction, err := h.service.CreateFromTemplate(id, workspaceID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create transaction", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Transaction created successfully", transaction)
+}
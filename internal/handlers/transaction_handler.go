@@ -3,6 +3,7 @@ package handlers
 import (
 	"finance-hub-api/internal/models"
 	"finance-hub-api/internal/services"
+	"finance-hub-api/internal/utils"
 	"finance-hub-api/pkg/response"
 	"net/http"
 	"strconv"
@@ -35,8 +36,10 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	}
 
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	transaction, err := h.service.CreateTransaction(userID, req)
+	transaction, err := h.service.CreateTransaction(workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create transaction", err.Error())
 		return
@@ -45,14 +48,35 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	response.SuccessResponse(c, http.StatusCreated, "Transaction created successfully", transaction)
 }
 
+// ParseTransaction handles POST /transactions/parse
+// Returns a draft for review; nothing is saved
+func (h *TransactionHandler) ParseTransaction(c *gin.Context) {
+	var req models.ParseTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	parsed, err := h.service.ParseTransaction(workspaceID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to parse transaction", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Transaction parsed successfully", parsed)
+}
+
 // GetTransaction handles GET /transactions/:id
 func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	id := c.Param("id")
 
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	transaction, err := h.service.GetTransaction(id, userID)
+	transaction, err := h.service.GetTransaction(id, workspaceID)
 	if err != nil {
 		response.NotFoundResponse(c, "Transaction")
 		return
@@ -62,6 +86,7 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 }
 
 // GetAllTransactions handles GET /transactions
+// Supports page/limit and, with pagination=cursor or a cursor, keyset pagination
 func (h *TransactionHandler) GetAllTransactions(c *gin.Context) {
 	var filters models.TransactionFilterQuery
 	if err := c.ShouldBindQuery(&filters); err != nil {
@@ -69,10 +94,31 @@ func (h *TransactionHandler) GetAllTransactions(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	// Parse the search query here so syntax errors are reported as validation errors
+	if filters.Search != "" {
+		terms, err := utils.ParseSearchQuery(filters.Search)
+		if err != nil {
+			response.ValidationErrorResponse(c, err.Error())
+			return
+		}
+		filters.SearchTerms = terms
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if filters.UsesCursor() {
+		page, err := h.service.GetTransactionsByCursor(workspaceID, filters)
+		if err != nil {
+			response.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve transactions", err.Error())
+			return
+		}
+
+		response.SuccessResponse(c, http.StatusOK, "Transactions retrieved successfully", page)
+		return
+	}
 
-	result, err := h.service.GetAllTransactions(userID, filters)
+	result, err := h.service.GetAllTransactions(workspaceID, filters)
 	if err != nil {
 		response.InternalErrorResponse(c, err)
 		return
@@ -91,10 +137,17 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	transaction, err := h.service.UpdateTransaction(id, userID, req)
+	transaction, err := h.service.UpdateTransaction(id, workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update transaction", err.Error())
 		return
@@ -107,15 +160,90 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 	id := c.Param("id")
 
-	userIDStr, _ := c.Get("user_id")
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	if err := h.service.DeleteTransaction(id, userID); err != nil {
+	if err := h.service.DeleteTransaction(id, workspaceID, userID); err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete transaction", err.Error())
 		return
 	}
 
-	response.SuccessResponse(c, http.StatusOK, "Transaction deleted successfully", nil)
+	response.SuccessResponse(c, http.StatusOK, "Transaction moved to trash", nil)
+}
+
+// UnlockTransaction handles POST /transactions/:id/unlock
+func (h *TransactionHandler) UnlockTransaction(c *gin.Context) {
+	id := c.Param("id")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	transaction, err := h.service.UnlockTransaction(id, workspaceID, userID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to unlock transaction", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Transaction unlocked successfully", transaction)
+}
+
+// GetTransactionHistory handles GET /transactions/:id/history
+func (h *TransactionHandler) GetTransactionHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	history, err := h.service.GetTransactionHistory(id, workspaceID)
+	if err != nil {
+		response.NotFoundResponse(c, "Transaction")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Transaction history retrieved successfully", history)
+}
+
+// RevertTransaction handles POST /transactions/:id/revert
+func (h *TransactionHandler) RevertTransaction(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.RevertTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	transaction, err := h.service.RevertTransaction(id, workspaceID, userID, req.Version)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to revert transaction", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Transaction reverted successfully", transaction)
 }
 
 // BulkUpdateCategory handles PUT /transactions/bulk/category
@@ -126,10 +254,17 @@ func (h *TransactionHandler) BulkUpdateCategory(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	count, err := h.service.BulkUpdateCategory(userID, req)
+	count, err := h.service.BulkUpdateCategory(workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update categories", err.Error())
 		return
@@ -140,6 +275,44 @@ func (h *TransactionHandler) BulkUpdateCategory(c *gin.Context) {
 	})
 }
 
+// BulkEdit handles POST /transactions/bulk
+// Without transaction_ids in the body, the transactions are selected by the same query filters as GET /transactions
+func (h *TransactionHandler) BulkEdit(c *gin.Context) {
+	var filters models.TransactionFilterQuery
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	var req models.BulkEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.BulkEdit(workspaceID, userID, filters, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to edit transactions", err.Error())
+		return
+	}
+
+	message := "Transactions updated successfully"
+	if req.DryRun {
+		message = "Bulk edit checked successfully"
+	}
+	response.SuccessResponse(c, http.StatusOK, message, result)
+}
+
 // BulkDelete handles DELETE /transactions/bulk
 func (h *TransactionHandler) BulkDelete(c *gin.Context) {
 	var req models.BulkDeleteRequest
@@ -148,10 +321,17 @@ func (h *TransactionHandler) BulkDelete(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	count, err := h.service.BulkDelete(userID, req)
+	count, err := h.service.BulkDelete(workspaceID, userID, req)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete transactions", err.Error())
 		return
@@ -162,10 +342,75 @@ func (h *TransactionHandler) BulkDelete(c *gin.Context) {
 	})
 }
 
+// GetTrash handles GET /transactions/trash
+func (h *TransactionHandler) GetTrash(c *gin.Context) {
+	var pagination models.PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	result, err := h.service.GetTrash(workspaceID, pagination)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Trash retrieved successfully", result)
+}
+
+// RestoreTransaction handles POST /transactions/trash/:id/restore
+func (h *TransactionHandler) RestoreTransaction(c *gin.Context) {
+	id := c.Param("id")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	transaction, err := h.service.RestoreTransaction(id, workspaceID, userID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to restore transaction", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Transaction restored successfully", transaction)
+}
+
+// DeleteTransactionPermanently handles DELETE /transactions/trash/:id
+func (h *TransactionHandler) DeleteTransactionPermanently(c *gin.Context) {
+	id := c.Param("id")
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := h.service.DeleteTransactionPermanently(id, workspaceID, userID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete transaction", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Transaction permanently deleted", nil)
+}
+
 // GetRecentTransactions handles GET /transactions/recent
 func (h *TransactionHandler) GetRecentTransactions(c *gin.Context) {
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
 	// Get limit from query param, default to 5
 	limit := 5
@@ -175,7 +420,7 @@ func (h *TransactionHandler) GetRecentTransactions(c *gin.Context) {
 		}
 	}
 
-	transactions, err := h.service.GetRecentTransactions(userID, limit)
+	transactions, err := h.service.GetRecentTransactions(workspaceID, limit)
 	if err != nil {
 		response.InternalErrorResponse(c, err)
 		return
@@ -192,10 +437,10 @@ func (h *TransactionHandler) GetTransactionSummary(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
 
-	summary, err := h.service.GetTransactionSummary(userID, filters)
+	summary, err := h.service.GetTransactionSummary(workspaceID, filters)
 	if err != nil {
 		response.InternalErrorResponse(c, err)
 		return
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"finance-hub-api/internal/config"
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
 	"finance-hub-api/internal/utils"
 	"finance-hub-api/pkg/response"
 	"net/http"
@@ -10,31 +13,33 @@ import (
 )
 
 type UploadHandler struct {
-	r2Storage *utils.R2Storage
-	config    *config.Config
+	storage           utils.Storage
+	attachmentService *services.AttachmentService
+	authService       *services.AuthService
+	config            *config.Config
 }
 
-func NewUploadHandler(cfg *config.Config) (*UploadHandler, error) {
-	// Initialize R2 storage
-	r2Storage, err := utils.NewR2Storage(
-		cfg.R2.Endpoint,
-		cfg.R2.AccessKeyID,
-		cfg.R2.SecretAccessKey,
-		cfg.R2.BucketName,
-		cfg.R2.PublicBaseURL,
-	)
-	if err != nil {
-		return nil, err
-	}
-
+func NewUploadHandler(cfg *config.Config, storage utils.Storage, attachmentService *services.AttachmentService, authService *services.AuthService) *UploadHandler {
 	return &UploadHandler{
-		r2Storage: r2Storage,
-		config:    cfg,
-	}, nil
+		storage:           storage,
+		attachmentService: attachmentService,
+		authService:       authService,
+		config:            cfg,
+	}
 }
 
 // UploadAttachment handles file upload for transaction attachments
+// The file is recorded as an upload waiting for a transaction to list its id in attachment_ids
 func (h *UploadHandler) UploadAttachment(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
 	// Get file from form
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
@@ -43,26 +48,76 @@ func (h *UploadHandler) UploadAttachment(c *gin.Context) {
 	}
 	defer file.Close()
 
-	// Validate file
-	err = utils.ValidateFile(file, header, h.config.Storage.MaxUploadSize, h.config.Storage.AllowedFileTypes)
+	// Validate file against its content
+	content, contentType, err := utils.ReadUpload(file, header, h.config.Storage.MaxUploadSize, h.config.Storage.AllowedFileTypes)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Invalid file", err.Error())
 		return
 	}
 
-	// Upload to R2
-	publicURL, err := h.r2Storage.UploadFile(c.Request.Context(), file, header, "attachments")
+	// Upload to storage
+	attachment, err := h.attachmentService.Upload(workspaceID, userID, nil, header.Filename, contentType, content)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to upload file", err.Error())
 		return
 	}
 
-	// Return the public URL
+	// Return the URL to keep in attachment_url along with the attachment to link
 	response.SuccessResponse(c, http.StatusOK, "File uploaded successfully", gin.H{
-		"url": publicURL,
+		"url":        attachment.URL,
+		"attachment": attachment,
 	})
 }
 
+// PresignAttachment handles POST /uploads/attachment/presign
+// It returns a signed URL to PUT the file straight to storage, for files too large to send through the API
+func (h *UploadHandler) PresignAttachment(c *gin.Context) {
+	var req models.PresignAttachmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+	userID := userIDStr.(string)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	if err := utils.ValidateUpload(req.Size, req.ContentType, h.config.Storage.MaxDirectUploadSize, h.config.Storage.AllowedFileTypes); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Invalid file", err.Error())
+		return
+	}
+
+	upload, err := h.attachmentService.PresignUpload(workspaceID, userID, nil, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to prepare upload", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Upload URL created successfully", upload)
+}
+
+// CompleteAttachment handles POST /uploads/attachment/:id/complete
+// It checks and processes a file PUT to a presigned URL; linking it through attachment_ids does the same.
+func (h *UploadHandler) CompleteAttachment(c *gin.Context) {
+	id := c.Param("id")
+
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	attachment, err := h.attachmentService.CompleteUpload(id, workspaceID)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to complete upload", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Upload completed successfully", attachment)
+}
+
 // DeleteAttachment handles file deletion
 func (h *UploadHandler) DeleteAttachment(c *gin.Context) {
 	// Get URL from request body
@@ -75,10 +130,13 @@ func (h *UploadHandler) DeleteAttachment(c *gin.Context) {
 		return
 	}
 
-	// Delete from R2
-	err := h.r2Storage.DeleteFile(c.Request.Context(), req.URL)
+	workspaceIDStr, _ := c.Get("workspace_id")
+	workspaceID := workspaceIDStr.(string)
+
+	// Delete from storage, only when the file belongs to the workspace
+	err := h.attachmentService.DeleteUpload(workspaceID, req.URL)
 	if err != nil {
-		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete file", err.Error())
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete file", err.Error())
 		return
 	}
 
@@ -86,7 +144,14 @@ func (h *UploadHandler) DeleteAttachment(c *gin.Context) {
 }
 
 // UploadAvatar handles avatar upload
+// The image becomes the user's avatar and the previously uploaded one is deleted
 func (h *UploadHandler) UploadAvatar(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
 	// Get file from form
 	file, header, err := c.Request.FormFile("avatar")
 	if err != nil {
@@ -97,21 +162,46 @@ func (h *UploadHandler) UploadAvatar(c *gin.Context) {
 
 	// Validate file (only images for avatar)
 	allowedTypes := []string{"image/jpeg", "image/png", "image/webp"}
-	err = utils.ValidateFile(file, header, h.config.Storage.MaxUploadSize, allowedTypes)
+	content, contentType, err := utils.ReadUpload(file, header, h.config.Storage.MaxUploadSize, allowedTypes)
 	if err != nil {
 		response.ErrorResponse(c, http.StatusBadRequest, "Invalid file", err.Error())
 		return
 	}
 
-	// Upload to R2
-	publicURL, err := h.r2Storage.UploadFile(c.Request.Context(), file, header, "avatars")
+	profile, err := h.authService.SetAvatar(c.Request.Context(), userIDStr.(string), content, contentType)
 	if err != nil {
-		response.ErrorResponse(c, http.StatusInternalServerError, "Failed to upload avatar", err.Error())
+		switch {
+		case errors.Is(err, services.ErrInvalidAvatar):
+			response.ErrorResponse(c, http.StatusBadRequest, "Invalid file", err.Error())
+		case errors.Is(err, services.ErrUserNotFound):
+			response.NotFoundResponse(c, "User")
+		default:
+			response.ErrorResponse(c, http.StatusInternalServerError, "Failed to upload avatar", err.Error())
+		}
 		return
 	}
 
-	// Return the public URL
-	response.SuccessResponse(c, http.StatusOK, "Avatar uploaded successfully", gin.H{
-		"avatar_url": publicURL,
-	})
+	response.SuccessResponse(c, http.StatusOK, "Avatar uploaded successfully", profile)
+}
+
+// DeleteAvatar handles DELETE /uploads/avatar
+// The avatar goes back to the Google picture, or to initials
+func (h *UploadHandler) DeleteAvatar(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	profile, err := h.authService.RemoveAvatar(c.Request.Context(), userIDStr.(string))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			response.NotFoundResponse(c, "User")
+		} else {
+			response.InternalErrorResponse(c, err)
+		}
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Avatar removed successfully", profile)
 }
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/services"
+	"finance-hub-api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkspaceHandler handles workspace and membership HTTP requests
+type WorkspaceHandler struct {
+	service *services.WorkspaceService
+}
+
+// NewWorkspaceHandler creates a new workspace handler
+func NewWorkspaceHandler(service *services.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{service: service}
+}
+
+// CreateWorkspace handles POST /workspaces
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	var req models.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		response.UnauthorizedResponse(c, "User not authenticated")
+		return
+	}
+
+	userID := userIDStr.(string)
+
+	workspace, err := h.service.CreateWorkspace(userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to create workspace", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Workspace created successfully", workspace)
+}
+
+// GetWorkspaces handles GET /workspaces
+func (h *WorkspaceHandler) GetWorkspaces(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	workspaces, err := h.service.GetWorkspaces(userID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Workspaces retrieved successfully", workspaces)
+}
+
+// GetWorkspace handles GET /workspaces/:id
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	id := c.Param("id")
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	workspace, err := h.service.GetWorkspace(id, userID)
+	if err != nil {
+		response.NotFoundResponse(c, "Workspace")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Workspace retrieved successfully", workspace)
+}
+
+// UpdateWorkspace handles PUT /workspaces/:id
+func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	workspace, err := h.service.UpdateWorkspace(id, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update workspace", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Workspace updated successfully", workspace)
+}
+
+// DeleteWorkspace handles DELETE /workspaces/:id
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	id := c.Param("id")
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	if err := h.service.DeleteWorkspace(id, userID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to delete workspace", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Workspace deleted successfully", nil)
+}
+
+// GetMembers handles GET /workspaces/:id/members
+func (h *WorkspaceHandler) GetMembers(c *gin.Context) {
+	id := c.Param("id")
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	members, err := h.service.GetMembers(id, userID)
+	if err != nil {
+		response.NotFoundResponse(c, "Workspace")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Members retrieved successfully", members)
+}
+
+// InviteMember handles POST /workspaces/:id/members
+func (h *WorkspaceHandler) InviteMember(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.InviteWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	member, err := h.service.InviteMember(id, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to invite member", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusCreated, "Invitation sent successfully", member)
+}
+
+// UpdateMemberRole handles PUT /workspaces/:id/members/:memberId
+func (h *WorkspaceHandler) UpdateMemberRole(c *gin.Context) {
+	id := c.Param("id")
+	memberID := c.Param("memberId")
+
+	var req models.UpdateWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	member, err := h.service.UpdateMemberRole(id, memberID, userID, req)
+	if err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to update member", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Member updated successfully", member)
+}
+
+// RemoveMember handles DELETE /workspaces/:id/members/:memberId
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	id := c.Param("id")
+	memberID := c.Param("memberId")
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	if err := h.service.RemoveMember(id, memberID, userID); err != nil {
+		response.ErrorResponse(c, http.StatusBadRequest, "Failed to remove member", err.Error())
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Member removed successfully", nil)
+}
+
+// GetInvitations handles GET /workspaces/invitations
+func (h *WorkspaceHandler) GetInvitations(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	invitations, err := h.service.GetInvitations(userID)
+	if err != nil {
+		response.InternalErrorResponse(c, err)
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+// AcceptInvitation handles POST /workspaces/invitations/:id/accept
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	id := c.Param("id")
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	member, err := h.service.AcceptInvitation(id, userID)
+	if err != nil {
+		response.NotFoundResponse(c, "Invitation")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Invitation accepted successfully", member)
+}
+
+// DeclineInvitation handles POST /workspaces/invitations/:id/decline
+func (h *WorkspaceHandler) DeclineInvitation(c *gin.Context) {
+	id := c.Param("id")
+
+	userIDStr, _ := c.Get("user_id")
+	userID := userIDStr.(string)
+
+	if err := h.service.DeclineInvitation(id, userID); err != nil {
+		response.NotFoundResponse(c, "Invitation")
+		return
+	}
+
+	response.SuccessResponse(c, http.StatusOK, "Invitation declined successfully", nil)
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"finance-hub-api/internal/models"
 	"finance-hub-api/pkg/response"
+	"net/http"
 	"strings"
 	"time"
 
@@ -51,6 +53,46 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 	}
 }
 
+// WorkspaceResolver returns the user's role in a workspace, or an error when they are not a member
+type WorkspaceResolver func(workspaceID, userID string) (string, error)
+
+// WorkspaceMiddleware selects the workspace from the X-Workspace-ID header and enforces member roles.
+// Requests without the header use the user's personal workspace; viewers may only read.
+// readOnlyRoutes lists full route paths that take a body but change nothing, so viewers may call them too
+func WorkspaceMiddleware(resolve WorkspaceResolver, readOnlyRoutes ...string) gin.HandlerFunc {
+	readOnly := make(map[string]bool, len(readOnlyRoutes))
+	for _, route := range readOnlyRoutes {
+		readOnly[route] = true
+	}
+
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		workspaceID := c.GetHeader("X-Workspace-ID")
+		if workspaceID == "" {
+			workspaceID = userID
+		}
+
+		role, err := resolve(workspaceID, userID)
+		if err != nil {
+			response.ForbiddenResponse(c, "You do not have access to this workspace")
+			c.Abort()
+			return
+		}
+
+		if role == models.WorkspaceRoleViewer && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead && !readOnly[c.FullPath()] {
+			response.ForbiddenResponse(c, "Viewers cannot modify workspace data")
+			c.Abort()
+			return
+		}
+
+		c.Set("workspace_id", workspaceID)
+		c.Set("workspace_role", role)
+
+		c.Next()
+	}
+}
+
 // CORSMiddleware handles CORS
 func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -68,7 +110,7 @@ func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
 		if allowed {
 			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Workspace-ID")
 			c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		}
 
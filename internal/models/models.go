@@ -6,38 +6,63 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID             string    `json:"id" bson:"_id,omitempty"`
-	Email          string    `json:"email" bson:"email"`
-	PasswordHash   *string   `json:"-" bson:"password_hash,omitempty"` // Hidden from JSON
-	FullName       string    `json:"full_name" bson:"full_name"`
-	AvatarURL      *string   `json:"avatar_url,omitempty" bson:"avatar_url,omitempty"`
-	GoogleID       *string   `json:"-" bson:"google_id,omitempty"` // Hidden from JSON
-	AuthProvider   string    `json:"auth_provider" bson:"auth_provider"` // "email", "google"
-	EmailVerified  bool      `json:"email_verified" bson:"email_verified"`
-	IsActive       bool      `json:"is_active" bson:"is_active"`
-	LastLoginAt    *time.Time `json:"last_login_at,omitempty" bson:"last_login_at,omitempty"`
-	CreatedAt      time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at" bson:"updated_at"`
+	ID               string          `json:"id" bson:"_id,omitempty"`
+	Email            string          `json:"email" bson:"email"`
+	PasswordHash     *string         `json:"-" bson:"password_hash,omitempty"` // Hidden from JSON
+	FullName         string          `json:"full_name" bson:"full_name"`
+	AvatarURL        *string         `json:"avatar_url,omitempty" bson:"avatar_url,omitempty"`
+	GooglePictureURL *string         `json:"-" bson:"google_picture_url,omitempty"` // Google profile picture, restored when an uploaded avatar is removed
+	Locale           string          `json:"locale,omitempty" bson:"locale,omitempty"`
+	Timezone         string          `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	Preferences      UserPreferences `json:"preferences" bson:"preferences,omitempty"`
+	GoogleID         *string         `json:"-" bson:"google_id,omitempty"`       // Hidden from JSON
+	AuthProvider     string          `json:"auth_provider" bson:"auth_provider"` // "email", "google"
+	EmailVerified    bool            `json:"email_verified" bson:"email_verified"`
+	IsActive         bool            `json:"is_active" bson:"is_active"`
+	LastLoginAt      *time.Time      `json:"last_login_at,omitempty" bson:"last_login_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at" bson:"updated_at"`
 }
 
 // UserProfile represents public user profile (safe to send to client)
 type UserProfile struct {
-	ID            string     `json:"id"`
-	Email         string     `json:"email"`
-	FullName      string     `json:"full_name"`
-	AvatarURL     *string    `json:"avatar_url,omitempty"`
-	AuthProvider  string     `json:"auth_provider"`
-	EmailVerified bool       `json:"email_verified"`
-	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
+	ID                 string          `json:"id"`
+	Email              string          `json:"email"`
+	FullName           string          `json:"full_name"`
+	AvatarURL          *string         `json:"avatar_url,omitempty"`
+	AvatarThumbnailURL *string         `json:"avatar_thumbnail_url,omitempty"` // Small square variant, for avatars uploaded to storage
+	Initials           string          `json:"initials"`                       // Shown when there is no avatar
+	Locale             string          `json:"locale"`
+	Timezone           string          `json:"timezone"`
+	Preferences        UserPreferences `json:"preferences"`
+	AuthProvider       string          `json:"auth_provider"`
+	EmailVerified      bool            `json:"email_verified"`
+	LastLoginAt        *time.Time      `json:"last_login_at,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+}
+
+// Default profile settings, used until the user changes them
+const (
+	DefaultLocale   = "vi"
+	DefaultTimezone = "Asia/Ho_Chi_Minh"
+)
+
+// UserPreferences represents display preferences of a user
+// Empty fields mean the client default.
+type UserPreferences struct {
+	Currency    string `json:"currency,omitempty" bson:"currency,omitempty" binding:"omitempty,len=3,uppercase"`
+	DateFormat  string `json:"date_format,omitempty" bson:"date_format,omitempty" binding:"omitempty,oneof=DD/MM/YYYY MM/DD/YYYY YYYY-MM-DD"`
+	Theme       string `json:"theme,omitempty" bson:"theme,omitempty" binding:"omitempty,oneof=light dark system"`
+	StartOfWeek string `json:"start_of_week,omitempty" bson:"start_of_week,omitempty" binding:"omitempty,oneof=monday sunday"`
 }
 
 // Account represents a financial account
 type Account struct {
 	ID                   string    `json:"id" bson:"_id,omitempty"`
-	UserID               string    `json:"user_id" bson:"user_id"`
+	WorkspaceID          string    `json:"workspace_id" bson:"workspace_id"`
+	UserID               string    `json:"user_id" bson:"user_id"` // Member who created the account
 	Name                 string    `json:"name" bson:"name" binding:"required"`
-	Type                 string    `json:"type" bson:"type" binding:"required"` // cash, bank, credit
+	Type                 string    `json:"type" bson:"type" binding:"required"` // cash, bank, credit, loan, investment
 	Balance              float64   `json:"balance" bson:"balance"`
 	Currency             string    `json:"currency" bson:"currency" binding:"required"`
 	Icon                 *string   `json:"icon,omitempty" bson:"icon,omitempty"`
@@ -56,6 +81,14 @@ type Account struct {
 	StatementDate        *int      `json:"statement_date,omitempty" bson:"statement_date,omitempty"` // Day of month (1-31)
 	DueDate              *int      `json:"due_date,omitempty" bson:"due_date,omitempty"`             // Day of month (1-31)
 	
+	// Loan fields
+	LoanDirection        *string    `json:"loan_direction,omitempty" bson:"loan_direction,omitempty"`       // borrowed, lent
+	Principal            *float64   `json:"principal,omitempty" bson:"principal,omitempty"`
+	InterestRate         *float64   `json:"interest_rate,omitempty" bson:"interest_rate,omitempty"`         // Annual rate in percent
+	TermPeriods          *int       `json:"term_periods,omitempty" bson:"term_periods,omitempty"`           // Number of payments
+	PaymentFrequency     *string    `json:"payment_frequency,omitempty" bson:"payment_frequency,omitempty"` // weekly, biweekly, monthly, quarterly
+	LoanStartDate        *time.Time `json:"loan_start_date,omitempty" bson:"loan_start_date,omitempty"`
+	
 	// Status
 	IsActive             bool      `json:"is_active" bson:"is_active"`
 	IsExcludedFromTotal  bool      `json:"is_excluded_from_total" bson:"is_excluded_from_total"`
@@ -68,41 +101,57 @@ type Account struct {
 
 // Category represents a transaction category
 type Category struct {
-	ID        string    `json:"id" bson:"_id,omitempty"`
-	UserID    string    `json:"user_id" bson:"user_id"`
-	ParentID  *string   `json:"parent_id,omitempty" bson:"parent_id,omitempty"` // For hierarchical categories
-	Name      string    `json:"name" bson:"name" binding:"required"`
-	Type      string    `json:"type" bson:"type" binding:"required"` // income, expense, both
-	Icon      *string   `json:"icon,omitempty" bson:"icon,omitempty"`
-	Color     *string   `json:"color,omitempty" bson:"color,omitempty"`
-	IsDefault bool      `json:"is_default" bson:"is_default"`
-	CreatedAt time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID string    `json:"workspace_id" bson:"workspace_id"`
+	UserID      string    `json:"user_id" bson:"user_id"`                         // Member who created the category
+	ParentID    *string   `json:"parent_id,omitempty" bson:"parent_id,omitempty"` // For hierarchical categories
+	Name        string    `json:"name" bson:"name" binding:"required"`
+	Type        string    `json:"type" bson:"type" binding:"required"` // income, expense, both
+	Icon        *string   `json:"icon,omitempty" bson:"icon,omitempty"`
+	Color       *string   `json:"color,omitempty" bson:"color,omitempty"`
+	IsDefault   bool      `json:"is_default" bson:"is_default"`                         // Seeded from the default template; still editable
+	TemplateKey *string   `json:"template_key,omitempty" bson:"template_key,omitempty"` // Entry of the default template the category was seeded from
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
 }
 
 // Transaction represents a financial transaction
 type Transaction struct {
-	ID              string    `json:"id" bson:"_id,omitempty"`
-	UserID          string    `json:"user_id" bson:"user_id"`
-	AccountID       string    `json:"account_id" bson:"account_id" binding:"required"`
-	ToAccountID     *string   `json:"to_account_id,omitempty" bson:"to_account_id,omitempty"` // For transfer transactions
-	CategoryID      *string   `json:"category_id,omitempty" bson:"category_id,omitempty"` // Optional for transfers
-	Type            string    `json:"type" bson:"type" binding:"required"` // income, expense, transfer
-	Amount          float64   `json:"amount" bson:"amount" binding:"required,gt=0"`
-	Merchant        *string   `json:"merchant,omitempty" bson:"merchant,omitempty"` // Merchant/Payee name
-	Description     *string   `json:"description,omitempty" bson:"description,omitempty"`
-	TransactionDate time.Time `json:"transaction_date" bson:"transaction_date" binding:"required"`
-	Notes           *string   `json:"notes,omitempty" bson:"notes,omitempty"`
-	Tags            []string  `json:"tags,omitempty" bson:"tags,omitempty"` // Tags for categorization
-	AttachmentURL   *string   `json:"attachment_url,omitempty" bson:"attachment_url,omitempty"`
-	CreatedAt       time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" bson:"updated_at"`
+	ID              string                  `json:"id" bson:"_id,omitempty"`
+	WorkspaceID     string                  `json:"workspace_id" bson:"workspace_id"`
+	UserID          string                  `json:"user_id" bson:"user_id"` // Member who created the transaction
+	AccountID       string                  `json:"account_id" bson:"account_id" binding:"required"`
+	ToAccountID     *string                 `json:"to_account_id,omitempty" bson:"to_account_id,omitempty"` // For transfer transactions
+	CategoryID      *string                 `json:"category_id,omitempty" bson:"category_id,omitempty"`     // Optional for transfers
+	Type            string                  `json:"type" bson:"type" binding:"required"`                    // income, expense, transfer
+	Amount          float64                 `json:"amount" bson:"amount" binding:"required,gt=0"`
+	Merchant        *string                 `json:"merchant,omitempty" bson:"merchant,omitempty"` // Merchant/Payee name
+	PayeeID         *string                 `json:"payee_id,omitempty" bson:"payee_id,omitempty"` // Payee the merchant text was linked to
+	Description     *string                 `json:"description,omitempty" bson:"description,omitempty"`
+	TransactionDate time.Time               `json:"transaction_date" bson:"transaction_date" binding:"required"`
+	Notes           *string                 `json:"notes,omitempty" bson:"notes,omitempty"`
+	Tags            []string                `json:"tags,omitempty" bson:"tags,omitempty"` // Tags for categorization
+	AttachmentURL   *string                 `json:"attachment_url,omitempty" bson:"attachment_url,omitempty"`
+	Attachments     []Attachment            `json:"attachments,omitempty" bson:"-"`                       // Files stored for the transaction, loaded for single transactions
+	ThumbnailURL    *string                 `json:"thumbnail_url,omitempty" bson:"-"`                     // Thumbnail of the first image attachment, loaded for lists
+	RefundOfID      *string                 `json:"refund_of_id,omitempty" bson:"refund_of_id,omitempty"` // Expense this income refunds or reimburses
+	RefundKind      string                  `json:"refund_kind,omitempty" bson:"refund_kind,omitempty"`   // refund, reimbursement
+	RefundOfDate    *time.Time              `json:"-" bson:"refund_of_date,omitempty"`                    // Date of the original expense; reports and budgets count the refund there
+	Refunds         []RefundLink            `json:"refunds,omitempty" bson:"-"`                           // Refunds and reimbursements of this expense
+	RefundedAmount  float64                 `json:"refunded_amount,omitempty" bson:"-"`
+	LinkedTo        *TransactionLink        `json:"linked_to,omitempty" bson:"linked_to,omitempty"`   // Record that created the transaction and owns it
+	Status          string                  `json:"status" bson:"status,omitempty"`                   // pending, cleared, reconciled (missing means pending)
+	Search          *TransactionSearchIndex `json:"-" bson:"search,omitempty"`                        // Normalized copies of the searchable fields
+	DeletedAt       *time.Time              `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"` // Set while the transaction is in the trash
+	CreatedAt       time.Time               `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at" bson:"updated_at"`
 }
 
 // Budget represents a budget
 type Budget struct {
 	ID             string    `json:"id" bson:"_id,omitempty"`
-	UserID         string    `json:"user_id" bson:"user_id"`
+	WorkspaceID    string    `json:"workspace_id" bson:"workspace_id"`
+	UserID         string    `json:"user_id" bson:"user_id"` // Member who created the budget
 	Month          string    `json:"month" bson:"month" binding:"required"` // YYYY-MM format
 	Scope          string    `json:"scope" bson:"scope" binding:"required,oneof=total category"`
 	CategoryID     *string   `json:"category_id,omitempty" bson:"category_id,omitempty"` // Optional, only for category scope
@@ -119,7 +168,7 @@ type Budget struct {
 // CreateAccountRequest represents request to create an account
 type CreateAccountRequest struct {
 	Name                string   `json:"name" binding:"required,min=1,max=100"`
-	Type                string   `json:"type" binding:"required,oneof=cash bank credit"`
+	Type                string   `json:"type" binding:"required,oneof=cash bank credit loan investment"`
 	Balance             float64  `json:"balance"`
 	Currency            string   `json:"currency" binding:"required"`
 	Icon                *string  `json:"icon,omitempty"`
@@ -137,6 +186,14 @@ type CreateAccountRequest struct {
 	StatementDate       *int     `json:"statement_date,omitempty" binding:"omitempty,min=1,max=31"`
 	DueDate             *int     `json:"due_date,omitempty" binding:"omitempty,min=1,max=31"`
 	
+	// Loan fields
+	LoanDirection       *string    `json:"loan_direction,omitempty" binding:"omitempty,oneof=borrowed lent"`
+	Principal           *float64   `json:"principal,omitempty" binding:"omitempty,gt=0"`
+	InterestRate        *float64   `json:"interest_rate,omitempty" binding:"omitempty,min=0"`
+	TermPeriods         *int       `json:"term_periods,omitempty" binding:"omitempty,min=1"`
+	PaymentFrequency    *string    `json:"payment_frequency,omitempty" binding:"omitempty,oneof=weekly biweekly monthly quarterly"`
+	LoanStartDate       *time.Time `json:"loan_start_date,omitempty"`
+	
 	IsExcludedFromTotal bool     `json:"is_excluded_from_total"`
 	DisplayOrder        *int     `json:"display_order,omitempty"`
 }
@@ -160,6 +217,11 @@ type UpdateAccountRequest struct {
 	StatementDate       *int     `json:"statement_date,omitempty" binding:"omitempty,min=1,max=31"`
 	DueDate             *int     `json:"due_date,omitempty" binding:"omitempty,min=1,max=31"`
 	
+	// Loan fields
+	InterestRate        *float64   `json:"interest_rate,omitempty" binding:"omitempty,min=0"`
+	TermPeriods         *int       `json:"term_periods,omitempty" binding:"omitempty,min=1"`
+	PaymentFrequency    *string    `json:"payment_frequency,omitempty" binding:"omitempty,oneof=weekly biweekly monthly quarterly"`
+	
 	IsActive            *bool    `json:"is_active,omitempty"`
 	IsExcludedFromTotal *bool    `json:"is_excluded_from_total,omitempty"`
 	DisplayOrder        *int     `json:"display_order,omitempty"`
@@ -171,6 +233,9 @@ type AccountSummary struct {
 	TotalBalance        float64 `json:"total_balance"`
 	TotalIncome         float64 `json:"total_income"`
 	TotalExpense        float64 `json:"total_expense"`
+	TotalAssets         float64 `json:"total_assets"`
+	TotalLiabilities    float64 `json:"total_liabilities"`
+	InvestmentValue     float64 `json:"investment_value"` // Market value of holdings in investment accounts
 	NetWorth            float64 `json:"net_worth"`
 	AccountsByType      map[string]int `json:"accounts_by_type"`
 }
@@ -192,11 +257,21 @@ type CreateTransactionRequest struct {
 	Type            string    `json:"type" binding:"required,oneof=income expense transfer"`
 	Amount          float64   `json:"amount" binding:"required,gt=0"`
 	Merchant        *string   `json:"merchant,omitempty"`
+	PayeeID         *string   `json:"payee_id,omitempty"` // Linked from the merchant when omitted
 	Description     *string   `json:"description,omitempty"`
 	TransactionDate time.Time `json:"transaction_date" binding:"required"`
 	Notes           *string   `json:"notes,omitempty"`
 	Tags            []string  `json:"tags,omitempty"`
 	AttachmentURL   *string   `json:"attachment_url,omitempty"`
+	AttachmentIDs   []string  `json:"attachment_ids,omitempty"`                                             // Uploads from POST /uploads/attachment to attach
+	RefundOfID      *string   `json:"refund_of_id,omitempty"`                                               // Original expense; the transaction must be income and takes the expense's category
+	RefundKind      string    `json:"refund_kind,omitempty" binding:"omitempty,oneof=refund reimbursement"` // Defaults to refund when refund_of_id is set
+	RefundOfDate    time.Time `json:"-"`                                                                    // Set from the original expense
+	Status          string    `json:"status,omitempty" binding:"omitempty,oneof=pending cleared"`           // Defaults to pending
+	Source          string    `json:"-"`                                                                    // Audit source, set by the code creating the transaction (defaults to api)
+
+	// Record creating the transaction, set by its service so the transaction is only changed through that record
+	LinkedTo *TransactionLink `json:"-"`
 }
 
 // UpdateTransactionRequest represents request to update a transaction
@@ -207,11 +282,15 @@ type UpdateTransactionRequest struct {
 	Type            *string    `json:"type,omitempty" binding:"omitempty,oneof=income expense transfer"`
 	Amount          *float64   `json:"amount,omitempty" binding:"omitempty,gt=0"`
 	Merchant        *string    `json:"merchant,omitempty"`
+	PayeeID         *string    `json:"payee_id,omitempty"` // Relinked from the merchant when only the merchant changes; "" unlinks
 	Description     *string    `json:"description,omitempty"`
 	TransactionDate *time.Time `json:"transaction_date,omitempty"`
 	Notes           *string    `json:"notes,omitempty"`
 	Tags            []string   `json:"tags,omitempty"`
 	AttachmentURL   *string    `json:"attachment_url,omitempty"`
+	Status          *string    `json:"status,omitempty" binding:"omitempty,oneof=pending cleared"` // reconciled is only set by finishing a reconciliation
+	Source          string     `json:"-"`                                                          // Audit source, set by the code updating the transaction (defaults to api)
+	RevertedTo      *int       `json:"-"`                                                          // Set when the update restores an earlier version
 }
 
 // TransactionFilterQuery represents filter parameters for transaction queries
@@ -219,16 +298,49 @@ type TransactionFilterQuery struct {
 	PaginationQuery
 	AccountID  string `form:"account_id"`
 	CategoryID string `form:"category_id"`
+	PayeeID    string `form:"payee_id"`
 	Type       string `form:"type" binding:"omitempty,oneof=income expense transfer"`
-	Search     string `form:"search"`     // Search in merchant, description, notes
+	Status     string `form:"status" binding:"omitempty,oneof=pending cleared reconciled"`
+	Search     string `form:"search"`     // Search query, e.g. merchant:grab amount>100000 tag:work -category:food
 	StartDate  string `form:"start_date"` // YYYY-MM-DD
 	EndDate    string `form:"end_date"`   // YYYY-MM-DD
 	MinAmount  string `form:"min_amount"`
 	MaxAmount  string `form:"max_amount"`
-	Month      string `form:"month"`      // YYYY-MM (filter by month)
-	Tags       string `form:"tags"`       // Comma-separated tags
+	Month      string `form:"month"` // YYYY-MM (filter by month)
+	Tags       string `form:"tags"`  // Comma-separated tags
+	TagMode    string `form:"tag_mode" binding:"omitempty,oneof=any all none"`
 	SortBy     string `form:"sort_by" binding:"omitempty,oneof=date amount"`
 	SortOrder  string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+
+	// Cursor pagination: set pagination=cursor for the first page, then pass back next_cursor
+	Pagination   string `form:"pagination" binding:"omitempty,oneof=page cursor"`
+	Cursor       string `form:"cursor"`
+	IncludeTotal bool   `form:"include_total"` // Count matching transactions in cursor mode (slower)
+
+	SearchTerms []SearchTerm `form:"-"` // Parsed from Search, with category and account names resolved
+}
+
+// UsesCursor reports whether the query asks for cursor pagination
+func (q *TransactionFilterQuery) UsesCursor() bool {
+	return q.Pagination == "cursor" || q.Cursor != ""
+}
+
+// HasConditions reports whether the query narrows down the transactions at all
+func (q *TransactionFilterQuery) HasConditions() bool {
+	return q.AccountID != "" || q.CategoryID != "" || q.PayeeID != "" || q.Type != "" || q.Status != "" ||
+		q.Search != "" || q.StartDate != "" || q.EndDate != "" || q.MinAmount != "" || q.MaxAmount != "" ||
+		q.Month != "" || q.Tags != ""
+}
+
+// TransactionCursor represents the position after the last transaction of a cursor page
+// It is handed to clients as an opaque base64 token
+type TransactionCursor struct {
+	SortBy    string     `json:"s"`
+	SortOrder string     `json:"o"`
+	Date      *time.Time `json:"d,omitempty"`
+	Amount    *float64   `json:"a,omitempty"`
+	CreatedAt time.Time  `json:"c"`
+	ID        string     `json:"i"`
 }
 
 // BulkUpdateCategoryRequest represents request to update category for multiple transactions
@@ -242,6 +354,35 @@ type BulkDeleteRequest struct {
 	TransactionIDs []string `json:"transaction_ids" binding:"required,min=1"`
 }
 
+// BulkEditRequest represents request to apply the same changes to many transactions
+// Transactions are picked by transaction_ids or, when none are given, by the list filters in the query string.
+type BulkEditRequest struct {
+	TransactionIDs []string `json:"transaction_ids,omitempty"`
+	CategoryID     *string  `json:"category_id,omitempty"`
+	AccountID      *string  `json:"account_id,omitempty"` // Moves the amounts between the account balances
+	AddTags        []string `json:"add_tags,omitempty"`
+	RemoveTags     []string `json:"remove_tags,omitempty"`
+	ShiftDays      int      `json:"shift_days,omitempty"` // Moves transaction dates by this many days, negative moves them back
+	Merchant       *string  `json:"merchant,omitempty"`   // Relinks the payee; "" clears the merchant
+	DryRun         bool     `json:"dry_run"`              // Validate and count without changing anything
+}
+
+// BulkEditResult represents the outcome of a bulk edit
+type BulkEditResult struct {
+	DryRun    bool              `json:"dry_run"`
+	Matched   int               `json:"matched"`   // Transactions selected by the IDs or filter
+	Affected  int               `json:"affected"`  // Transactions updated, or that would be updated in a dry run
+	Unchanged int               `json:"unchanged"` // Transactions that already had the requested values
+	Failed    int               `json:"failed"`
+	Failures  []BulkEditFailure `json:"failures"`
+}
+
+// BulkEditFailure represents a transaction a bulk edit could not be applied to
+type BulkEditFailure struct {
+	TransactionID string `json:"transaction_id"`
+	Error         string `json:"error"`
+}
+
 // TransactionWithDetails represents a transaction with populated account and category details
 type TransactionWithDetails struct {
 	Transaction
@@ -293,6 +434,49 @@ type CategoryUsageResponse struct {
 	ChildCount      int   `json:"child_count"`
 }
 
+// MergeCategoryResult represents the outcome of merging a category into another one
+type MergeCategoryResult struct {
+	Category            *Category `json:"category"` // Target the category was merged into
+	TransactionsUpdated int64     `json:"transactions_updated"`
+	ChildrenMoved       int64     `json:"children_moved"`
+	BudgetsMoved        int       `json:"budgets_moved"`
+	BudgetsMerged       int       `json:"budgets_merged"` // Added to a budget the target already had for the month
+}
+
+// CategoryTemplate represents the default categories of a locale
+type CategoryTemplate struct {
+	Locale     string                 `json:"locale"`
+	Categories []CategoryTemplateItem `json:"categories"`
+}
+
+// CategoryTemplateItem represents a category of a template, with its sub-categories
+type CategoryTemplateItem struct {
+	Key      string                 `json:"key"`
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type"`
+	Icon     string                 `json:"icon"`
+	Color    string                 `json:"color"`
+	Children []CategoryTemplateItem `json:"children,omitempty"`
+}
+
+// ResetCategoriesRequest represents request to reset the categories of a workspace to the defaults
+type ResetCategoriesRequest struct {
+	Locale string `json:"locale" binding:"omitempty,oneof=vi en"` // Names of default categories that are missing, defaults to vi
+}
+
+// CategoryResetResult represents the result of resetting categories to the defaults
+type CategoryResetResult struct {
+	DeletedCount int        `json:"deleted_count"` // Custom categories deleted
+	Created      []Category `json:"created"`       // Default categories that were missing
+}
+
+// CategoryImportResult represents the result of importing a category template
+type CategoryImportResult struct {
+	Locale       string     `json:"locale"`
+	Created      []Category `json:"created"`
+	SkippedCount int        `json:"skipped_count"` // Template categories the workspace already has under the same name
+}
+
 // CreateBudgetRequest represents request to create or update a budget
 type CreateBudgetRequest struct {
 	Month          string  `json:"month" binding:"required"` // YYYY-MM format
@@ -319,8 +503,8 @@ type BudgetWithCategory struct {
 
 // PaginationQuery represents pagination parameters
 type PaginationQuery struct {
-	Page  int `form:"page" binding:"min=1"`
-	Limit int `form:"limit" binding:"min=1,max=100"`
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
 }
 
 // SetDefaults sets default values for pagination
@@ -370,7 +554,9 @@ type CategoryReport struct {
 
 // MerchantReport represents expenses grouped by merchant
 type MerchantReport struct {
-	Merchant         string  `json:"merchant"`
+	Merchant         string  `json:"merchant"` // Payee name, or the merchant text for unlinked transactions
+	PayeeID          *string `json:"payee_id,omitempty"`
+	Logo             *string `json:"logo,omitempty"`
 	Amount           float64 `json:"amount"`
 	TransactionCount int     `json:"transaction_count"`
 	Percentage       float64 `json:"percentage"`
@@ -408,6 +594,15 @@ type PaginatedResponse struct {
 	TotalPages int         `json:"total_pages"`
 }
 
+// CursorPaginatedResponse represents a page of results fetched with a cursor
+type CursorPaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	Limit      int         `json:"limit"`
+	NextCursor *string     `json:"next_cursor"` // Nil on the last page
+	HasMore    bool        `json:"has_more"`
+	TotalItems *int        `json:"total_items,omitempty"` // Only when include_total is set
+}
+
 // Auth DTOs
 
 // RegisterRequest represents user registration request
@@ -416,6 +611,7 @@ type RegisterRequest struct {
 	Password        string `json:"password" binding:"required,min=8"`
 	ConfirmPassword string `json:"confirm_password" binding:"required"`
 	FullName        string `json:"full_name" binding:"required"`
+	Locale          string `json:"locale,omitempty" binding:"omitempty,oneof=vi en"` // Language of the profile and the default categories, defaults to vi
 }
 
 // LoginRequest represents user login request
@@ -461,6 +657,17 @@ type ChangePasswordRequest struct {
 	NewPassword string `json:"new_password" binding:"required,min=8"`
 }
 
+// UpdateProfileRequest represents request to update the current user's profile
+// Omitted fields are left unchanged; preferences are merged field by field.
+type UpdateProfileRequest struct {
+	FullName     *string          `json:"full_name" binding:"omitempty,min=1,max=100"`
+	AvatarURL    *string          `json:"avatar_url" binding:"omitempty,url,startswith=https://"` // External image URL; upload files through POST /uploads/avatar
+	RemoveAvatar bool             `json:"remove_avatar"`                                          // Back to the Google picture, or initials
+	Locale       *string          `json:"locale" binding:"omitempty,oneof=vi en"`
+	Timezone     *string          `json:"timezone" binding:"omitempty,max=64"`
+	Preferences  *UserPreferences `json:"preferences"`
+}
+
 // ResetPasswordRequest represents reset password request
 type ResetPasswordRequest struct {
 	Email string `json:"email" binding:"required,email"`
@@ -487,3 +694,1023 @@ type VerificationToken struct {
 type VerifyEmailRequest struct {
 	Token string `json:"token" binding:"required"`
 }
+
+// Goal Types
+
+// Goal represents a savings goal
+type Goal struct {
+	ID           string     `json:"id" bson:"_id,omitempty"`
+	WorkspaceID  string     `json:"workspace_id" bson:"workspace_id"` // Workspace of the linked accounts
+	UserID       string     `json:"user_id" bson:"user_id"`
+	Name         string     `json:"name" bson:"name"`
+	TargetAmount float64    `json:"target_amount" bson:"target_amount"`
+	TargetDate   *time.Time `json:"target_date,omitempty" bson:"target_date,omitempty"`
+	AccountID    *string    `json:"account_id,omitempty" bson:"account_id,omitempty"` // Linked account, nil for a virtual envelope
+	Icon         *string    `json:"icon,omitempty" bson:"icon,omitempty"`
+	Color        *string    `json:"color,omitempty" bson:"color,omitempty"`
+	Status       string     `json:"status" bson:"status"` // active, completed, archived
+	CreatedAt    time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// GoalContribution represents money put toward a goal
+type GoalContribution struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	UserID        string    `json:"user_id" bson:"user_id"`
+	GoalID        string    `json:"goal_id" bson:"goal_id"`
+	Method        string    `json:"method" bson:"method"` // transfer, allocation
+	Amount        float64   `json:"amount" bson:"amount"`
+	FromAccountID *string   `json:"from_account_id,omitempty" bson:"from_account_id,omitempty"`
+	TransactionID *string   `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"` // Set for transfer contributions
+	Notes         *string   `json:"notes,omitempty" bson:"notes,omitempty"`
+	ContributedAt time.Time `json:"contributed_at" bson:"contributed_at"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// CreateGoalRequest represents request to create a goal
+type CreateGoalRequest struct {
+	Name         string     `json:"name" binding:"required,min=1,max=100"`
+	TargetAmount float64    `json:"target_amount" binding:"required,gt=0"`
+	TargetDate   *time.Time `json:"target_date,omitempty"`
+	AccountID    *string    `json:"account_id,omitempty"`
+	Icon         *string    `json:"icon,omitempty"`
+	Color        *string    `json:"color,omitempty"`
+}
+
+// UpdateGoalRequest represents request to update a goal
+type UpdateGoalRequest struct {
+	Name         *string    `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	TargetAmount *float64   `json:"target_amount,omitempty" binding:"omitempty,gt=0"`
+	TargetDate   *time.Time `json:"target_date,omitempty"`
+	Icon         *string    `json:"icon,omitempty"`
+	Color        *string    `json:"color,omitempty"`
+	Status       *string    `json:"status,omitempty" binding:"omitempty,oneof=active completed archived"`
+}
+
+// CreateGoalContributionRequest represents request to contribute to a goal
+type CreateGoalContributionRequest struct {
+	Method        string     `json:"method" binding:"omitempty,oneof=transfer allocation"`
+	Amount        float64    `json:"amount" binding:"required,gt=0"`
+	FromAccountID *string    `json:"from_account_id,omitempty"` // Required for transfer contributions
+	Notes         *string    `json:"notes,omitempty"`
+	ContributedAt *time.Time `json:"contributed_at,omitempty"`
+}
+
+// GoalProgress represents a goal with computed progress
+type GoalProgress struct {
+	Goal
+	SavedAmount                 float64    `json:"saved_amount"`
+	RemainingAmount             float64    `json:"remaining_amount"`
+	Percentage                  float64    `json:"percentage"`
+	ContributionCount           int        `json:"contribution_count"`
+	AvgMonthlyContribution      float64    `json:"avg_monthly_contribution"`
+	RequiredMonthlyContribution *float64   `json:"required_monthly_contribution,omitempty"` // Only when target_date is set
+	AvgMonthlySaving            float64    `json:"avg_monthly_saving"`                      // From overview report
+	ProjectedCompletionDate     *time.Time `json:"projected_completion_date,omitempty"`
+	OnTrack                     *bool      `json:"on_track,omitempty"`
+}
+
+// Loan Types
+
+// LoanPayment represents a single repayment of a loan account
+type LoanPayment struct {
+	ID                     string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID            string    `json:"workspace_id" bson:"workspace_id"`
+	UserID                 string    `json:"user_id" bson:"user_id"`
+	AccountID              string    `json:"account_id" bson:"account_id"`                 // Loan account
+	PaymentAccountID       string    `json:"payment_account_id" bson:"payment_account_id"` // Account the money is paid from or received into
+	Amount                 float64   `json:"amount" bson:"amount"`
+	Principal              float64   `json:"principal" bson:"principal"`
+	Interest               float64   `json:"interest" bson:"interest"`
+	PrincipalTransactionID *string   `json:"principal_transaction_id,omitempty" bson:"principal_transaction_id,omitempty"`
+	InterestTransactionID  *string   `json:"interest_transaction_id,omitempty" bson:"interest_transaction_id,omitempty"`
+	RemainingBalance       float64   `json:"remaining_balance" bson:"remaining_balance"`
+	PaymentDate            time.Time `json:"payment_date" bson:"payment_date"`
+	CreatedAt              time.Time `json:"created_at" bson:"created_at"`
+}
+
+// CreateLoanPaymentRequest represents request to record a loan payment
+type CreateLoanPaymentRequest struct {
+	PaymentAccountID string     `json:"payment_account_id" binding:"required"`
+	Amount           *float64   `json:"amount,omitempty" binding:"omitempty,gt=0"` // Defaults to the scheduled payment
+	CategoryID       *string    `json:"category_id,omitempty"`                     // Category for the interest portion
+	PaymentDate      *time.Time `json:"payment_date,omitempty"`
+	Notes            *string    `json:"notes,omitempty"`
+}
+
+// AmortizationEntry represents one period of an amortization schedule
+type AmortizationEntry struct {
+	Period           int       `json:"period"`
+	DueDate          time.Time `json:"due_date"`
+	Payment          float64   `json:"payment"`
+	Principal        float64   `json:"principal"`
+	Interest         float64   `json:"interest"`
+	RemainingBalance float64   `json:"remaining_balance"`
+}
+
+// LoanSchedule represents the amortization schedule and current status of a loan
+type LoanSchedule struct {
+	AccountID        string              `json:"account_id"`
+	Direction        string              `json:"direction"`
+	Principal        float64             `json:"principal"`
+	InterestRate     float64             `json:"interest_rate"`
+	TermPeriods      int                 `json:"term_periods"`
+	PaymentFrequency string              `json:"payment_frequency"`
+	PeriodicPayment  float64             `json:"periodic_payment"`
+	TotalInterest    float64             `json:"total_interest"`
+	RemainingBalance float64             `json:"remaining_balance"`
+	PrincipalPaid    float64             `json:"principal_paid"`
+	InterestPaid     float64             `json:"interest_paid"`
+	PaymentsMade     int                 `json:"payments_made"`
+	NextPaymentDate  *time.Time          `json:"next_payment_date,omitempty"`
+	PayoffDate       *time.Time          `json:"payoff_date,omitempty"`
+	Schedule         []AmortizationEntry `json:"schedule"`
+}
+
+// Shared Expense Types
+
+// SelfParticipantID identifies the current user among shared expense participants
+const SelfParticipantID = "self"
+
+// Contact represents a person the user shares expenses with
+type Contact struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID string    `json:"workspace_id" bson:"workspace_id"`
+	UserID      string    `json:"user_id" bson:"user_id"` // Member who added the contact
+	Name        string    `json:"name" bson:"name"`
+	Email       *string   `json:"email,omitempty" bson:"email,omitempty"`
+	Phone       *string   `json:"phone,omitempty" bson:"phone,omitempty"`
+	Notes       *string   `json:"notes,omitempty" bson:"notes,omitempty"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// ContactGroup represents a group of contacts sharing expenses, e.g. roommates or a trip
+type ContactGroup struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID string    `json:"workspace_id" bson:"workspace_id"`
+	UserID      string    `json:"user_id" bson:"user_id"` // Member who created the group
+	Name        string    `json:"name" bson:"name"`
+	ContactIDs  []string  `json:"contact_ids" bson:"contact_ids"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// ExpenseShare represents one participant's part of a shared expense
+type ExpenseShare struct {
+	ParticipantID string   `json:"participant_id" bson:"participant_id"` // Contact ID or "self"
+	Amount        float64  `json:"amount" bson:"amount"`
+	Percentage    *float64 `json:"percentage,omitempty" bson:"percentage,omitempty"` // Only for percentage splits
+}
+
+// SharedExpense represents an expense split between the user and contacts
+type SharedExpense struct {
+	ID            string         `json:"id" bson:"_id,omitempty"`
+	WorkspaceID   string         `json:"workspace_id" bson:"workspace_id"`
+	UserID        string         `json:"user_id" bson:"user_id"`                                   // Member who recorded the expense
+	TransactionID *string        `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"` // Set when the user paid
+	GroupID       *string        `json:"group_id,omitempty" bson:"group_id,omitempty"`
+	PaidBy        string         `json:"paid_by" bson:"paid_by"` // Contact ID or "self"
+	TotalAmount   float64        `json:"total_amount" bson:"total_amount"`
+	SplitType     string         `json:"split_type" bson:"split_type"` // equal, percentage, exact
+	Shares        []ExpenseShare `json:"shares" bson:"shares"`
+	Description   *string        `json:"description,omitempty" bson:"description,omitempty"`
+	ExpenseDate   time.Time      `json:"expense_date" bson:"expense_date"`
+	CreatedAt     time.Time      `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" bson:"updated_at"`
+}
+
+// Settlement represents a repayment between the user and a contact
+type Settlement struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID   string    `json:"workspace_id" bson:"workspace_id"` // Workspace of the settlement account
+	UserID        string    `json:"user_id" bson:"user_id"`
+	ContactID     string    `json:"contact_id" bson:"contact_id"`
+	GroupID       *string   `json:"group_id,omitempty" bson:"group_id,omitempty"`
+	Direction     string    `json:"direction" bson:"direction"` // received (contact paid the user), paid (user paid the contact)
+	Amount        float64   `json:"amount" bson:"amount"`
+	AccountID     string    `json:"account_id" bson:"account_id"`
+	TransactionID *string   `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+	SettledAt     time.Time `json:"settled_at" bson:"settled_at"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// CreateContactRequest represents request to create a contact
+type CreateContactRequest struct {
+	Name  string  `json:"name" binding:"required,min=1,max=100"`
+	Email *string `json:"email,omitempty" binding:"omitempty,email"`
+	Phone *string `json:"phone,omitempty"`
+	Notes *string `json:"notes,omitempty"`
+}
+
+// UpdateContactRequest represents request to update a contact
+type UpdateContactRequest struct {
+	Name  *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Email *string `json:"email,omitempty" binding:"omitempty,email"`
+	Phone *string `json:"phone,omitempty"`
+	Notes *string `json:"notes,omitempty"`
+}
+
+// CreateContactGroupRequest represents request to create a contact group
+type CreateContactGroupRequest struct {
+	Name       string   `json:"name" binding:"required,min=1,max=100"`
+	ContactIDs []string `json:"contact_ids" binding:"required,min=1"`
+}
+
+// UpdateContactGroupRequest represents request to update a contact group
+type UpdateContactGroupRequest struct {
+	Name       *string  `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	ContactIDs []string `json:"contact_ids,omitempty"`
+}
+
+// ExpenseShareRequest represents one participant in a split request
+type ExpenseShareRequest struct {
+	ParticipantID string   `json:"participant_id" binding:"required"`
+	Amount        *float64 `json:"amount,omitempty" binding:"omitempty,gte=0"`     // For exact splits
+	Percentage    *float64 `json:"percentage,omitempty" binding:"omitempty,gte=0"` // For percentage splits
+}
+
+// CreateSharedExpenseRequest represents request to share an expense
+type CreateSharedExpenseRequest struct {
+	TransactionID *string               `json:"transaction_id,omitempty"` // Share an existing expense paid by the user
+	GroupID       *string               `json:"group_id,omitempty"`
+	PaidBy        string                `json:"paid_by,omitempty"`      // Defaults to "self"
+	TotalAmount   *float64              `json:"total_amount,omitempty"` // Required without transaction_id
+	SplitType     string                `json:"split_type" binding:"required,oneof=equal percentage exact"`
+	Shares        []ExpenseShareRequest `json:"shares" binding:"required,min=1,dive"`
+	Description   *string               `json:"description,omitempty"`
+	ExpenseDate   *time.Time            `json:"expense_date,omitempty"`
+}
+
+// SettleUpRequest represents request to settle the balance with a contact
+type SettleUpRequest struct {
+	AccountID  string     `json:"account_id" binding:"required"`
+	CategoryID string     `json:"category_id" binding:"required"`
+	Amount     *float64   `json:"amount,omitempty" binding:"omitempty,gt=0"` // Defaults to the full balance
+	GroupID    *string    `json:"group_id,omitempty"`
+	SettledAt  *time.Time `json:"settled_at,omitempty"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// ContactBalance represents how much a contact owes the user (negative when the user owes them)
+type ContactBalance struct {
+	ContactID   string  `json:"contact_id"`
+	ContactName string  `json:"contact_name"`
+	Balance     float64 `json:"balance"`
+}
+
+// ContactLedgerEntry represents one balance-changing event with a contact
+type ContactLedgerEntry struct {
+	Type           string    `json:"type"` // expense, settlement
+	ReferenceID    string    `json:"reference_id"`
+	Description    *string   `json:"description,omitempty"`
+	Date           time.Time `json:"date"`
+	Amount         float64   `json:"amount"` // Positive increases what the contact owes
+	RunningBalance float64   `json:"running_balance"`
+}
+
+// ContactLedger represents a contact balance with its running history
+type ContactLedger struct {
+	ContactBalance
+	Entries []ContactLedgerEntry `json:"entries"`
+}
+
+// SimplifiedDebt represents one payment in a simplified group settlement plan
+type SimplifiedDebt struct {
+	From     string  `json:"from"`
+	FromName string  `json:"from_name"`
+	To       string  `json:"to"`
+	ToName   string  `json:"to_name"`
+	Amount   float64 `json:"amount"`
+}
+
+// Workspace Types
+
+// PersonalWorkspaceName is the name of the workspace every user owns.
+// A personal workspace shares its owner's user ID, so data created before workspaces existed
+// and personal features such as goals, loans and shared expenses resolve to it
+const PersonalWorkspaceName = "Personal"
+
+// Workspace member roles
+const (
+	WorkspaceRoleOwner  = "owner"  // Full access, manages members
+	WorkspaceRoleEditor = "editor" // Reads and writes workspace data
+	WorkspaceRoleViewer = "viewer" // Read-only access
+)
+
+// Workspace represents a household that shares accounts, categories, budgets and transactions
+type Workspace struct {
+	ID         string    `json:"id" bson:"_id,omitempty"`
+	Name       string    `json:"name" bson:"name"`
+	OwnerID    string    `json:"owner_id" bson:"owner_id"`
+	IsPersonal bool      `json:"is_personal" bson:"is_personal"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// WorkspaceMember represents a user invited to a workspace
+type WorkspaceMember struct {
+	ID          string     `json:"id" bson:"_id,omitempty"`
+	WorkspaceID string     `json:"workspace_id" bson:"workspace_id"`
+	UserID      string     `json:"user_id,omitempty" bson:"user_id,omitempty"` // Set once the invitation is accepted
+	Email       string     `json:"email" bson:"email"`
+	Role        string     `json:"role" bson:"role"`     // editor, viewer
+	Status      string     `json:"status" bson:"status"` // pending, active
+	InvitedBy   string     `json:"invited_by" bson:"invited_by"`
+	InvitedAt   time.Time  `json:"invited_at" bson:"invited_at"`
+	JoinedAt    *time.Time `json:"joined_at,omitempty" bson:"joined_at,omitempty"`
+}
+
+// WorkspaceWithRole represents a workspace together with the current user's role in it
+type WorkspaceWithRole struct {
+	Workspace
+	Role string `json:"role"`
+}
+
+// WorkspaceInvitation represents a pending invitation shown to the invited user
+type WorkspaceInvitation struct {
+	WorkspaceMember
+	WorkspaceName string `json:"workspace_name"`
+}
+
+// CreateWorkspaceRequest represents request to create a workspace
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// UpdateWorkspaceRequest represents request to update a workspace
+type UpdateWorkspaceRequest struct {
+	Name *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+}
+
+// InviteWorkspaceMemberRequest represents request to invite a user to a workspace by email
+type InviteWorkspaceMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+// UpdateWorkspaceMemberRequest represents request to change a member's role
+type UpdateWorkspaceMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+// Investment Types
+
+// Security represents a stock, fund certificate or other priced instrument held in investment accounts
+type Security struct {
+	ID            string     `json:"id" bson:"_id,omitempty"`
+	WorkspaceID   string     `json:"workspace_id" bson:"workspace_id"`
+	UserID        string     `json:"user_id" bson:"user_id"` // Member who created the security
+	Symbol        string     `json:"symbol" bson:"symbol"`   // Stored upper-case, unique per workspace
+	Name          string     `json:"name" bson:"name"`
+	Type          string     `json:"type" bson:"type"` // stock, fund, etf, bond, crypto, other
+	Currency      string     `json:"currency" bson:"currency"`
+	Exchange      *string    `json:"exchange,omitempty" bson:"exchange,omitempty"` // HOSE, HNX, UPCOM, ...
+	LastPrice     *float64   `json:"last_price,omitempty" bson:"last_price,omitempty"`
+	LastPriceDate *time.Time `json:"last_price_date,omitempty" bson:"last_price_date,omitempty"`
+	CreatedAt     time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// SecurityPrice represents a recorded price of a security on a given date
+type SecurityPrice struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID string    `json:"workspace_id" bson:"workspace_id"`
+	SecurityID  string    `json:"security_id" bson:"security_id"`
+	Price       float64   `json:"price" bson:"price"`
+	PriceDate   time.Time `json:"price_date" bson:"price_date"`
+	Source      string    `json:"source" bson:"source"` // manual, import
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+}
+
+// InvestmentTransaction represents a buy, sell, dividend or fee in an investment account
+type InvestmentTransaction struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID   string    `json:"workspace_id" bson:"workspace_id"`
+	UserID        string    `json:"user_id" bson:"user_id"` // Member who recorded the transaction
+	AccountID     string    `json:"account_id" bson:"account_id"`
+	SecurityID    *string   `json:"security_id,omitempty" bson:"security_id,omitempty"` // Optional for account-level fees
+	Type          string    `json:"type" bson:"type"`                                   // buy, sell, dividend, fee
+	Quantity      float64   `json:"quantity" bson:"quantity"`
+	Price         float64   `json:"price" bson:"price"`
+	Fee           float64   `json:"fee" bson:"fee"`                                           // Commission and taxes charged on a buy or sell
+	Amount        float64   `json:"amount" bson:"amount"`                                     // Net cash effect on the account, always positive
+	TransactionID *string   `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"` // Income or expense transaction booking the cash side
+	TradeDate     time.Time `json:"trade_date" bson:"trade_date"`
+	Notes         *string   `json:"notes,omitempty" bson:"notes,omitempty"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// CreateSecurityRequest represents request to create a security
+type CreateSecurityRequest struct {
+	Symbol    string     `json:"symbol" binding:"required,min=1,max=20"`
+	Name      string     `json:"name" binding:"required,min=1,max=200"`
+	Type      string     `json:"type" binding:"required,oneof=stock fund etf bond crypto other"`
+	Currency  string     `json:"currency" binding:"required"`
+	Exchange  *string    `json:"exchange,omitempty"`
+	Price     *float64   `json:"price,omitempty" binding:"omitempty,gt=0"`
+	PriceDate *time.Time `json:"price_date,omitempty"`
+}
+
+// UpdateSecurityRequest represents request to update a security
+type UpdateSecurityRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
+	Type     *string `json:"type,omitempty" binding:"omitempty,oneof=stock fund etf bond crypto other"`
+	Exchange *string `json:"exchange,omitempty"`
+}
+
+// UpdateSecurityPriceRequest represents request to record a security price manually
+type UpdateSecurityPriceRequest struct {
+	Price     float64    `json:"price" binding:"required,gt=0"`
+	PriceDate *time.Time `json:"price_date,omitempty"` // Defaults to now
+}
+
+// PriceImportResult represents the outcome of a CSV price import
+type PriceImportResult struct {
+	Imported int                `json:"imported"`
+	Skipped  int                `json:"skipped"`
+	Errors   []PriceImportError `json:"errors"`
+}
+
+// PriceImportError describes a CSV row that could not be imported
+type PriceImportError struct {
+	Line    int    `json:"line"`
+	Symbol  string `json:"symbol,omitempty"`
+	Message string `json:"message"`
+}
+
+// CreateInvestmentTransactionRequest represents request to record an investment transaction
+type CreateInvestmentTransactionRequest struct {
+	Type       string     `json:"type" binding:"required,oneof=buy sell dividend fee"`
+	SecurityID *string    `json:"security_id,omitempty"`                       // Required for buy, sell and dividend
+	Quantity   *float64   `json:"quantity,omitempty" binding:"omitempty,gt=0"` // Required for buy and sell
+	Price      *float64   `json:"price,omitempty" binding:"omitempty,gt=0"`    // Required for buy and sell
+	Fee        *float64   `json:"fee,omitempty" binding:"omitempty,min=0"`     // Commission and taxes on a buy or sell
+	Amount     *float64   `json:"amount,omitempty" binding:"omitempty,gt=0"`   // Required for dividend and fee
+	CategoryID *string    `json:"category_id,omitempty"`                       // Category of the cash transaction the trade books
+	TradeDate  *time.Time `json:"trade_date,omitempty"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// InvestmentLot represents an open FIFO lot of a holding
+type InvestmentLot struct {
+	TransactionID string    `json:"transaction_id"`
+	TradeDate     time.Time `json:"trade_date"`
+	Quantity      float64   `json:"quantity"`  // Quantity still open
+	UnitCost      float64   `json:"unit_cost"` // Purchase price plus allocated fees
+	CostBasis     float64   `json:"cost_basis"`
+}
+
+// Holding represents the position in one security of an investment account
+type Holding struct {
+	Security              Security        `json:"security"`
+	Quantity              float64         `json:"quantity"`
+	CostBasis             float64         `json:"cost_basis"`
+	AverageCost           float64         `json:"average_cost"`
+	Price                 *float64        `json:"price,omitempty"` // Nil when the security has never been priced
+	PriceDate             *time.Time      `json:"price_date,omitempty"`
+	MarketValue           float64         `json:"market_value"` // Falls back to cost basis when unpriced
+	UnrealizedGain        float64         `json:"unrealized_gain"`
+	UnrealizedGainPercent float64         `json:"unrealized_gain_percent"`
+	RealizedGain          float64         `json:"realized_gain"`
+	Dividends             float64         `json:"dividends"`
+	Lots                  []InvestmentLot `json:"lots"`
+}
+
+// Portfolio represents the holdings and performance of an investment account
+type Portfolio struct {
+	AccountID      string    `json:"account_id"`
+	CashBalance    float64   `json:"cash_balance"`
+	MarketValue    float64   `json:"market_value"`
+	CostBasis      float64   `json:"cost_basis"`
+	UnrealizedGain float64   `json:"unrealized_gain"`
+	RealizedGain   float64   `json:"realized_gain"`
+	Dividends      float64   `json:"dividends"`
+	Fees           float64   `json:"fees"`
+	TotalValue     float64   `json:"total_value"` // Cash balance plus market value
+	Holdings       []Holding `json:"holdings"`
+}
+
+// Search Types
+
+// Search query fields
+const (
+	SearchFieldMerchant    = "merchant"
+	SearchFieldDescription = "description"
+	SearchFieldNotes       = "notes"
+	SearchFieldTag         = "tag"
+	SearchFieldCategory    = "category"
+	SearchFieldAccount     = "account"
+	SearchFieldType        = "type"
+	SearchFieldAmount      = "amount"
+	SearchFieldDate        = "date"
+)
+
+// SearchTerm represents one condition of a parsed transaction search query
+type SearchTerm struct {
+	Field    string   // Empty for free text
+	Operator string   // :, =, >, >=, <, <=
+	Value    string   // Normalized value
+	Values   []string // IDs matching a category or account name
+	Negated  bool
+}
+
+// TransactionSearchIndex holds the lower-cased, diacritic-free searchable fields of a transaction
+type TransactionSearchIndex struct {
+	Text        string   `bson:"text"` // Merchant, description, notes and tags combined for the text index
+	Merchant    string   `bson:"merchant,omitempty"`
+	Description string   `bson:"description,omitempty"`
+	Notes       string   `bson:"notes,omitempty"`
+	Tags        []string `bson:"tags,omitempty"`
+}
+
+// Audit Types
+
+// Audited entity types
+const (
+	AuditEntityTransaction = "transaction"
+	AuditEntityAccount     = "account"
+	AuditEntityCategory    = "category"
+	AuditEntityBudget      = "budget"
+)
+
+// Audit actions
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+	AuditActionRevert  = "revert"
+)
+
+// Sources a change can come from
+const (
+	AuditSourceAPI       = "api"
+	AuditSourceImport    = "import"
+	AuditSourceRecurring = "recurring"
+	AuditSourceRule      = "rule"
+	AuditSourceRetention = "retention" // Trash purged after the retention period, with no actor
+)
+
+// AuditEntry represents one versioned change to a transaction, account, category or budget
+type AuditEntry struct {
+	ID          string                 `json:"id" bson:"_id,omitempty"`
+	WorkspaceID string                 `json:"workspace_id" bson:"workspace_id"`
+	EntityType  string                 `json:"entity_type" bson:"entity_type"` // transaction, account, category, budget
+	EntityID    string                 `json:"entity_id" bson:"entity_id"`
+	Version     int                    `json:"version" bson:"version"`   // Starts at 1 and increases with every change of the entity
+	Action      string                 `json:"action" bson:"action"`     // create, update, delete, restore, purge, revert
+	ActorID     string                 `json:"actor_id" bson:"actor_id"` // User who made the change
+	Source      string                 `json:"source" bson:"source"`     // api, import, recurring, rule, retention
+	Changes     []AuditFieldChange     `json:"changes" bson:"changes"`
+	Snapshot    map[string]interface{} `json:"snapshot,omitempty" bson:"snapshot,omitempty"`       // Entity state after the change (before it for deletes)
+	RevertedTo  *int                   `json:"reverted_to,omitempty" bson:"reverted_to,omitempty"` // Version restored by a revert
+	CreatedAt   time.Time              `json:"created_at" bson:"created_at"`
+}
+
+// AuditFieldChange represents the before and after value of one changed field
+type AuditFieldChange struct {
+	Field  string      `json:"field" bson:"field"`
+	Before interface{} `json:"before" bson:"before"`
+	After  interface{} `json:"after" bson:"after"`
+}
+
+// RevertTransactionRequest represents request to revert a transaction to an earlier version
+type RevertTransactionRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// Reconciliation Types
+
+// Transaction statuses
+const (
+	TransactionStatusPending    = "pending"
+	TransactionStatusCleared    = "cleared"
+	TransactionStatusReconciled = "reconciled"
+)
+
+// Reconciliation session statuses
+const (
+	ReconciliationStatusInProgress = "in_progress"
+	ReconciliationStatusCompleted  = "completed"
+)
+
+// Reconciliation represents matching an account's transactions against a bank statement
+type Reconciliation struct {
+	ID               string     `json:"id" bson:"_id,omitempty"`
+	WorkspaceID      string     `json:"workspace_id" bson:"workspace_id"`
+	UserID           string     `json:"user_id" bson:"user_id"` // Member who started the reconciliation
+	AccountID        string     `json:"account_id" bson:"account_id"`
+	StatementDate    time.Time  `json:"statement_date" bson:"statement_date"`       // Last day covered by the statement
+	StatementBalance float64    `json:"statement_balance" bson:"statement_balance"` // Closing balance printed on the statement
+	Status           string     `json:"status" bson:"status"`                       // in_progress, completed
+	ReconciledCount  int        `json:"reconciled_count" bson:"reconciled_count"`   // Transactions locked when the reconciliation finished
+	CompletedAt      *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// CreateReconciliationRequest represents request to start reconciling an account
+type CreateReconciliationRequest struct {
+	StatementDate    time.Time `json:"statement_date" binding:"required"`
+	StatementBalance *float64  `json:"statement_balance" binding:"required"`
+}
+
+// ClearTransactionsRequest represents request to mark transactions cleared or pending during a reconciliation
+type ClearTransactionsRequest struct {
+	TransactionIDs []string `json:"transaction_ids" binding:"required,min=1"`
+	Cleared        bool     `json:"cleared"`
+}
+
+// ReconciliationProgress represents a reconciliation with its running totals
+type ReconciliationProgress struct {
+	Reconciliation
+	ClearedBalance float64       `json:"cleared_balance"` // Account balance counting only cleared and reconciled transactions up to the statement date
+	Difference     float64       `json:"difference"`      // Statement balance minus cleared balance; must be zero to finish
+	ClearedCount   int           `json:"cleared_count"`
+	PendingCount   int           `json:"pending_count"`
+	Transactions   []Transaction `json:"transactions"` // Unreconciled transactions up to the statement date
+}
+
+// Quick Add Types
+
+// ParseTransactionRequest represents request to turn a free-text line into a draft transaction
+type ParseTransactionRequest struct {
+	Text     string `json:"text" binding:"required,max=500"`
+	Timezone string `json:"timezone,omitempty"` // IANA zone for relative dates such as "hôm qua", defaults to Asia/Ho_Chi_Minh
+}
+
+// ParsedTransaction represents a draft transaction read from a quick-add line
+// The draft is not saved; the client reviews it and submits it to POST /transactions
+type ParsedTransaction struct {
+	Draft   CreateTransactionRequest `json:"draft"`
+	Missing []string                 `json:"missing"` // Required fields that could not be worked out: amount, account, category
+}
+
+// Bank Notification Types
+
+// Money directions reported by a bank notification
+const (
+	BankNotificationDirectionIn  = "in"
+	BankNotificationDirectionOut = "out"
+)
+
+// BankNotification represents a balance-change SMS or push notification turned into a transaction
+type BankNotification struct {
+	ID                string     `json:"id" bson:"_id,omitempty"`
+	WorkspaceID       string     `json:"workspace_id" bson:"workspace_id"`
+	UserID            string     `json:"user_id" bson:"user_id"` // Member whose device forwarded the notification
+	AccountID         string     `json:"account_id" bson:"account_id"`
+	TransactionID     string     `json:"transaction_id" bson:"transaction_id"`
+	BankCode          string     `json:"bank_code" bson:"bank_code"`
+	Text              string     `json:"text" bson:"text"`
+	TextHash          string     `json:"-" bson:"text_hash"`                                   // Detects the same notification forwarded twice
+	AccountHint       string     `json:"account_hint,omitempty" bson:"account_hint,omitempty"` // Account number as printed, often masked
+	Amount            float64    `json:"amount" bson:"amount"`
+	Direction         string     `json:"direction" bson:"direction"`                 // in, out
+	Balance           *float64   `json:"balance,omitempty" bson:"balance,omitempty"` // Balance reported by the bank after the transaction
+	OccurredAt        *time.Time `json:"occurred_at,omitempty" bson:"occurred_at,omitempty"`
+	Reference         *string    `json:"reference,omitempty" bson:"reference,omitempty"` // Transfer content or bank reference
+	AccountBalance    float64    `json:"account_balance" bson:"account_balance"`         // Account.Balance after the transaction was recorded
+	BalanceMismatch   bool       `json:"balance_mismatch" bson:"balance_mismatch"`
+	BalanceDifference float64    `json:"balance_difference" bson:"balance_difference"` // Bank balance minus tracked balance
+	CreatedAt         time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// IngestBankNotificationRequest represents request to record a transaction from a bank notification
+type IngestBankNotificationRequest struct {
+	Text       string  `json:"text" binding:"required,max=1000"`
+	AccountID  *string `json:"account_id,omitempty"`  // Skips matching the notification against the workspace's bank accounts
+	CategoryID *string `json:"category_id,omitempty"` // Guessed from the transfer content when omitted
+}
+
+// BankNotificationFilterQuery represents query parameters for listing bank notifications
+type BankNotificationFilterQuery struct {
+	AccountID  string `form:"account_id"`
+	Mismatched bool   `form:"mismatched"` // Only notifications whose balance disagreed with the account
+	Limit      int    `form:"limit"`
+}
+
+// Payee Types
+
+// Payee represents a merchant or counterparty that transactions are grouped under
+type Payee struct {
+	ID                string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID       string    `json:"workspace_id" bson:"workspace_id"`
+	UserID            string    `json:"user_id" bson:"user_id"` // Member who created the payee
+	Name              string    `json:"name" bson:"name"`       // Canonical name shown in reports
+	NormalizedName    string    `json:"-" bson:"normalized_name"`
+	Aliases           []string  `json:"aliases" bson:"aliases"`                                             // Other spellings of the merchant, compared after normalization
+	Patterns          []string  `json:"patterns" bson:"patterns"`                                           // Regular expressions matched against the normalized merchant text
+	DefaultCategoryID *string   `json:"default_category_id,omitempty" bson:"default_category_id,omitempty"` // Fills in the category of linked transactions that have none
+	Logo              *string   `json:"logo,omitempty" bson:"logo,omitempty"`
+	CreatedAt         time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// CreatePayeeRequest represents request to create a payee
+type CreatePayeeRequest struct {
+	Name              string   `json:"name" binding:"required,max=100"`
+	Aliases           []string `json:"aliases,omitempty"`
+	Patterns          []string `json:"patterns,omitempty"`
+	DefaultCategoryID *string  `json:"default_category_id,omitempty"`
+	Logo              *string  `json:"logo,omitempty"`
+}
+
+// UpdatePayeeRequest represents request to update a payee
+type UpdatePayeeRequest struct {
+	Name              *string  `json:"name,omitempty" binding:"omitempty,max=100"`
+	Aliases           []string `json:"aliases,omitempty"`
+	Patterns          []string `json:"patterns,omitempty"`
+	DefaultCategoryID *string  `json:"default_category_id,omitempty"` // "" clears the default category
+	Logo              *string  `json:"logo,omitempty"`                // "" clears the logo
+}
+
+// MergePayeesRequest represents request to merge payees into another one
+type MergePayeesRequest struct {
+	SourceIDs []string `json:"source_ids" binding:"required,min=1"`
+}
+
+// MergePayeesResult represents the outcome of merging payees
+type MergePayeesResult struct {
+	Payee               *Payee `json:"payee"`
+	MergedCount         int    `json:"merged_count"`
+	TransactionsUpdated int64  `json:"transactions_updated"`
+}
+
+// Refund Types
+
+// Kinds of money returned against an expense
+const (
+	RefundKindRefund        = "refund"        // Returned by the merchant
+	RefundKindReimbursement = "reimbursement" // Paid back by someone else, such as an employer
+)
+
+// RefundLink represents a refund or reimbursement as listed on its original expense
+type RefundLink struct {
+	TransactionID   string    `json:"transaction_id"`
+	Kind            string    `json:"kind"`
+	Amount          float64   `json:"amount"`
+	TransactionDate time.Time `json:"transaction_date"`
+}
+
+// Records that create and own transactions
+const (
+	LinkTypeGoalContribution      = "goal_contribution"
+	LinkTypeLoanPayment           = "loan_payment"
+	LinkTypeSettlement            = "settlement"
+	LinkTypeInvestmentTransaction = "investment_transaction"
+)
+
+// TransactionLink points from a transaction to the record that created it
+// Such a transaction is changed and removed through its record, never on its own.
+type TransactionLink struct {
+	Type string `json:"type" bson:"type"` // goal_contribution, loan_payment, settlement, investment_transaction
+	ID   string `json:"id" bson:"id"`
+}
+
+// Installment Types
+
+// Installment plan statuses
+const (
+	InstallmentStatusActive  = "active"
+	InstallmentStatusPaidOff = "paid_off"
+)
+
+// InstallmentPlan represents a credit card purchase converted into fixed monthly installments (trả góp)
+type InstallmentPlan struct {
+	ID                  string              `json:"id" bson:"_id,omitempty"`
+	WorkspaceID         string              `json:"workspace_id" bson:"workspace_id"`
+	UserID              string              `json:"user_id" bson:"user_id"`       // Member who recorded the purchase
+	AccountID           string              `json:"account_id" bson:"account_id"` // Credit card account the installments are billed to
+	CategoryID          string              `json:"category_id" bson:"category_id"`
+	Description         string              `json:"description" bson:"description"`
+	Merchant            *string             `json:"merchant,omitempty" bson:"merchant,omitempty"`
+	PurchaseDate        time.Time           `json:"purchase_date" bson:"purchase_date"`
+	Principal           float64             `json:"principal" bson:"principal"` // Purchase price split across the installments
+	Installments        int                 `json:"installments" bson:"installments"`
+	InterestRate        float64             `json:"interest_rate" bson:"interest_rate"` // Flat annual percentage on the original principal, 0 for 0% plans
+	Fee                 float64             `json:"fee" bson:"fee"`                     // One-off conversion fee, billed with the first installment
+	StartMonth          string              `json:"start_month" bson:"start_month"`     // Statement (YYYY-MM) the first installment is billed on
+	Status              string              `json:"status" bson:"status"`               // active, paid_off
+	Schedule            []InstallmentCharge `json:"schedule" bson:"schedule"`
+	PayoffTransactionID *string             `json:"payoff_transaction_id,omitempty" bson:"payoff_transaction_id,omitempty"`
+	PaidOffAt           *time.Time          `json:"paid_off_at,omitempty" bson:"paid_off_at,omitempty"`
+	CreatedAt           time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// InstallmentCharge represents one period of an installment plan
+type InstallmentCharge struct {
+	Period        int       `json:"period" bson:"period"`
+	ChargeDate    time.Time `json:"charge_date" bson:"charge_date"` // Statement closing date the charge is billed on
+	DueDate       time.Time `json:"due_date" bson:"due_date"`
+	Principal     float64   `json:"principal" bson:"principal"`
+	Interest      float64   `json:"interest" bson:"interest"`
+	Fee           float64   `json:"fee" bson:"fee"`
+	Amount        float64   `json:"amount" bson:"amount"`
+	TransactionID *string   `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"` // Set once the charge is posted to the account
+	SettledEarly  bool      `json:"settled_early" bson:"settled_early"`                       // Covered by an early payoff instead of its own charge
+}
+
+// CreateInstallmentPlanRequest represents request to record an installment purchase
+type CreateInstallmentPlanRequest struct {
+	CategoryID   string     `json:"category_id" binding:"required"`
+	Description  string     `json:"description" binding:"required,max=200"`
+	Merchant     *string    `json:"merchant,omitempty"`
+	Principal    float64    `json:"principal" binding:"required,gt=0"`
+	Installments int        `json:"installments" binding:"required,min=2,max=60"`
+	InterestRate float64    `json:"interest_rate" binding:"min=0,max=100"`
+	Fee          float64    `json:"fee" binding:"min=0"`
+	PurchaseDate *time.Time `json:"purchase_date,omitempty"` // Defaults to now
+	StartMonth   string     `json:"start_month,omitempty"`   // YYYY-MM, defaults to the first statement closing after the purchase
+}
+
+// PayoffInstallmentPlanRequest represents request to settle the rest of an installment plan early
+type PayoffInstallmentPlanRequest struct {
+	Fee        float64    `json:"fee" binding:"min=0"`   // Early settlement fee charged by the bank
+	PayoffDate *time.Time `json:"payoff_date,omitempty"` // Defaults to now
+}
+
+// InstallmentPlanSummary represents an installment plan with its remaining balance
+type InstallmentPlanSummary struct {
+	InstallmentPlan
+	RemainingInstallments int                `json:"remaining_installments"`
+	OutstandingPrincipal  float64            `json:"outstanding_principal"`
+	RemainingAmount       float64            `json:"remaining_amount"` // Principal, interest and fees not yet billed
+	NextCharge            *InstallmentCharge `json:"next_charge,omitempty"`
+}
+
+// Transaction Template Types
+
+// TransactionTemplate represents a saved transaction for one-tap entry
+type TransactionTemplate struct {
+	ID          string     `json:"id" bson:"_id,omitempty"`
+	WorkspaceID string     `json:"workspace_id" bson:"workspace_id"`
+	UserID      string     `json:"user_id" bson:"user_id"` // Member who created the template
+	Name        string     `json:"name" bson:"name"`
+	AccountID   string     `json:"account_id" bson:"account_id"`
+	CategoryID  *string    `json:"category_id,omitempty" bson:"category_id,omitempty"`
+	Type        string     `json:"type" bson:"type"`                             // income, expense
+	Amount      *float64   `json:"amount,omitempty" bson:"amount,omitempty"`     // Missing means the amount is entered on use
+	Merchant    *string    `json:"merchant,omitempty" bson:"merchant,omitempty"` // Linked to a payee when the transaction is created
+	Description *string    `json:"description,omitempty" bson:"description,omitempty"`
+	Tags        []string   `json:"tags" bson:"tags"`
+	Notes       *string    `json:"notes,omitempty" bson:"notes,omitempty"`
+	Favorite    bool       `json:"favorite" bson:"favorite"`       // Favorites are listed first
+	UsageCount  int        `json:"usage_count" bson:"usage_count"` // Transactions created from the template
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// CreateTransactionTemplateRequest represents request to create a transaction template
+type CreateTransactionTemplateRequest struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	AccountID   string   `json:"account_id" binding:"required"`
+	CategoryID  *string  `json:"category_id,omitempty"`
+	Type        string   `json:"type" binding:"required,oneof=income expense"`
+	Amount      *float64 `json:"amount,omitempty" binding:"omitempty,gt=0"`
+	Merchant    *string  `json:"merchant,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Notes       *string  `json:"notes,omitempty"`
+	Favorite    bool     `json:"favorite"`
+}
+
+// UpdateTransactionTemplateRequest represents request to update a transaction template
+// Optional fields set to an empty string (or an amount of 0) are cleared
+type UpdateTransactionTemplateRequest struct {
+	Name        *string  `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	AccountID   *string  `json:"account_id,omitempty"`
+	CategoryID  *string  `json:"category_id,omitempty"`
+	Type        *string  `json:"type,omitempty" binding:"omitempty,oneof=income expense"`
+	Amount      *float64 `json:"amount,omitempty" binding:"omitempty,min=0"`
+	Merchant    *string  `json:"merchant,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Notes       *string  `json:"notes,omitempty"`
+	Favorite    *bool    `json:"favorite,omitempty"`
+}
+
+// CreateFromTemplateRequest represents overrides applied when creating a transaction from a template
+type CreateFromTemplateRequest struct {
+	AccountID       *string    `json:"account_id,omitempty"`
+	CategoryID      *string    `json:"category_id,omitempty"`
+	Amount          *float64   `json:"amount,omitempty" binding:"omitempty,gt=0"` // Required when the template has no amount
+	Merchant        *string    `json:"merchant,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	Tags            []string   `json:"tags,omitempty"`
+	Notes           *string    `json:"notes,omitempty"`
+	TransactionDate *time.Time `json:"transaction_date,omitempty"` // Defaults to now
+	Status          string     `json:"status,omitempty" binding:"omitempty,oneof=pending cleared"`
+}
+
+// SuggestedTemplate represents a frequently repeated transaction that could be saved as a template
+type SuggestedTemplate struct {
+	Name        string    `json:"name"`
+	AccountID   string    `json:"account_id"`
+	CategoryID  *string   `json:"category_id,omitempty"`
+	Type        string    `json:"type"`
+	Amount      float64   `json:"amount"` // Most common amount of the pattern
+	Merchant    *string   `json:"merchant,omitempty"`
+	Occurrences int       `json:"occurrences"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+}
+
+// TransactionPattern represents a group of transactions with the same account, category, type, merchant and amount
+type TransactionPattern struct {
+	AccountID  string    `bson:"account_id"`
+	CategoryID *string   `bson:"category_id"`
+	Type       string    `bson:"type"`
+	Merchant   *string   `bson:"merchant"`
+	Amount     float64   `bson:"amount"`
+	Count      int       `bson:"count"`
+	LastUsedAt time.Time `bson:"last_used_at"`
+}
+
+// Tag Types
+
+// Tag holds the settings of a transaction tag
+// Tags live on transactions as plain strings; a Tag document only exists once a tag is given settings such as a color.
+type Tag struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID string    `json:"workspace_id" bson:"workspace_id"`
+	Name        string    `json:"name" bson:"name"`
+	Color       *string   `json:"color,omitempty" bson:"color,omitempty"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// TagUsage represents a tag with how often it is used
+type TagUsage struct {
+	Name       string     `json:"name" bson:"_id"`
+	Color      *string    `json:"color,omitempty" bson:"-"`
+	UsageCount int        `json:"usage_count" bson:"usage_count"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+}
+
+// UpdateTagRequest represents request to rename a tag or change its color
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty" binding:"omitempty,min=1,max=50"` // Renames the tag on every transaction
+	Color *string `json:"color,omitempty"`                                 // "" clears the color
+}
+
+// MergeTagsRequest represents request to fold other tags into a tag
+type MergeTagsRequest struct {
+	SourceTags []string `json:"source_tags" binding:"required,min=1"`
+}
+
+// TagChangeResult represents the outcome of renaming or merging tags
+type TagChangeResult struct {
+	Tag                 TagUsage `json:"tag"`
+	TransactionsUpdated int64    `json:"transactions_updated"`
+}
+
+// TagReport represents expenses grouped by tag
+// A transaction with several tags counts toward each of them, so percentages can add up to more than 100.
+type TagReport struct {
+	Tag              string  `json:"tag"`
+	Color            *string `json:"color,omitempty"`
+	Amount           float64 `json:"amount"`
+	TransactionCount int     `json:"transaction_count"`
+	Percentage       float64 `json:"percentage"` // Share of the expenses that have any tag
+}
+
+// Attachment Types
+
+// Attachment represents a file stored for a transaction, such as a receipt
+type Attachment struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	WorkspaceID   string    `json:"workspace_id" bson:"workspace_id"`
+	UserID        string    `json:"user_id" bson:"user_id"`                                   // Member who uploaded the file
+	TransactionID *string   `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"` // Missing until the upload is linked to a transaction
+	Key           string    `json:"key" bson:"key"`                                           // Object key in storage
+	URL           string    `json:"-" bson:"url"`                                             // Unsigned URL, matched against attachment_url
+	DownloadURL   string    `json:"download_url,omitempty" bson:"-"`                          // Signed on every read and expires
+	FileName      string    `json:"file_name" bson:"file_name"`
+	ContentType   string    `json:"content_type" bson:"content_type"`
+	Size          int64     `json:"size" bson:"size"`
+	Checksum      string    `json:"checksum,omitempty" bson:"checksum,omitempty"` // SHA-256 of the stored content, hex encoded; missing until a direct upload is completed
+	Width         int       `json:"width,omitempty" bson:"width,omitempty"`       // Pixel size of images the pipeline could decode
+	Height        int       `json:"height,omitempty" bson:"height,omitempty"`
+	ThumbnailKey  string    `json:"-" bson:"thumbnail_key,omitempty"`
+	ThumbnailURL  string    `json:"thumbnail_url,omitempty" bson:"-"` // Signed on every read and expires
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// PresignAttachmentRequest describes a file the client will upload straight to storage
+type PresignAttachmentRequest struct {
+	FileName    string `json:"file_name" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,gt=0"`
+}
+
+// PresignedUpload is a signed URL the client PUTs the file to, with the attachment recorded for it
+// The PUT must carry the Content-Type and Content-Length given in the request.
+type PresignedUpload struct {
+	Attachment *Attachment `json:"attachment"`
+	UploadURL  string      `json:"upload_url"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+}
@@ -24,13 +24,13 @@ func NewAccountRepository(db *mongo.Database) *AccountRepository {
 }
 
 // Create creates a new account
-func (r *AccountRepository) Create(userID string, req models.CreateAccountRequest) (*models.Account, error) {
+func (r *AccountRepository) Create(workspaceID, userID string, req models.CreateAccountRequest) (*models.Account, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Get max display order
 	maxOrder := 0
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"workspace_id": workspaceID}
 	opts := options.FindOne().SetSort(bson.D{{Key: "display_order", Value: -1}})
 	var lastAccount models.Account
 	err := r.collection.FindOne(ctx, filter, opts).Decode(&lastAccount)
@@ -46,6 +46,7 @@ func (r *AccountRepository) Create(userID string, req models.CreateAccountReques
 
 	account := &models.Account{
 		ID:                  uuid.New().String(),
+		WorkspaceID:         workspaceID,
 		UserID:              userID,
 		Name:                req.Name,
 		Type:                req.Type,
@@ -61,6 +62,12 @@ func (r *AccountRepository) Create(userID string, req models.CreateAccountReques
 		CreditLimit:         req.CreditLimit,
 		StatementDate:       req.StatementDate,
 		DueDate:             req.DueDate,
+		LoanDirection:       req.LoanDirection,
+		Principal:           req.Principal,
+		InterestRate:        req.InterestRate,
+		TermPeriods:         req.TermPeriods,
+		PaymentFrequency:    req.PaymentFrequency,
+		LoanStartDate:       req.LoanStartDate,
 		IsActive:            true,
 		IsExcludedFromTotal: req.IsExcludedFromTotal,
 		DisplayOrder:        displayOrder,
@@ -77,12 +84,12 @@ func (r *AccountRepository) Create(userID string, req models.CreateAccountReques
 }
 
 // GetByID retrieves an account by ID
-func (r *AccountRepository) GetByID(id, userID string) (*models.Account, error) {
+func (r *AccountRepository) GetByID(id, workspaceID string) (*models.Account, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var account models.Account
-	filter := bson.M{"_id": id, "user_id": userID}
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
 
 	err := r.collection.FindOne(ctx, filter).Decode(&account)
 	if err == mongo.ErrNoDocuments {
@@ -95,12 +102,12 @@ func (r *AccountRepository) GetByID(id, userID string) (*models.Account, error)
 	return &account, nil
 }
 
-// GetAll retrieves all accounts for a user
-func (r *AccountRepository) GetAll(userID string, pagination models.PaginationQuery) ([]models.Account, int, error) {
+// GetAll retrieves all accounts in a workspace
+func (r *AccountRepository) GetAll(workspaceID string, pagination models.PaginationQuery) ([]models.Account, int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"workspace_id": workspaceID}
 
 	// Get total count
 	totalCount, err := r.collection.CountDocuments(ctx, filter)
@@ -132,12 +139,38 @@ func (r *AccountRepository) GetAll(userID string, pagination models.PaginationQu
 	return accounts, int(totalCount), nil
 }
 
+// GetAllInWorkspace retrieves every account in a workspace without pagination
+func (r *AccountRepository) GetAllInWorkspace(workspaceID string) ([]models.Account, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "display_order", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var accounts []models.Account
+	if err = cursor.All(ctx, &accounts); err != nil {
+		return nil, err
+	}
+
+	if accounts == nil {
+		accounts = []models.Account{}
+	}
+
+	return accounts, nil
+}
+
 // Update updates an account
-func (r *AccountRepository) Update(id, userID string, req models.UpdateAccountRequest) (*models.Account, error) {
+func (r *AccountRepository) Update(id, workspaceID string, req models.UpdateAccountRequest) (*models.Account, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"_id": id, "user_id": userID}
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
 	update := bson.M{
 		"$set": bson.M{
 			"updated_at": time.Now(),
@@ -181,6 +214,15 @@ func (r *AccountRepository) Update(id, userID string, req models.UpdateAccountRe
 	if req.DueDate != nil {
 		update["$set"].(bson.M)["due_date"] = *req.DueDate
 	}
+	if req.InterestRate != nil {
+		update["$set"].(bson.M)["interest_rate"] = *req.InterestRate
+	}
+	if req.TermPeriods != nil {
+		update["$set"].(bson.M)["term_periods"] = *req.TermPeriods
+	}
+	if req.PaymentFrequency != nil {
+		update["$set"].(bson.M)["payment_frequency"] = *req.PaymentFrequency
+	}
 	if req.IsActive != nil {
 		update["$set"].(bson.M)["is_active"] = *req.IsActive
 	}
@@ -206,12 +248,12 @@ func (r *AccountRepository) Update(id, userID string, req models.UpdateAccountRe
 }
 
 // GetTotalBalance calculates total balance across all active accounts
-func (r *AccountRepository) GetTotalBalance(userID string) (float64, error) {
+func (r *AccountRepository) GetTotalBalance(workspaceID string) (float64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id":                userID,
+		"workspace_id":           workspaceID,
 		"is_active":              true,
 		"is_excluded_from_total": false,
 	}
@@ -234,12 +276,38 @@ func (r *AccountRepository) GetTotalBalance(userID string) (float64, error) {
 	return total, nil
 }
 
+// GetByType retrieves the active accounts of a type in a workspace
+func (r *AccountRepository) GetByType(workspaceID, accountType string) ([]models.Account, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID, "type": accountType, "is_active": true}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var accounts []models.Account
+	if err = cursor.All(ctx, &accounts); err != nil {
+		return nil, err
+	}
+
+	if accounts == nil {
+		accounts = []models.Account{}
+	}
+
+	return accounts, nil
+}
+
 // GetSummary returns account summary statistics
-func (r *AccountRepository) GetSummary(userID string) (*models.AccountSummary, error) {
+// marketValues holds the value of investment holdings by account ID, which counts towards assets on top of cash
+func (r *AccountRepository) GetSummary(workspaceID string, marketValues map[string]float64) (*models.AccountSummary, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"user_id": userID, "is_active": true}
+	filter := bson.M{"workspace_id": workspaceID, "is_active": true}
 
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
@@ -265,31 +333,44 @@ func (r *AccountRepository) GetSummary(userID string) (*models.AccountSummary, e
 
 		if !account.IsExcludedFromTotal {
 			totalBalance += account.Balance
+
+			// Negative balances (borrowed loans, overdrawn accounts) count as liabilities,
+			// everything else including money lent out counts as assets
+			if account.Balance < 0 {
+				summary.TotalLiabilities += -account.Balance
+			} else {
+				summary.TotalAssets += account.Balance
+			}
+
+			if value, ok := marketValues[account.ID]; ok {
+				summary.InvestmentValue += value
+				summary.TotalAssets += value
+			}
 		}
 	}
 
 	summary.TotalAccounts = accountCount
 	summary.TotalBalance = totalBalance
-	summary.NetWorth = totalBalance
+	summary.NetWorth = summary.TotalAssets - summary.TotalLiabilities
 
 	return summary, nil
 }
 
-// CountByUser counts accounts for a user
-func (r *AccountRepository) CountByUser(userID string) (int64, error) {
+// CountByWorkspace counts active accounts in a workspace
+func (r *AccountRepository) CountByWorkspace(workspaceID string) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"user_id": userID, "is_active": true}
+	filter := bson.M{"workspace_id": workspaceID, "is_active": true}
 	return r.collection.CountDocuments(ctx, filter)
 }
 
 // Delete deletes an account
-func (r *AccountRepository) Delete(id, userID string) error {
+func (r *AccountRepository) Delete(id, workspaceID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"_id": id, "user_id": userID}
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
 	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
@@ -303,11 +384,11 @@ func (r *AccountRepository) Delete(id, userID string) error {
 }
 
 // UpdateBalance updates account balance
-func (r *AccountRepository) UpdateBalance(id, userID string, amount float64) error {
+func (r *AccountRepository) UpdateBalance(id, workspaceID string, amount float64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"_id": id, "user_id": userID}
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
 	update := bson.M{
 		"$inc": bson.M{"balance": amount},
 		"$set": bson.M{"updated_at": time.Now()},
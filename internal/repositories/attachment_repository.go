@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AttachmentRepository handles attachment data operations
+type AttachmentRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *mongo.Database) *AttachmentRepository {
+	return &AttachmentRepository{
+		collection: db.Collection("attachments"),
+	}
+}
+
+// EnsureAttachmentIndexes creates the indexes used to list attachments and find stored objects
+func EnsureAttachmentIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspace_id", Value: 1},
+				{Key: "transaction_id", Value: 1},
+			},
+			Options: options.Index().SetName("attachment_transaction"),
+		},
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetName("attachment_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "thumbnail_key", Value: 1}},
+			Options: options.Index().SetName("attachment_thumbnail_key").SetSparse(true),
+		},
+	}
+
+	_, err := db.Collection("attachments").Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+// Create stores a new attachment
+func (r *AttachmentRepository) Create(attachment *models.Attachment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	attachment.ID = uuid.New().String()
+	attachment.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, attachment)
+	return err
+}
+
+// GetByID retrieves an attachment by ID
+func (r *AttachmentRepository) GetByID(id, workspaceID string) (*models.Attachment, error) {
+	return r.findOne(bson.M{"_id": id, "workspace_id": workspaceID})
+}
+
+// GetByKey retrieves the attachment of a workspace that records the object stored under a key
+func (r *AttachmentRepository) GetByKey(key, workspaceID string) (*models.Attachment, error) {
+	return r.findOne(bson.M{"key": key, "workspace_id": workspaceID})
+}
+
+// findOne retrieves the attachment matching a filter
+func (r *AttachmentRepository) findOne(filter bson.M) (*models.Attachment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var attachment models.Attachment
+	err := r.collection.FindOne(ctx, filter).Decode(&attachment)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// GetByTransactionID retrieves the attachments of a transaction, oldest first
+func (r *AttachmentRepository) GetByTransactionID(workspaceID, transactionID string) ([]models.Attachment, error) {
+	filter := bson.M{"workspace_id": workspaceID, "transaction_id": transactionID}
+	return r.find(filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+}
+
+// GetByTransactionIDs retrieves the attachments of several transactions, oldest first
+func (r *AttachmentRepository) GetByTransactionIDs(workspaceID string, transactionIDs []string) ([]models.Attachment, error) {
+	filter := bson.M{"workspace_id": workspaceID, "transaction_id": bson.M{"$in": transactionIDs}}
+	return r.find(filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+}
+
+// CountByTransactionID counts the attachments of a transaction
+func (r *AttachmentRepository) CountByTransactionID(workspaceID, transactionID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID, "transaction_id": transactionID}
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+// ExistsByKey reports whether an attachment records the object stored under a key, as its file or thumbnail
+func (r *AttachmentRepository) ExistsByKey(key string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"$or": []bson.M{{"key": key}, {"thumbnail_key": key}}}
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetUnlinkedBefore retrieves uploads, across all workspaces, that were never linked to a transaction
+func (r *AttachmentRepository) GetUnlinkedBefore(cutoff time.Time) ([]models.Attachment, error) {
+	filter := bson.M{"transaction_id": nil, "created_at": bson.M{"$lt": cutoff}}
+	return r.find(filter, options.Find())
+}
+
+// GetOrphaned retrieves attachments, across all workspaces, whose transaction no longer exists, not even in the trash
+func (r *AttachmentRepository) GetOrphaned() ([]models.Attachment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"transaction_id": bson.M{"$ne": nil}}},
+		{"$lookup": bson.M{
+			"from":         "transactions",
+			"localField":   "transaction_id",
+			"foreignField": "_id",
+			"as":           "transaction",
+		}},
+		{"$match": bson.M{"transaction": bson.M{"$size": 0}}},
+		{"$project": bson.M{"transaction": 0}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []models.Attachment
+	if err := cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+
+	return attachments, nil
+}
+
+// Link attaches an upload to a transaction
+func (r *AttachmentRepository) Link(id, workspaceID, transactionID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	update := bson.M{"$set": bson.M{"transaction_id": transactionID}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// UpdateFile records the processed file of an attachment
+func (r *AttachmentRepository) UpdateFile(attachment *models.Attachment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": attachment.ID, "workspace_id": attachment.WorkspaceID}
+	update := bson.M{"$set": bson.M{
+		"content_type":  attachment.ContentType,
+		"size":          attachment.Size,
+		"checksum":      attachment.Checksum,
+		"width":         attachment.Width,
+		"height":        attachment.Height,
+		"thumbnail_key": attachment.ThumbnailKey,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Delete deletes an attachment record
+func (r *AttachmentRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// find retrieves the attachments matching a filter
+func (r *AttachmentRepository) find(filter bson.M, opts *options.FindOptions) ([]models.Attachment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []models.Attachment
+	if err = cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+
+	return attachments, nil
+}
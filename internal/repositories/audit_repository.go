@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditRepository handles audit log data operations
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *mongo.Database) *AuditRepository {
+	return &AuditRepository{
+		collection: db.Collection("audit_log"),
+	}
+}
+
+// EnsureAuditIndexes creates the index used to look up and number the versions of an entity
+func EnsureAuditIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "workspace_id", Value: 1},
+			{Key: "entity_type", Value: 1},
+			{Key: "entity_id", Value: 1},
+			{Key: "version", Value: -1},
+		},
+		Options: options.Index().SetName("audit_entity_version").SetUnique(true),
+	}
+
+	_, err := db.Collection("audit_log").Indexes().CreateOne(ctx, index)
+	return err
+}
+
+// Create stores an audit entry as the next version of its entity
+func (r *AuditRepository) Create(entry *models.AuditEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	entry.ID = uuid.New().String()
+	entry.CreatedAt = time.Now()
+
+	// Two writers may pick the same version; the unique index rejects the loser, which then retries
+	for attempt := 0; attempt < 3; attempt++ {
+		latest, err := r.latestVersion(ctx, entry.WorkspaceID, entry.EntityType, entry.EntityID)
+		if err != nil {
+			return err
+		}
+		entry.Version = latest + 1
+
+		_, err = r.collection.InsertOne(ctx, entry)
+		if err == nil || !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("could not assign a version to the %s audit entry", entry.EntityType)
+}
+
+// GetByEntity retrieves the audit entries of an entity, newest version first
+func (r *AuditRepository) GetByEntity(workspaceID, entityType, entityID string) ([]models.AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID, "entity_type": entityType, "entity_id": entityID}
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+
+	return entries, nil
+}
+
+// GetVersion retrieves one version of an entity
+func (r *AuditRepository) GetVersion(workspaceID, entityType, entityID string, version int) (*models.AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var entry models.AuditEntry
+	filter := bson.M{"workspace_id": workspaceID, "entity_type": entityType, "entity_id": entityID, "version": version}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// latestVersion returns the highest recorded version of an entity, or 0 when it has none
+func (r *AuditRepository) latestVersion(ctx context.Context, workspaceID, entityType, entityID string) (int, error) {
+	filter := bson.M{"workspace_id": workspaceID, "entity_type": entityType, "entity_id": entityID}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"version": 1})
+
+	var latest struct {
+		Version int `bson:"version"`
+	}
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&latest)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return latest.Version, nil
+}
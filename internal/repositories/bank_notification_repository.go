@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BankNotificationRepository handles ingested bank notification data operations
+type BankNotificationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewBankNotificationRepository creates a new bank notification repository
+func NewBankNotificationRepository(db *mongo.Database) *BankNotificationRepository {
+	return &BankNotificationRepository{
+		collection: db.Collection("bank_notifications"),
+	}
+}
+
+// EnsureBankNotificationIndexes creates the index that keeps a notification from being ingested twice
+func EnsureBankNotificationIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "workspace_id", Value: 1},
+			{Key: "text_hash", Value: 1},
+		},
+		Options: options.Index().SetName("bank_notification_text").SetUnique(true),
+	}
+
+	_, err := db.Collection("bank_notifications").Indexes().CreateOne(ctx, index)
+	return err
+}
+
+// Create stores an ingested notification
+// It returns false without an error when the workspace already has a notification with the same text.
+func (r *BankNotificationRepository) Create(notification *models.BankNotification) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notification.ID = uuid.New().String()
+	notification.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, notification)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LinkTransaction records the transaction created for a notification and the balance comparison made after it
+func (r *BankNotificationRepository) LinkTransaction(notification *models.BankNotification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": notification.ID, "workspace_id": notification.WorkspaceID}
+	update := bson.M{"$set": bson.M{
+		"transaction_id":     notification.TransactionID,
+		"account_balance":    notification.AccountBalance,
+		"balance_mismatch":   notification.BalanceMismatch,
+		"balance_difference": notification.BalanceDifference,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Delete deletes a notification
+func (r *BankNotificationRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// GetByTextHash retrieves a notification already ingested with the same text
+func (r *BankNotificationRepository) GetByTextHash(workspaceID, textHash string) (*models.BankNotification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var notification models.BankNotification
+	filter := bson.M{"workspace_id": workspaceID, "text_hash": textHash}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&notification)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+// GetAll retrieves the ingested notifications of a workspace, newest first
+func (r *BankNotificationRepository) GetAll(workspaceID string, query models.BankNotificationFilterQuery) ([]models.BankNotification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	if query.AccountID != "" {
+		filter["account_id"] = query.AccountID
+	}
+	if query.Mismatched {
+		filter["balance_mismatch"] = true
+	}
+
+	limit := query.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []models.BankNotification
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	if notifications == nil {
+		notifications = []models.BankNotification{}
+	}
+
+	return notifications, nil
+}
@@ -24,12 +24,13 @@ func NewBudgetRepository(db *mongo.Database) *BudgetRepository {
 }
 
 // Create creates a new budget
-func (r *BudgetRepository) Create(userID string, req models.CreateBudgetRequest) (*models.Budget, error) {
+func (r *BudgetRepository) Create(workspaceID, userID string, req models.CreateBudgetRequest) (*models.Budget, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	budget := &models.Budget{
 		ID:             uuid.New().String(),
+		WorkspaceID:    workspaceID,
 		UserID:         userID,
 		Month:          req.Month,
 		Scope:          req.Scope,
@@ -51,12 +52,12 @@ func (r *BudgetRepository) Create(userID string, req models.CreateBudgetRequest)
 }
 
 // GetByID retrieves a budget by ID
-func (r *BudgetRepository) GetByID(id, userID string) (*models.Budget, error) {
+func (r *BudgetRepository) GetByID(id, workspaceID string) (*models.Budget, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var budget models.Budget
-	filter := bson.M{"_id": id, "user_id": userID}
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
 
 	err := r.collection.FindOne(ctx, filter).Decode(&budget)
 	if err == mongo.ErrNoDocuments {
@@ -70,13 +71,13 @@ func (r *BudgetRepository) GetByID(id, userID string) (*models.Budget, error) {
 }
 
 // GetByMonth retrieves all budgets for a specific month
-func (r *BudgetRepository) GetByMonth(userID, month string) ([]models.Budget, error) {
+func (r *BudgetRepository) GetByMonth(workspaceID, month string) ([]models.Budget, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id": userID,
-		"month":   month,
+		"workspace_id": workspaceID,
+		"month":        month,
 	}
 
 	cursor, err := r.collection.Find(ctx, filter)
@@ -98,14 +99,14 @@ func (r *BudgetRepository) GetByMonth(userID, month string) ([]models.Budget, er
 }
 
 // GetByMonthAndScope retrieves budget by month and scope
-func (r *BudgetRepository) GetByMonthAndScope(userID, month, scope string, categoryID *string) (*models.Budget, error) {
+func (r *BudgetRepository) GetByMonthAndScope(workspaceID, month, scope string, categoryID *string) (*models.Budget, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id": userID,
-		"month":   month,
-		"scope":   scope,
+		"workspace_id": workspaceID,
+		"month":        month,
+		"scope":        scope,
 	}
 
 	if scope == "category" && categoryID != nil {
@@ -130,7 +131,7 @@ func (r *BudgetRepository) GetByMonthAndScope(userID, month, scope string, categ
 }
 
 // Update updates a budget
-func (r *BudgetRepository) Update(id, userID string, req models.UpdateBudgetRequest) (*models.Budget, error) {
+func (r *BudgetRepository) Update(id, workspaceID string, req models.UpdateBudgetRequest) (*models.Budget, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -154,8 +155,8 @@ func (r *BudgetRepository) Update(id, userID string, req models.UpdateBudgetRequ
 	}
 
 	filter := bson.M{
-		"_id":     id,
-		"user_id": userID,
+		"_id":          id,
+		"workspace_id": workspaceID,
 	}
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
@@ -173,13 +174,13 @@ func (r *BudgetRepository) Update(id, userID string, req models.UpdateBudgetRequ
 }
 
 // UpdateSpent updates the spent amount for a budget
-func (r *BudgetRepository) UpdateSpent(id, userID string, spent float64) error {
+func (r *BudgetRepository) UpdateSpent(id, workspaceID string, spent float64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"_id":     id,
-		"user_id": userID,
+		"_id":          id,
+		"workspace_id": workspaceID,
 	}
 
 	update := bson.M{
@@ -193,14 +194,73 @@ func (r *BudgetRepository) UpdateSpent(id, userID string, spent float64) error {
 	return err
 }
 
+// MoveToCategory moves the budgets of a category to another category
+// When the target already has a budget for the month, the limit is added to it and the moved budget is deleted.
+// It returns how many budgets were moved and how many were merged.
+func (r *BudgetRepository) MoveToCategory(ctx context.Context, workspaceID, fromCategoryID, toCategoryID string) (int, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"workspace_id": workspaceID,
+		"scope":        "category",
+		"category_id":  fromCategoryID,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var budgets []models.Budget
+	if err = cursor.All(ctx, &budgets); err != nil {
+		return 0, 0, err
+	}
+
+	moved, merged := 0, 0
+	for _, budget := range budgets {
+		var existing models.Budget
+		err := r.collection.FindOne(ctx, bson.M{
+			"workspace_id": workspaceID,
+			"month":        budget.Month,
+			"scope":        "category",
+			"category_id":  toCategoryID,
+		}).Decode(&existing)
+
+		if err == mongo.ErrNoDocuments {
+			update := bson.M{"$set": bson.M{"category_id": toCategoryID, "updated_at": time.Now()}}
+			if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": budget.ID, "workspace_id": workspaceID}, update); err != nil {
+				return moved, merged, err
+			}
+			moved++
+			continue
+		}
+		if err != nil {
+			return moved, merged, err
+		}
+
+		update := bson.M{"$set": bson.M{"limit": existing.Limit + budget.Limit, "updated_at": time.Now()}}
+		if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": existing.ID, "workspace_id": workspaceID}, update); err != nil {
+			return moved, merged, err
+		}
+		if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": budget.ID, "workspace_id": workspaceID}); err != nil {
+			return moved, merged, err
+		}
+		merged++
+	}
+
+	return moved, merged, nil
+}
+
 // Delete deletes a budget
-func (r *BudgetRepository) Delete(id, userID string) error {
+func (r *BudgetRepository) Delete(id, workspaceID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"_id":     id,
-		"user_id": userID,
+		"_id":          id,
+		"workspace_id": workspaceID,
 	}
 
 	result, err := r.collection.DeleteOne(ctx, filter)
@@ -215,12 +275,20 @@ func (r *BudgetRepository) Delete(id, userID string) error {
 	return nil
 }
 
-// GetAll retrieves all budgets for a user
-func (r *BudgetRepository) GetAll(userID string) ([]models.Budget, error) {
+// CountByWorkspace counts the budgets of a workspace
+func (r *BudgetRepository) CountByWorkspace(workspaceID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return r.collection.CountDocuments(ctx, bson.M{"workspace_id": workspaceID})
+}
+
+// GetAll retrieves all budgets in a workspace
+func (r *BudgetRepository) GetAll(workspaceID string) ([]models.Budget, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"workspace_id": workspaceID}
 	opts := options.Find().SetSort(bson.D{{Key: "month", Value: -1}, {Key: "created_at", Value: -1}})
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
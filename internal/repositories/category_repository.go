@@ -24,21 +24,34 @@ func NewCategoryRepository(db *mongo.Database) *CategoryRepository {
 }
 
 // Create creates a new category
-func (r *CategoryRepository) Create(userID string, req models.CreateCategoryRequest) (*models.Category, error) {
+func (r *CategoryRepository) Create(workspaceID, userID string, req models.CreateCategoryRequest) (*models.Category, error) {
+	return r.insert(workspaceID, userID, req, nil)
+}
+
+// CreateDefault creates a default category seeded from a template entry
+// Default categories can be edited like any other; the template key records the entry they came from.
+func (r *CategoryRepository) CreateDefault(workspaceID, userID, templateKey string, req models.CreateCategoryRequest) (*models.Category, error) {
+	return r.insert(workspaceID, userID, req, &templateKey)
+}
+
+// insert creates a category, a default one recording its template entry when it is seeded from a template
+func (r *CategoryRepository) insert(workspaceID, userID string, req models.CreateCategoryRequest, templateKey *string) (*models.Category, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	category := &models.Category{
-		ID:        uuid.New().String(),
-		UserID:    userID,
-		ParentID:  req.ParentID,
-		Name:      req.Name,
-		Type:      req.Type,
-		Icon:      req.Icon,
-		Color:     req.Color,
-		IsDefault: false,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		ParentID:    req.ParentID,
+		Name:        req.Name,
+		Type:        req.Type,
+		Icon:        req.Icon,
+		Color:       req.Color,
+		IsDefault:   templateKey != nil,
+		TemplateKey: templateKey,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 
 	_, err := r.collection.InsertOne(ctx, category)
@@ -50,12 +63,12 @@ func (r *CategoryRepository) Create(userID string, req models.CreateCategoryRequ
 }
 
 // GetByID retrieves a category by ID
-func (r *CategoryRepository) GetByID(id, userID string) (*models.Category, error) {
+func (r *CategoryRepository) GetByID(id, workspaceID string) (*models.Category, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var category models.Category
-	filter := bson.M{"_id": id, "user_id": userID}
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
 
 	err := r.collection.FindOne(ctx, filter).Decode(&category)
 	if err == mongo.ErrNoDocuments {
@@ -68,12 +81,12 @@ func (r *CategoryRepository) GetByID(id, userID string) (*models.Category, error
 	return &category, nil
 }
 
-// GetAll retrieves all categories for a user
-func (r *CategoryRepository) GetAll(userID string) ([]models.Category, error) {
+// GetAll retrieves all categories in a workspace
+func (r *CategoryRepository) GetAll(workspaceID string) ([]models.Category, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"workspace_id": workspaceID}
 	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
@@ -95,11 +108,11 @@ func (r *CategoryRepository) GetAll(userID string) ([]models.Category, error) {
 }
 
 // GetByType retrieves categories by type
-func (r *CategoryRepository) GetByType(userID string, categoryType string) ([]models.Category, error) {
+func (r *CategoryRepository) GetByType(workspaceID string, categoryType string) ([]models.Category, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"user_id": userID, "type": categoryType}
+	filter := bson.M{"workspace_id": workspaceID, "type": categoryType}
 	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
@@ -121,14 +134,13 @@ func (r *CategoryRepository) GetByType(userID string, categoryType string) ([]mo
 }
 
 // Delete deletes a category
-func (r *CategoryRepository) Delete(id, userID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (r *CategoryRepository) Delete(ctx context.Context, id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"_id":        id,
-		"user_id":    userID,
-		"is_default": false,
+		"_id":          id,
+		"workspace_id": workspaceID,
 	}
 
 	result, err := r.collection.DeleteOne(ctx, filter)
@@ -143,8 +155,27 @@ func (r *CategoryRepository) Delete(id, userID string) error {
 	return nil
 }
 
+// DeleteCustom deletes every category of a workspace that is neither a default one nor seeded from the template
+func (r *CategoryRepository) DeleteCustom(workspaceID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"workspace_id": workspaceID,
+		"is_default":   false,
+		"template_key": bson.M{"$exists": false},
+	}
+
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.DeletedCount), nil
+}
+
 // Update updates a category
-func (r *CategoryRepository) Update(id, userID string, req models.UpdateCategoryRequest) (*models.Category, error) {
+func (r *CategoryRepository) Update(id, workspaceID string, req models.UpdateCategoryRequest) (*models.Category, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -174,8 +205,8 @@ func (r *CategoryRepository) Update(id, userID string, req models.UpdateCategory
 	}
 
 	filter := bson.M{
-		"_id":     id,
-		"user_id": userID,
+		"_id":          id,
+		"workspace_id": workspaceID,
 	}
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
@@ -193,12 +224,12 @@ func (r *CategoryRepository) Update(id, userID string, req models.UpdateCategory
 }
 
 // GetParentCategories retrieves all parent categories (no parent_id)
-func (r *CategoryRepository) GetParentCategories(userID string) ([]models.Category, error) {
+func (r *CategoryRepository) GetParentCategories(workspaceID string) ([]models.Category, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id": userID,
+		"workspace_id": workspaceID,
 		"$or": []bson.M{
 			{"parent_id": nil},
 			{"parent_id": bson.M{"$exists": false}},
@@ -225,13 +256,13 @@ func (r *CategoryRepository) GetParentCategories(userID string) ([]models.Catego
 }
 
 // GetChildCategories retrieves all child categories of a parent
-func (r *CategoryRepository) GetChildCategories(userID, parentID string) ([]models.Category, error) {
+func (r *CategoryRepository) GetChildCategories(workspaceID, parentID string) ([]models.Category, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id":   userID,
-		"parent_id": parentID,
+		"workspace_id": workspaceID,
+		"parent_id":    parentID,
 	}
 	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
 
@@ -254,13 +285,13 @@ func (r *CategoryRepository) GetChildCategories(userID, parentID string) ([]mode
 }
 
 // CountChildCategories counts the number of child categories
-func (r *CategoryRepository) CountChildCategories(userID, categoryID string) (int, error) {
+func (r *CategoryRepository) CountChildCategories(workspaceID, categoryID string) (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id":   userID,
-		"parent_id": categoryID,
+		"workspace_id": workspaceID,
+		"parent_id":    categoryID,
 	}
 
 	count, err := r.collection.CountDocuments(ctx, filter)
@@ -271,6 +302,66 @@ func (r *CategoryRepository) CountChildCategories(userID, categoryID string) (in
 	return int(count), nil
 }
 
+// RunInTransaction runs fn in a MongoDB transaction
+// Repository methods that take a context join the transaction when they are given the one fn receives.
+func (r *CategoryRepository) RunInTransaction(fn func(ctx context.Context) error) error {
+	return runInTransaction(r.collection.Database().Client(), fn)
+}
+
+// MoveChildren moves the child categories of a parent under another parent
+func (r *CategoryRepository) MoveChildren(ctx context.Context, workspaceID, fromParentID, toParentID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"workspace_id": workspaceID,
+		"parent_id":    fromParentID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"parent_id":  toParentID,
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// SetParent moves a category under a parent, or to the top level when parentID is nil
+func (r *CategoryRepository) SetParent(ctx context.Context, id, workspaceID string, parentID *string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":          id,
+		"workspace_id": workspaceID,
+	}
+
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	if parentID != nil {
+		update["$set"].(bson.M)["parent_id"] = *parentID
+	} else {
+		update["$unset"] = bson.M{"parent_id": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
 // GetByIDWithoutUserCheck retrieves a category by ID without checking user_id (for validation)
 func (r *CategoryRepository) GetByIDWithoutUserCheck(id string) (*models.Category, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -0,0 +1,298 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ContactRepository handles contact and contact group data operations
+type ContactRepository struct {
+	collection      *mongo.Collection
+	groupCollection *mongo.Collection
+}
+
+// NewContactRepository creates a new contact repository
+func NewContactRepository(db *mongo.Database) *ContactRepository {
+	return &ContactRepository{
+		collection:      db.Collection("contacts"),
+		groupCollection: db.Collection("contact_groups"),
+	}
+}
+
+// Create creates a new contact in a workspace
+func (r *ContactRepository) Create(workspaceID, userID string, req models.CreateContactRequest) (*models.Contact, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	contact := &models.Contact{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Notes:       req.Notes,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err := r.collection.InsertOne(ctx, contact)
+	if err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// GetByID retrieves a contact of a workspace by ID
+func (r *ContactRepository) GetByID(id, workspaceID string) (*models.Contact, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var contact models.Contact
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&contact)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &contact, nil
+}
+
+// GetAll retrieves all contacts of a workspace
+func (r *ContactRepository) GetAll(workspaceID string) ([]models.Contact, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var contacts []models.Contact
+	if err = cursor.All(ctx, &contacts); err != nil {
+		return nil, err
+	}
+
+	if contacts == nil {
+		contacts = []models.Contact{}
+	}
+
+	return contacts, nil
+}
+
+// CountByIDs counts how many of the given contact IDs belong to the workspace
+func (r *ContactRepository) CountByIDs(workspaceID string, ids []string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "workspace_id": workspaceID}
+
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// Update updates a contact
+func (r *ContactRepository) Update(id, workspaceID string, req models.UpdateContactRequest) (*models.Contact, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+	}
+
+	setFields := update["$set"].(bson.M)
+
+	if req.Name != nil {
+		setFields["name"] = *req.Name
+	}
+	if req.Email != nil {
+		setFields["email"] = *req.Email
+	}
+	if req.Phone != nil {
+		setFields["phone"] = *req.Phone
+	}
+	if req.Notes != nil {
+		setFields["notes"] = *req.Notes
+	}
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Contact
+
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Delete deletes a contact and removes it from all groups
+func (r *ContactRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_, err = r.groupCollection.UpdateMany(
+		ctx,
+		bson.M{"workspace_id": workspaceID, "contact_ids": id},
+		bson.M{"$pull": bson.M{"contact_ids": id}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	return err
+}
+
+// CreateGroup creates a new contact group in a workspace
+func (r *ContactRepository) CreateGroup(workspaceID, userID string, req models.CreateContactGroupRequest) (*models.ContactGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	group := &models.ContactGroup{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Name:        req.Name,
+		ContactIDs:  req.ContactIDs,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err := r.groupCollection.InsertOne(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGroupByID retrieves a contact group of a workspace by ID
+func (r *ContactRepository) GetGroupByID(id, workspaceID string) (*models.ContactGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var group models.ContactGroup
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	err := r.groupCollection.FindOne(ctx, filter).Decode(&group)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// GetAllGroups retrieves all contact groups of a workspace
+func (r *ContactRepository) GetAllGroups(workspaceID string) ([]models.ContactGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.groupCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []models.ContactGroup
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	if groups == nil {
+		groups = []models.ContactGroup{}
+	}
+
+	return groups, nil
+}
+
+// UpdateGroup updates a contact group
+func (r *ContactRepository) UpdateGroup(id, workspaceID string, req models.UpdateContactGroupRequest) (*models.ContactGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+	}
+
+	setFields := update["$set"].(bson.M)
+
+	if req.Name != nil {
+		setFields["name"] = *req.Name
+	}
+	if req.ContactIDs != nil {
+		setFields["contact_ids"] = req.ContactIDs
+	}
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.ContactGroup
+
+	err := r.groupCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteGroup deletes a contact group
+func (r *ContactRepository) DeleteGroup(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.groupCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GoalRepository handles savings goal data operations
+type GoalRepository struct {
+	collection             *mongo.Collection
+	contributionCollection *mongo.Collection
+}
+
+// NewGoalRepository creates a new goal repository
+func NewGoalRepository(db *mongo.Database) *GoalRepository {
+	return &GoalRepository{
+		collection:             db.Collection("goals"),
+		contributionCollection: db.Collection("goal_contributions"),
+	}
+}
+
+// Create creates a new goal
+func (r *GoalRepository) Create(workspaceID, userID string, req models.CreateGoalRequest) (*models.Goal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	goal := &models.Goal{
+		ID:           uuid.New().String(),
+		WorkspaceID:  workspaceID,
+		UserID:       userID,
+		Name:         req.Name,
+		TargetAmount: req.TargetAmount,
+		TargetDate:   req.TargetDate,
+		AccountID:    req.AccountID,
+		Icon:         req.Icon,
+		Color:        req.Color,
+		Status:       "active",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	_, err := r.collection.InsertOne(ctx, goal)
+	if err != nil {
+		return nil, err
+	}
+
+	return goal, nil
+}
+
+// GetByID retrieves a goal of a workspace by ID
+func (r *GoalRepository) GetByID(id, workspaceID string) (*models.Goal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var goal models.Goal
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&goal)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &goal, nil
+}
+
+// GetAll retrieves all goals of a workspace, optionally filtered by status
+func (r *GoalRepository) GetAll(workspaceID, status string) ([]models.Goal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var goals []models.Goal
+	if err = cursor.All(ctx, &goals); err != nil {
+		return nil, err
+	}
+
+	if goals == nil {
+		goals = []models.Goal{}
+	}
+
+	return goals, nil
+}
+
+// Update updates a goal
+func (r *GoalRepository) Update(id, workspaceID string, req models.UpdateGoalRequest) (*models.Goal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+	}
+
+	setFields := update["$set"].(bson.M)
+
+	if req.Name != nil {
+		setFields["name"] = *req.Name
+	}
+	if req.TargetAmount != nil {
+		setFields["target_amount"] = *req.TargetAmount
+	}
+	if req.TargetDate != nil {
+		setFields["target_date"] = *req.TargetDate
+	}
+	if req.Icon != nil {
+		setFields["icon"] = *req.Icon
+	}
+	if req.Color != nil {
+		setFields["color"] = *req.Color
+	}
+	if req.Status != nil {
+		setFields["status"] = *req.Status
+	}
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Goal
+
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Delete deletes a goal and all of its contributions
+func (r *GoalRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_, err = r.contributionCollection.DeleteMany(ctx, bson.M{"goal_id": id})
+	return err
+}
+
+// CreateContribution records a contribution toward a goal
+func (r *GoalRepository) CreateContribution(contribution *models.GoalContribution) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The service assigns the ID up front when it links transactions to the record
+	if contribution.ID == "" {
+		contribution.ID = uuid.New().String()
+	}
+	contribution.CreatedAt = time.Now()
+
+	_, err := r.contributionCollection.InsertOne(ctx, contribution)
+	return err
+}
+
+// GetContributionByID retrieves a single contribution of a goal
+// Callers check the goal belongs to the workspace first
+func (r *GoalRepository) GetContributionByID(id, goalID string) (*models.GoalContribution, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var contribution models.GoalContribution
+	filter := bson.M{"_id": id, "goal_id": goalID}
+
+	err := r.contributionCollection.FindOne(ctx, filter).Decode(&contribution)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &contribution, nil
+}
+
+// GetContributions retrieves all contributions of a goal, newest first
+func (r *GoalRepository) GetContributions(goalID string) ([]models.GoalContribution, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"goal_id": goalID}
+	opts := options.Find().SetSort(bson.D{{Key: "contributed_at", Value: -1}, {Key: "created_at", Value: -1}})
+
+	cursor, err := r.contributionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var contributions []models.GoalContribution
+	if err = cursor.All(ctx, &contributions); err != nil {
+		return nil, err
+	}
+
+	if contributions == nil {
+		contributions = []models.GoalContribution{}
+	}
+
+	return contributions, nil
+}
+
+// DeleteContribution deletes a contribution
+func (r *GoalRepository) DeleteContribution(id, goalID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "goal_id": goalID}
+	result, err := r.contributionCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
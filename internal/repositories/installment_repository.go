@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InstallmentRepository handles installment plan data operations
+type InstallmentRepository struct {
+	collection *mongo.Collection
+}
+
+// NewInstallmentRepository creates a new installment repository
+func NewInstallmentRepository(db *mongo.Database) *InstallmentRepository {
+	return &InstallmentRepository{
+		collection: db.Collection("installment_plans"),
+	}
+}
+
+// Create creates a new installment plan
+// The service builds the schedule, so the plan is stored as given apart from its ID and timestamps.
+func (r *InstallmentRepository) Create(plan *models.InstallmentPlan) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Stored times keep millisecond precision, and Update matches on updated_at
+	plan.ID = uuid.New().String()
+	plan.CreatedAt = time.Now().Truncate(time.Millisecond)
+	plan.UpdatedAt = plan.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, plan)
+	return err
+}
+
+// GetByID retrieves an installment plan of an account by ID
+func (r *InstallmentRepository) GetByID(id, accountID, workspaceID string) (*models.InstallmentPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var plan models.InstallmentPlan
+	filter := bson.M{"_id": id, "account_id": accountID, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&plan)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// GetByAccountID retrieves the installment plans of an account, newest purchase first
+func (r *InstallmentRepository) GetByAccountID(accountID, workspaceID string) ([]models.InstallmentPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"account_id": accountID, "workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "purchase_date", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var plans []models.InstallmentPlan
+	if err = cursor.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+
+	if plans == nil {
+		plans = []models.InstallmentPlan{}
+	}
+
+	return plans, nil
+}
+
+// GetDue retrieves active plans, across all workspaces, with a charge due on or before the given time that has not been posted
+func (r *InstallmentRepository) GetDue(now time.Time) ([]models.InstallmentPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"status": models.InstallmentStatusActive,
+		"schedule": bson.M{"$elemMatch": bson.M{
+			"charge_date":    bson.M{"$lte": now},
+			"transaction_id": bson.M{"$exists": false},
+			"settled_early":  false,
+		}},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var plans []models.InstallmentPlan
+	if err = cursor.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+
+	if plans == nil {
+		plans = []models.InstallmentPlan{}
+	}
+
+	return plans, nil
+}
+
+// Update saves the schedule and payoff state of an installment plan
+// The plan is only saved when it has not been updated since it was read, so two runs posting the same
+// charges cannot both save; the one that loses gets an error and has to undo what it posted.
+func (r *InstallmentRepository) Update(plan *models.InstallmentPlan) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	updatedAt := time.Now().Truncate(time.Millisecond)
+
+	setFields := bson.M{
+		"schedule":   plan.Schedule,
+		"status":     plan.Status,
+		"updated_at": updatedAt,
+	}
+	if plan.PayoffTransactionID != nil {
+		setFields["payoff_transaction_id"] = *plan.PayoffTransactionID
+	}
+	if plan.PaidOffAt != nil {
+		setFields["paid_off_at"] = *plan.PaidOffAt
+	}
+
+	filter := bson.M{"_id": plan.ID, "workspace_id": plan.WorkspaceID, "updated_at": plan.UpdatedAt}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": setFields})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("installment plan was changed or deleted by another request")
+	}
+
+	plan.UpdatedAt = updatedAt
+	return nil
+}
+
+// Delete deletes an installment plan
+func (r *InstallmentRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InvestmentRepository handles investment transaction data operations
+type InvestmentRepository struct {
+	collection *mongo.Collection
+}
+
+// NewInvestmentRepository creates a new investment repository
+func NewInvestmentRepository(db *mongo.Database) *InvestmentRepository {
+	return &InvestmentRepository{
+		collection: db.Collection("investment_transactions"),
+	}
+}
+
+// Create records an investment transaction
+func (r *InvestmentRepository) Create(transaction *models.InvestmentTransaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The service assigns the ID up front when it links transactions to the record
+	if transaction.ID == "" {
+		transaction.ID = uuid.New().String()
+	}
+	transaction.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, transaction)
+	return err
+}
+
+// GetByID retrieves an investment transaction by ID
+func (r *InvestmentRepository) GetByID(id, accountID, workspaceID string) (*models.InvestmentTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transaction models.InvestmentTransaction
+	filter := bson.M{"_id": id, "account_id": accountID, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&transaction)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// GetByAccountID retrieves all transactions of an investment account in trade order
+func (r *InvestmentRepository) GetByAccountID(accountID, workspaceID string) ([]models.InvestmentTransaction, error) {
+	return r.find(bson.M{"account_id": accountID, "workspace_id": workspaceID})
+}
+
+// GetByWorkspace retrieves all investment transactions in a workspace in trade order
+func (r *InvestmentRepository) GetByWorkspace(workspaceID string) ([]models.InvestmentTransaction, error) {
+	return r.find(bson.M{"workspace_id": workspaceID})
+}
+
+// CountBySecurity counts the transactions that reference a security
+func (r *InvestmentRepository) CountBySecurity(securityID, workspaceID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"security_id": securityID, "workspace_id": workspaceID}
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+// CountByAccountID counts the transactions of an investment account
+func (r *InvestmentRepository) CountByAccountID(accountID, workspaceID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"account_id": accountID, "workspace_id": workspaceID}
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+// Delete deletes an investment transaction
+func (r *InvestmentRepository) Delete(id, accountID, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "account_id": accountID, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// find retrieves investment transactions sorted oldest first, as FIFO lot matching requires
+func (r *InvestmentRepository) find(filter bson.M) ([]models.InvestmentTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "trade_date", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.InvestmentTransaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+
+	if transactions == nil {
+		transactions = []models.InvestmentTransaction{}
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LoanRepository handles loan payment data operations
+type LoanRepository struct {
+	collection *mongo.Collection
+}
+
+// NewLoanRepository creates a new loan repository
+func NewLoanRepository(db *mongo.Database) *LoanRepository {
+	return &LoanRepository{
+		collection: db.Collection("loan_payments"),
+	}
+}
+
+// CreatePayment records a loan payment
+func (r *LoanRepository) CreatePayment(payment *models.LoanPayment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The service assigns the ID up front when it links transactions to the record
+	if payment.ID == "" {
+		payment.ID = uuid.New().String()
+	}
+	payment.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, payment)
+	return err
+}
+
+// GetPaymentByID retrieves a loan payment by ID
+func (r *LoanRepository) GetPaymentByID(id, accountID, workspaceID string) (*models.LoanPayment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var payment models.LoanPayment
+	filter := bson.M{"_id": id, "account_id": accountID, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&payment)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// GetPaymentsByAccountID retrieves all payments of a loan account, oldest first
+func (r *LoanRepository) GetPaymentsByAccountID(accountID, workspaceID string) ([]models.LoanPayment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"account_id": accountID, "workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "payment_date", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var payments []models.LoanPayment
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+
+	if payments == nil {
+		payments = []models.LoanPayment{}
+	}
+
+	return payments, nil
+}
+
+// UpdatePaymentTransactions saves which transactions a loan payment still has
+func (r *LoanRepository) UpdatePaymentTransactions(payment *models.LoanPayment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set, unset := bson.M{}, bson.M{}
+	if payment.PrincipalTransactionID != nil {
+		set["principal_transaction_id"] = *payment.PrincipalTransactionID
+	} else {
+		unset["principal_transaction_id"] = ""
+	}
+	if payment.InterestTransactionID != nil {
+		set["interest_transaction_id"] = *payment.InterestTransactionID
+	} else {
+		unset["interest_transaction_id"] = ""
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	filter := bson.M{"_id": payment.ID, "account_id": payment.AccountID, "workspace_id": payment.WorkspaceID}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DeletePayment deletes a loan payment
+func (r *LoanRepository) DeletePayment(id, accountID, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "account_id": accountID, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
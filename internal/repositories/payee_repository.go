@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PayeeRepository handles payee data operations
+type PayeeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPayeeRepository creates a new payee repository
+func NewPayeeRepository(db *mongo.Database) *PayeeRepository {
+	return &PayeeRepository{
+		collection: db.Collection("payees"),
+	}
+}
+
+// Create creates a new payee
+func (r *PayeeRepository) Create(workspaceID, userID string, req models.CreatePayeeRequest) (*models.Payee, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	payee := &models.Payee{
+		ID:                uuid.New().String(),
+		WorkspaceID:       workspaceID,
+		UserID:            userID,
+		Name:              req.Name,
+		NormalizedName:    utils.NormalizeMerchantName(req.Name),
+		Aliases:           req.Aliases,
+		Patterns:          req.Patterns,
+		DefaultCategoryID: req.DefaultCategoryID,
+		Logo:              req.Logo,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+
+	if payee.Aliases == nil {
+		payee.Aliases = []string{}
+	}
+	if payee.Patterns == nil {
+		payee.Patterns = []string{}
+	}
+
+	_, err := r.collection.InsertOne(ctx, payee)
+	if err != nil {
+		return nil, err
+	}
+
+	return payee, nil
+}
+
+// GetByID retrieves a payee by ID
+func (r *PayeeRepository) GetByID(id, workspaceID string) (*models.Payee, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var payee models.Payee
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&payee)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &payee, nil
+}
+
+// GetByNormalizedName retrieves the payee whose name normalizes to the given text
+func (r *PayeeRepository) GetByNormalizedName(workspaceID, normalizedName string) (*models.Payee, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var payee models.Payee
+	filter := bson.M{"workspace_id": workspaceID, "normalized_name": normalizedName}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&payee)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &payee, nil
+}
+
+// GetAll retrieves all payees of a workspace sorted by name
+func (r *PayeeRepository) GetAll(workspaceID string) ([]models.Payee, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "normalized_name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var payees []models.Payee
+	if err = cursor.All(ctx, &payees); err != nil {
+		return nil, err
+	}
+
+	if payees == nil {
+		payees = []models.Payee{}
+	}
+
+	return payees, nil
+}
+
+// Update updates a payee
+func (r *PayeeRepository) Update(ctx context.Context, id, workspaceID string, req models.UpdatePayeeRequest) (*models.Payee, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	setFields := bson.M{"updated_at": time.Now()}
+	unsetFields := bson.M{}
+
+	if req.Name != nil {
+		setFields["name"] = *req.Name
+		setFields["normalized_name"] = utils.NormalizeMerchantName(*req.Name)
+	}
+	if req.Aliases != nil {
+		setFields["aliases"] = req.Aliases
+	}
+	if req.Patterns != nil {
+		setFields["patterns"] = req.Patterns
+	}
+	if req.DefaultCategoryID != nil {
+		if *req.DefaultCategoryID == "" {
+			unsetFields["default_category_id"] = ""
+		} else {
+			setFields["default_category_id"] = *req.DefaultCategoryID
+		}
+	}
+	if req.Logo != nil {
+		if *req.Logo == "" {
+			unsetFields["logo"] = ""
+		} else {
+			setFields["logo"] = *req.Logo
+		}
+	}
+
+	update := bson.M{"$set": setFields}
+	if len(unsetFields) > 0 {
+		update["$unset"] = unsetFields
+	}
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	var payee models.Payee
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&payee)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &payee, nil
+}
+
+// Delete deletes a payee
+func (r *PayeeRepository) Delete(ctx context.Context, id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// RunInTransaction runs fn in a MongoDB transaction
+// Repository methods that take a context join the transaction when they are given the one fn receives.
+func (r *PayeeRepository) RunInTransaction(fn func(ctx context.Context) error) error {
+	return runInTransaction(r.collection.Database().Client(), fn)
+}
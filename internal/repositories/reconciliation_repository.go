@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReconciliationRepository handles reconciliation data operations
+type ReconciliationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReconciliationRepository creates a new reconciliation repository
+func NewReconciliationRepository(db *mongo.Database) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		collection: db.Collection("reconciliations"),
+	}
+}
+
+// Create starts a new reconciliation
+func (r *ReconciliationRepository) Create(workspaceID, userID, accountID string, req models.CreateReconciliationRequest) (*models.Reconciliation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reconciliation := &models.Reconciliation{
+		ID:               uuid.New().String(),
+		WorkspaceID:      workspaceID,
+		UserID:           userID,
+		AccountID:        accountID,
+		StatementDate:    req.StatementDate,
+		StatementBalance: *req.StatementBalance,
+		Status:           models.ReconciliationStatusInProgress,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+
+	_, err := r.collection.InsertOne(ctx, reconciliation)
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// GetByID retrieves a reconciliation of an account by ID
+func (r *ReconciliationRepository) GetByID(id, accountID, workspaceID string) (*models.Reconciliation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var reconciliation models.Reconciliation
+	filter := bson.M{"_id": id, "account_id": accountID, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&reconciliation)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+// GetInProgress retrieves the unfinished reconciliation of an account, if any
+func (r *ReconciliationRepository) GetInProgress(accountID, workspaceID string) (*models.Reconciliation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var reconciliation models.Reconciliation
+	filter := bson.M{
+		"account_id":   accountID,
+		"workspace_id": workspaceID,
+		"status":       models.ReconciliationStatusInProgress,
+	}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&reconciliation)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+// GetByAccountID retrieves the reconciliations of an account, newest statement first
+func (r *ReconciliationRepository) GetByAccountID(accountID, workspaceID string) ([]models.Reconciliation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"account_id": accountID, "workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "statement_date", Value: -1}, {Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reconciliations []models.Reconciliation
+	if err = cursor.All(ctx, &reconciliations); err != nil {
+		return nil, err
+	}
+
+	if reconciliations == nil {
+		reconciliations = []models.Reconciliation{}
+	}
+
+	return reconciliations, nil
+}
+
+// Complete marks a reconciliation as finished
+func (r *ReconciliationRepository) Complete(id, workspaceID string, reconciledCount int) (*models.Reconciliation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{"_id": id, "workspace_id": workspaceID, "status": models.ReconciliationStatusInProgress}
+	update := bson.M{
+		"$set": bson.M{
+			"status":           models.ReconciliationStatusCompleted,
+			"reconciled_count": reconciledCount,
+			"completed_at":     now,
+			"updated_at":       now,
+		},
+	}
+
+	var reconciliation models.Reconciliation
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&reconciliation)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+// Delete deletes a reconciliation
+func (r *ReconciliationRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SecurityRepository handles security and price data operations
+type SecurityRepository struct {
+	collection      *mongo.Collection
+	priceCollection *mongo.Collection
+}
+
+// NewSecurityRepository creates a new security repository
+func NewSecurityRepository(db *mongo.Database) *SecurityRepository {
+	return &SecurityRepository{
+		collection:      db.Collection("securities"),
+		priceCollection: db.Collection("security_prices"),
+	}
+}
+
+// Create creates a new security
+func (r *SecurityRepository) Create(workspaceID, userID string, req models.CreateSecurityRequest) (*models.Security, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	security := &models.Security{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Symbol:      strings.ToUpper(strings.TrimSpace(req.Symbol)),
+		Name:        req.Name,
+		Type:        req.Type,
+		Currency:    req.Currency,
+		Exchange:    req.Exchange,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err := r.collection.InsertOne(ctx, security)
+	if err != nil {
+		return nil, err
+	}
+
+	return security, nil
+}
+
+// GetByID retrieves a security by ID
+func (r *SecurityRepository) GetByID(id, workspaceID string) (*models.Security, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var security models.Security
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&security)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// GetBySymbol retrieves a security by its symbol
+func (r *SecurityRepository) GetBySymbol(symbol, workspaceID string) (*models.Security, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var security models.Security
+	filter := bson.M{"symbol": strings.ToUpper(strings.TrimSpace(symbol)), "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&security)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// GetAll retrieves all securities in a workspace ordered by symbol
+func (r *SecurityRepository) GetAll(workspaceID string) ([]models.Security, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "symbol", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var securities []models.Security
+	if err = cursor.All(ctx, &securities); err != nil {
+		return nil, err
+	}
+
+	if securities == nil {
+		securities = []models.Security{}
+	}
+
+	return securities, nil
+}
+
+// Update updates a security
+func (r *SecurityRepository) Update(id, workspaceID string, req models.UpdateSecurityRequest) (*models.Security, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	setFields := update["$set"].(bson.M)
+
+	if req.Name != nil {
+		setFields["name"] = *req.Name
+	}
+	if req.Type != nil {
+		setFields["type"] = *req.Type
+	}
+	if req.Exchange != nil {
+		setFields["exchange"] = *req.Exchange
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var security models.Security
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&security)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// Delete deletes a security and its price history
+func (r *SecurityRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_, err = r.priceCollection.DeleteMany(ctx, bson.M{"security_id": id, "workspace_id": workspaceID})
+	return err
+}
+
+// SavePrice records the price of a security for a date, replacing an earlier price recorded for the same date,
+// and moves the security's last price forward when the date is not older than the current one
+func (r *SecurityRepository) SavePrice(securityID, workspaceID string, price float64, priceDate time.Time, source string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	priceFilter := bson.M{"security_id": securityID, "workspace_id": workspaceID, "price_date": priceDate}
+	priceUpdate := bson.M{
+		"$set": bson.M{
+			"price":  price,
+			"source": source,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        uuid.New().String(),
+			"created_at": now,
+		},
+	}
+	if _, err := r.priceCollection.UpdateOne(ctx, priceFilter, priceUpdate, options.Update().SetUpsert(true)); err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":          securityID,
+		"workspace_id": workspaceID,
+		"$or": []bson.M{
+			{"last_price_date": bson.M{"$exists": false}},
+			{"last_price_date": bson.M{"$lte": priceDate}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"last_price":      price,
+			"last_price_date": priceDate,
+			"updated_at":      now,
+		},
+	}
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// GetPrices retrieves the price history of a security, newest first
+func (r *SecurityRepository) GetPrices(securityID, workspaceID string, limit int) ([]models.SecurityPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"security_id": securityID, "workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "price_date", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := r.priceCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var prices []models.SecurityPrice
+	if err = cursor.All(ctx, &prices); err != nil {
+		return nil, err
+	}
+
+	if prices == nil {
+		prices = []models.SecurityPrice{}
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SharedExpenseRepository handles shared expense and settlement data operations
+type SharedExpenseRepository struct {
+	collection           *mongo.Collection
+	settlementCollection *mongo.Collection
+}
+
+// NewSharedExpenseRepository creates a new shared expense repository
+func NewSharedExpenseRepository(db *mongo.Database) *SharedExpenseRepository {
+	return &SharedExpenseRepository{
+		collection:           db.Collection("shared_expenses"),
+		settlementCollection: db.Collection("settlements"),
+	}
+}
+
+// Create creates a new shared expense
+func (r *SharedExpenseRepository) Create(expense *models.SharedExpense) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	expense.ID = uuid.New().String()
+	expense.CreatedAt = time.Now()
+	expense.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, expense)
+	return err
+}
+
+// GetByID retrieves a shared expense of a workspace by ID
+func (r *SharedExpenseRepository) GetByID(id, workspaceID string) (*models.SharedExpense, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var expense models.SharedExpense
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&expense)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &expense, nil
+}
+
+// GetByTransactionID retrieves the shared expense attached to a transaction
+func (r *SharedExpenseRepository) GetByTransactionID(transactionID, workspaceID string) (*models.SharedExpense, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var expense models.SharedExpense
+	filter := bson.M{"transaction_id": transactionID, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&expense)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &expense, nil
+}
+
+// GetAll retrieves shared expenses of a workspace, optionally limited to a contact or a group, oldest first
+func (r *SharedExpenseRepository) GetAll(workspaceID, contactID, groupID string) ([]models.SharedExpense, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	if contactID != "" {
+		filter["$or"] = []bson.M{
+			{"paid_by": contactID},
+			{"shares.participant_id": contactID},
+		}
+	}
+	if groupID != "" {
+		filter["group_id"] = groupID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "expense_date", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var expenses []models.SharedExpense
+	if err = cursor.All(ctx, &expenses); err != nil {
+		return nil, err
+	}
+
+	if expenses == nil {
+		expenses = []models.SharedExpense{}
+	}
+
+	return expenses, nil
+}
+
+// Delete deletes a shared expense
+func (r *SharedExpenseRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// CreateSettlement records a settlement with a contact
+func (r *SharedExpenseRepository) CreateSettlement(settlement *models.Settlement) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The service assigns the ID up front when it links transactions to the record
+	if settlement.ID == "" {
+		settlement.ID = uuid.New().String()
+	}
+	settlement.CreatedAt = time.Now()
+
+	_, err := r.settlementCollection.InsertOne(ctx, settlement)
+	return err
+}
+
+// GetSettlements retrieves settlements of a workspace, optionally limited to a contact or a group, oldest first
+func (r *SharedExpenseRepository) GetSettlements(workspaceID, contactID, groupID string) ([]models.Settlement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	if contactID != "" {
+		filter["contact_id"] = contactID
+	}
+	if groupID != "" {
+		filter["group_id"] = groupID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "settled_at", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.settlementCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var settlements []models.Settlement
+	if err = cursor.All(ctx, &settlements); err != nil {
+		return nil, err
+	}
+
+	if settlements == nil {
+		settlements = []models.Settlement{}
+	}
+
+	return settlements, nil
+}
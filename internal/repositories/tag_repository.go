@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TagRepository handles tag settings data operations
+type TagRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *mongo.Database) *TagRepository {
+	return &TagRepository{
+		collection: db.Collection("tags"),
+	}
+}
+
+// EnsureTagIndexes creates the index that keeps one settings document per tag name
+func EnsureTagIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "workspace_id", Value: 1},
+			{Key: "name", Value: 1},
+		},
+		Options: options.Index().SetName("tag_name").SetUnique(true),
+	}
+
+	_, err := db.Collection("tags").Indexes().CreateOne(ctx, index)
+	return err
+}
+
+// GetAll retrieves the settings of all tags in a workspace
+func (r *TagRepository) GetAll(workspaceID string) ([]models.Tag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tags []models.Tag
+	if err = cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+
+	return tags, nil
+}
+
+// GetByName retrieves the settings of a tag
+func (r *TagRepository) GetByName(workspaceID, name string) (*models.Tag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var tag models.Tag
+	filter := bson.M{"workspace_id": workspaceID, "name": name}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&tag)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// SetColor sets or, with an empty color, clears the color of a tag, creating its settings when needed
+func (r *TagRepository) SetColor(workspaceID, name, color string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{"updated_at": time.Now()},
+		"$setOnInsert": bson.M{
+			"_id":        uuid.New().String(),
+			"created_at": time.Now(),
+		},
+	}
+	if color == "" {
+		update["$unset"] = bson.M{"color": ""}
+	} else {
+		update["$set"].(bson.M)["color"] = color
+	}
+
+	filter := bson.M{"workspace_id": workspaceID, "name": name}
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Rename moves the settings of a tag to a new name
+func (r *TagRepository) Rename(workspaceID, name, newName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID, "name": name}
+	update := bson.M{"$set": bson.M{"name": newName, "updated_at": time.Now()}}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// DeleteByNames deletes the settings of the given tags
+func (r *TagRepository) DeleteByNames(workspaceID string, names []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID, "name": bson.M{"$in": names}}
+	_, err := r.collection.DeleteMany(ctx, filter)
+	return err
+}
@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TemplateRepository handles transaction template data operations
+type TemplateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTemplateRepository creates a new transaction template repository
+func NewTemplateRepository(db *mongo.Database) *TemplateRepository {
+	return &TemplateRepository{
+		collection: db.Collection("transaction_templates"),
+	}
+}
+
+// Create creates a new transaction template
+func (r *TemplateRepository) Create(workspaceID, userID string, req models.CreateTransactionTemplateRequest) (*models.TransactionTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	template := &models.TransactionTemplate{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Name:        req.Name,
+		AccountID:   req.AccountID,
+		CategoryID:  req.CategoryID,
+		Type:        req.Type,
+		Amount:      req.Amount,
+		Merchant:    req.Merchant,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Notes:       req.Notes,
+		Favorite:    req.Favorite,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if template.Tags == nil {
+		template.Tags = []string{}
+	}
+
+	_, err := r.collection.InsertOne(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetByID retrieves a transaction template by ID
+func (r *TemplateRepository) GetByID(id, workspaceID string) (*models.TransactionTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var template models.TransactionTemplate
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&template)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// GetAll retrieves all templates of a workspace, favorites first and then by how often they are used
+func (r *TemplateRepository) GetAll(workspaceID string) ([]models.TransactionTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{
+		{Key: "favorite", Value: -1},
+		{Key: "usage_count", Value: -1},
+		{Key: "last_used_at", Value: -1},
+		{Key: "name", Value: 1},
+	})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var templates []models.TransactionTemplate
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+
+	if templates == nil {
+		templates = []models.TransactionTemplate{}
+	}
+
+	return templates, nil
+}
+
+// Update updates a transaction template
+func (r *TemplateRepository) Update(id, workspaceID string, req models.UpdateTransactionTemplateRequest) (*models.TransactionTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setFields := bson.M{"updated_at": time.Now()}
+	unsetFields := bson.M{}
+
+	if req.Name != nil {
+		setFields["name"] = *req.Name
+	}
+	if req.AccountID != nil {
+		setFields["account_id"] = *req.AccountID
+	}
+	if req.Type != nil {
+		setFields["type"] = *req.Type
+	}
+	if req.Tags != nil {
+		setFields["tags"] = req.Tags
+	}
+	if req.Favorite != nil {
+		setFields["favorite"] = *req.Favorite
+	}
+	if req.Amount != nil {
+		if *req.Amount == 0 {
+			unsetFields["amount"] = ""
+		} else {
+			setFields["amount"] = *req.Amount
+		}
+	}
+
+	optional := map[string]*string{
+		"category_id": req.CategoryID,
+		"merchant":    req.Merchant,
+		"description": req.Description,
+		"notes":       req.Notes,
+	}
+	for field, value := range optional {
+		if value == nil {
+			continue
+		}
+		if *value == "" {
+			unsetFields[field] = ""
+		} else {
+			setFields[field] = *value
+		}
+	}
+
+	update := bson.M{"$set": setFields}
+	if len(unsetFields) > 0 {
+		update["$unset"] = unsetFields
+	}
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	var template models.TransactionTemplate
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&template)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// RecordUsage counts a transaction created from a template
+func (r *TemplateRepository) RecordUsage(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	update := bson.M{
+		"$inc": bson.M{"usage_count": 1},
+		"$set": bson.M{"last_used_at": time.Now()},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// Delete deletes a transaction template
+func (r *TemplateRepository) Delete(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
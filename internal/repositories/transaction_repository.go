@@ -3,6 +3,9 @@ package repositories
 import (
 	"context"
 	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -26,13 +29,84 @@ func NewTransactionRepository(db *mongo.Database) *TransactionRepository {
 	}
 }
 
+// EnsureTransactionIndexes creates the list and search indexes of the transactions collection and fills in
+// the normalized search fields and status of transactions created before those existed
+func EnsureTransactionIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	collection := db.Collection("transactions")
+
+	indexes := []mongo.IndexModel{
+		// Language "none" disables stemming and stop words, which are English-only and would mangle Vietnamese
+		{
+			Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "search.text", Value: "text"}},
+			Options: options.Index().
+				SetName("transaction_search").
+				SetDefaultLanguage("none"),
+		},
+		// Match the list sort orders so cursor pages are served straight from the index
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "transaction_date", Value: -1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("transaction_list_by_date"),
+		},
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "amount", Value: -1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("transaction_list_by_amount"),
+		},
+		// Trash listing and purge
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetName("transaction_trash").SetSparse(true),
+		},
+		// Refunds of an expense
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "refund_of_id", Value: 1}},
+			Options: options.Index().SetName("transaction_refunds").SetSparse(true),
+		},
+		// Payee filter, merge and unlink
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "payee_id", Value: 1}},
+			Options: options.Index().SetName("transaction_payee").SetSparse(true),
+		},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return err
+	}
+
+	statusUpdate := bson.M{"$set": bson.M{"status": models.TransactionStatusPending}}
+	if _, err := collection.UpdateMany(ctx, bson.M{"status": bson.M{"$exists": false}}, statusUpdate); err != nil {
+		return err
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"search": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var transaction models.Transaction
+		if err := cursor.Decode(&transaction); err != nil {
+			return err
+		}
+		update := bson.M{"$set": bson.M{"search": buildSearchIndex(&transaction)}}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": transaction.ID}, update); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
 // Create creates a new transaction
-func (r *TransactionRepository) Create(userID string, req models.CreateTransactionRequest) (*models.Transaction, error) {
+func (r *TransactionRepository) Create(workspaceID, userID string, req models.CreateTransactionRequest) (*models.Transaction, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	transaction := &models.Transaction{
 		ID:              uuid.New().String(),
+		WorkspaceID:     workspaceID,
 		UserID:          userID,
 		AccountID:       req.AccountID,
 		ToAccountID:     req.ToAccountID,
@@ -40,19 +114,31 @@ func (r *TransactionRepository) Create(userID string, req models.CreateTransacti
 		Type:            req.Type,
 		Amount:          req.Amount,
 		Merchant:        req.Merchant,
+		PayeeID:         req.PayeeID,
 		Description:     req.Description,
 		TransactionDate: req.TransactionDate,
 		Notes:           req.Notes,
 		Tags:            req.Tags,
 		AttachmentURL:   req.AttachmentURL,
+		RefundOfID:      req.RefundOfID,
+		RefundKind:      req.RefundKind,
+		LinkedTo:        req.LinkedTo,
+		Status:          req.Status,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
+	if !req.RefundOfDate.IsZero() {
+		transaction.RefundOfDate = &req.RefundOfDate
+	}
 
 	// Initialize empty tags array if nil
 	if transaction.Tags == nil {
 		transaction.Tags = []string{}
 	}
+	if transaction.Status == "" {
+		transaction.Status = models.TransactionStatusPending
+	}
+	transaction.Search = buildSearchIndex(transaction)
 
 	_, err := r.collection.InsertOne(ctx, transaction)
 	if err != nil {
@@ -63,12 +149,12 @@ func (r *TransactionRepository) Create(userID string, req models.CreateTransacti
 }
 
 // GetByID retrieves a transaction by ID
-func (r *TransactionRepository) GetByID(id, userID string) (*models.Transaction, error) {
+func (r *TransactionRepository) GetByID(id, workspaceID string) (*models.Transaction, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var transaction models.Transaction
-	filter := bson.M{"_id": id, "user_id": userID}
+	filter := bson.M{"_id": id, "workspace_id": workspaceID, "deleted_at": nil}
 
 	err := r.collection.FindOne(ctx, filter).Decode(&transaction)
 	if err == mongo.ErrNoDocuments {
@@ -81,157 +167,160 @@ func (r *TransactionRepository) GetByID(id, userID string) (*models.Transaction,
 	return &transaction, nil
 }
 
-// GetAll retrieves all transactions for a user with filters
-func (r *TransactionRepository) GetAll(userID string, filters models.TransactionFilterQuery) ([]models.Transaction, int, error) {
+// GetAll retrieves all transactions in a workspace with filters
+func (r *TransactionRepository) GetAll(workspaceID string, filters models.TransactionFilterQuery) ([]models.Transaction, int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Build filter query
-	filter := bson.M{"user_id": userID}
+	filter, err := buildFilter(workspaceID, filters)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	// Account filter
-	if filters.AccountID != "" {
-		// Include transactions where AccountID or ToAccountID matches (for transfers)
-		filter["$or"] = []bson.M{
-			{"account_id": filters.AccountID},
-			{"to_account_id": filters.AccountID},
-		}
+	// Get total count
+	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Category filter
-	if filters.CategoryID != "" {
-		filter["category_id"] = filters.CategoryID
+	// Get transactions with pagination
+	opts := options.Find()
+	opts.SetSort(transactionSort(filters))
+
+	// Apply pagination
+	filters.SetDefaults()
+	opts.SetLimit(int64(filters.Limit))
+	opts.SetSkip(int64(filters.GetOffset()))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
 	}
+	defer cursor.Close(ctx)
 
-	// Type filter
-	if filters.Type != "" {
-		filter["type"] = filters.Type
+	var transactions []models.Transaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, 0, err
 	}
 
-	// Date range filters
-	if filters.StartDate != "" {
-		startDate, err := time.Parse("2006-01-02", filters.StartDate)
-		if err == nil {
-			if filter["transaction_date"] == nil {
-				filter["transaction_date"] = bson.M{}
-			}
-			filter["transaction_date"].(bson.M)["$gte"] = startDate
-		}
+	if transactions == nil {
+		transactions = []models.Transaction{}
 	}
 
-	if filters.EndDate != "" {
-		endDate, err := time.Parse("2006-01-02", filters.EndDate)
-		if err == nil {
-			// Add one day and use $lt to include the entire end date
-			endDate = endDate.AddDate(0, 0, 1)
-			if filter["transaction_date"] == nil {
-				filter["transaction_date"] = bson.M{}
-			}
-			filter["transaction_date"].(bson.M)["$lt"] = endDate
-		}
+	return transactions, int(totalCount), nil
+}
+
+// GetPage retrieves up to limit transactions that sort after the cursor, or the first page when after is nil
+// It relies on the sort keys rather than Skip, so pages stay stable while transactions are added
+func (r *TransactionRepository) GetPage(workspaceID string, filters models.TransactionFilterQuery, after *models.TransactionCursor, limit int) ([]models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter, err := buildFilter(workspaceID, filters)
+	if err != nil {
+		return nil, err
 	}
 
-	// Month filter (YYYY-MM format)
-	if filters.Month != "" {
-		// Parse month string
-		parts := strings.Split(filters.Month, "-")
-		if len(parts) == 2 {
-			year, _ := strconv.Atoi(parts[0])
-			month, _ := strconv.Atoi(parts[1])
-			
-			startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-			endOfMonth := startOfMonth.AddDate(0, 1, 0)
-			
-			filter["transaction_date"] = bson.M{
-				"$gte": startOfMonth,
-				"$lt":  endOfMonth,
-			}
+	if after != nil {
+		field, order := transactionSortField(filters)
+		var value interface{}
+		if field == "amount" {
+			value = *after.Amount
+		} else {
+			value = *after.Date
 		}
-	}
 
-	// Amount range filters
-	if filters.MinAmount != "" {
-		minAmount, err := strconv.ParseFloat(filters.MinAmount, 64)
-		if err == nil {
-			if filter["amount"] == nil {
-				filter["amount"] = bson.M{}
-			}
-			filter["amount"].(bson.M)["$gte"] = minAmount
+		fieldOperator := "$lt"
+		if order == 1 {
+			fieldOperator = "$gt"
 		}
-	}
 
-	if filters.MaxAmount != "" {
-		maxAmount, err := strconv.ParseFloat(filters.MaxAmount, 64)
-		if err == nil {
-			if filter["amount"] == nil {
-				filter["amount"] = bson.M{}
-			}
-			filter["amount"].(bson.M)["$lte"] = maxAmount
+		// Ties on the sort field fall back to created_at and _id, both descending
+		keyset := bson.M{"$or": []bson.M{
+			{field: bson.M{fieldOperator: value}},
+			{field: value, "created_at": bson.M{"$lt": after.CreatedAt}},
+			{field: value, "created_at": after.CreatedAt, "_id": bson.M{"$lt": after.ID}},
+		}}
+
+		if conditions, ok := filter["$and"].([]bson.M); ok {
+			filter["$and"] = append(conditions, keyset)
+		} else {
+			filter["$and"] = []bson.M{keyset}
 		}
 	}
 
-	// Search filter (search in merchant, description, notes)
-	if filters.Search != "" {
-		searchRegex := primitive.Regex{Pattern: filters.Search, Options: "i"}
-		filter["$or"] = []bson.M{
-			{"merchant": searchRegex},
-			{"description": searchRegex},
-			{"notes": searchRegex},
-		}
+	opts := options.Find().SetSort(transactionSort(filters)).SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, err
 	}
 
-	// Tags filter
-	if filters.Tags != "" {
-		tags := strings.Split(filters.Tags, ",")
-		filter["tags"] = bson.M{"$in": tags}
+	if transactions == nil {
+		transactions = []models.Transaction{}
 	}
 
-	// Get total count
-	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	return transactions, nil
+}
+
+// Count counts the transactions matching the filters
+func (r *TransactionRepository) Count(workspaceID string, filters models.TransactionFilterQuery) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter, err := buildFilter(workspaceID, filters)
 	if err != nil {
-		return nil, 0, err
+		return 0, err
 	}
 
-	// Build sort options
-	sortField := "transaction_date"
-	if filters.SortBy == "amount" {
-		sortField = "amount"
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
 	}
-	
-	sortOrder := -1 // Default descending
-	if filters.SortOrder == "asc" {
-		sortOrder = 1
+
+	return int(count), nil
+}
+
+// GetMatching retrieves up to limit transactions matching the filters, in list order and without paging
+func (r *TransactionRepository) GetMatching(workspaceID string, filters models.TransactionFilterQuery, limit int) ([]models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter, err := buildFilter(workspaceID, filters)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get transactions with pagination
 	opts := options.Find()
-	opts.SetSort(bson.D{{Key: sortField, Value: sortOrder}, {Key: "created_at", Value: -1}})
-	
-	// Apply pagination
-	filters.SetDefaults()
-	opts.SetLimit(int64(filters.Limit))
-	opts.SetSkip(int64(filters.GetOffset()))
+	opts.SetSort(transactionSort(filters))
+	opts.SetLimit(int64(limit))
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var transactions []models.Transaction
 	if err = cursor.All(ctx, &transactions); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	if transactions == nil {
 		transactions = []models.Transaction{}
 	}
 
-	return transactions, int(totalCount), nil
+	return transactions, nil
 }
 
 // Update updates a transaction
-func (r *TransactionRepository) Update(id, userID string, req models.UpdateTransactionRequest) (*models.Transaction, error) {
+func (r *TransactionRepository) Update(id, workspaceID string, req models.UpdateTransactionRequest) (*models.Transaction, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -241,95 +330,638 @@ func (r *TransactionRepository) Update(id, userID string, req models.UpdateTrans
 		},
 	}
 
+	setFields := update["$set"].(bson.M)
+	unsetFields := bson.M{}
+
+	// setOptional stores a provided optional field, or removes it when it is provided empty
+	setOptional := func(field string, value *string) {
+		if value == nil {
+			return
+		}
+		if *value == "" {
+			unsetFields[field] = ""
+			return
+		}
+		setFields[field] = *value
+	}
+
 	// Only update provided fields
 	if req.AccountID != nil {
-		update["$set"].(bson.M)["account_id"] = *req.AccountID
-	}
-	if req.ToAccountID != nil {
-		update["$set"].(bson.M)["to_account_id"] = *req.ToAccountID
-	}
-	if req.CategoryID != nil {
-		update["$set"].(bson.M)["category_id"] = *req.CategoryID
+		setFields["account_id"] = *req.AccountID
 	}
+	setOptional("to_account_id", req.ToAccountID)
+	setOptional("category_id", req.CategoryID)
 	if req.Type != nil {
-		update["$set"].(bson.M)["type"] = *req.Type
+		setFields["type"] = *req.Type
 	}
 	if req.Amount != nil {
-		update["$set"].(bson.M)["amount"] = *req.Amount
+		setFields["amount"] = *req.Amount
 	}
-	if req.Merchant != nil {
-		update["$set"].(bson.M)["merchant"] = *req.Merchant
+	setOptional("merchant", req.Merchant)
+	setOptional("payee_id", req.PayeeID)
+	setOptional("description", req.Description)
+	if req.TransactionDate != nil {
+		setFields["transaction_date"] = *req.TransactionDate
+	}
+	setOptional("notes", req.Notes)
+	if req.Tags != nil {
+		setFields["tags"] = req.Tags
 	}
-	if req.Description != nil {
-		update["$set"].(bson.M)["description"] = *req.Description
+	setOptional("attachment_url", req.AttachmentURL)
+	if req.Status != nil {
+		setFields["status"] = *req.Status
 	}
-	if req.TransactionDate != nil {
-		update["$set"].(bson.M)["transaction_date"] = *req.TransactionDate
+
+	if len(unsetFields) > 0 {
+		update["$unset"] = unsetFields
+	}
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID, "deleted_at": nil}
+
+	var transaction models.Transaction
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&transaction)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the search fields in step with the fields they are built from
+	if req.Merchant != nil || req.Description != nil || req.Notes != nil || req.Tags != nil {
+		transaction.Search = buildSearchIndex(&transaction)
+		searchUpdate := bson.M{"$set": bson.M{"search": transaction.Search}}
+		if _, err := r.collection.UpdateOne(ctx, filter, searchUpdate); err != nil {
+			return nil, err
+		}
+	}
+
+	return &transaction, nil
+}
+
+// Delete moves a transaction to the trash
+func (r *TransactionRepository) Delete(id, workspaceID string) (*models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transaction models.Transaction
+	filter := bson.M{"_id": id, "workspace_id": workspaceID, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now()}}
+
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&transaction)
+	if err == mongo.ErrNoDocuments {
+		return nil, mongo.ErrNoDocuments
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// GetDeletedByID retrieves a transaction from the trash
+func (r *TransactionRepository) GetDeletedByID(id, workspaceID string) (*models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transaction models.Transaction
+	filter := bson.M{"_id": id, "workspace_id": workspaceID, "deleted_at": bson.M{"$ne": nil}}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&transaction)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// GetTrash retrieves the transactions in the trash, most recently deleted first
+func (r *TransactionRepository) GetTrash(workspaceID string, pagination models.PaginationQuery) ([]models.Transaction, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID, "deleted_at": bson.M{"$ne": nil}}
+
+	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find()
+	opts.SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}})
+	opts.SetLimit(int64(pagination.Limit))
+	opts.SetSkip(int64(pagination.GetOffset()))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, 0, err
+	}
+
+	if transactions == nil {
+		transactions = []models.Transaction{}
+	}
+
+	return transactions, int(totalCount), nil
+}
+
+// Restore takes a transaction out of the trash
+func (r *TransactionRepository) Restore(id, workspaceID string) (*models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transaction models.Transaction
+	filter := bson.M{"_id": id, "workspace_id": workspaceID, "deleted_at": bson.M{"$ne": nil}}
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&transaction)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// DeletePermanently removes a transaction from the trash for good
+func (r *TransactionRepository) DeletePermanently(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID, "deleted_at": bson.M{"$ne": nil}}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DeleteImmediately removes a live transaction without moving it to the trash
+func (r *TransactionRepository) DeleteImmediately(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID, "deleted_at": nil}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// GetByAttachmentURL retrieves the transaction, live or in the trash, whose attachment_url is the given URL
+func (r *TransactionRepository) GetByAttachmentURL(workspaceID, url string) (*models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transaction models.Transaction
+	filter := bson.M{"workspace_id": workspaceID, "attachment_url": url}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&transaction)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// AttachmentURLInUse reports whether any transaction, in any workspace, still points at a URL through attachment_url
+func (r *TransactionRepository) AttachmentURLInUse(url string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"attachment_url": url}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetDeletedBefore retrieves the transactions of every workspace that were trashed before the cutoff
+func (r *TransactionRepository) GetDeletedBefore(cutoff time.Time) ([]models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"deleted_at": bson.M{"$lt": cutoff}}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// BulkUpdateCategory updates category for multiple transactions
+func (r *TransactionRepository) BulkUpdateCategory(workspaceID string, transactionIDs []string, categoryID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":          bson.M{"$in": transactionIDs},
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
+		"type":         bson.M{"$ne": "transfer"}, // Don't update transfers
+		"refund_of_id": nil,                       // Refunds keep the category of their original expense
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"category_id": categoryID,
+			"updated_at":  time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// ReassignCategory moves the transactions of a category to another category, including those in the trash
+// It returns the moved transactions as they were before the change.
+func (r *TransactionRepository) ReassignCategory(ctx context.Context, workspaceID, fromCategoryID, toCategoryID string) ([]models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"workspace_id": workspaceID,
+		"category_id":  fromCategoryID,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		return []models.Transaction{}, nil
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"category_id": toCategoryID,
+			"updated_at":  time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// ReassignPayee links the transactions of some payees to another payee, including those in the trash
+// It returns the transactions as they were before the change.
+func (r *TransactionRepository) ReassignPayee(ctx context.Context, workspaceID string, fromPayeeIDs []string, toPayeeID string) ([]models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"workspace_id": workspaceID,
+		"payee_id":     bson.M{"$in": fromPayeeIDs},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		return []models.Transaction{}, nil
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"payee_id":   toPayeeID,
+			"updated_at": time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// UnlinkPayee removes a payee from all transactions linked to it, including those in the trash
+func (r *TransactionRepository) UnlinkPayee(workspaceID, payeeID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID, "payee_id": payeeID}
+	update := bson.M{
+		"$unset": bson.M{"payee_id": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// GetTagUsage counts how many transactions use each tag, most used first
+func (r *TransactionRepository) GetTagUsage(workspaceID string) ([]models.TagUsage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"workspace_id": workspaceID, "deleted_at": nil}},
+		{"$unwind": "$tags"},
+		{"$group": bson.M{
+			"_id":          "$tags",
+			"usage_count":  bson.M{"$sum": 1},
+			"last_used_at": bson.M{"$max": "$transaction_date"},
+		}},
+		{"$sort": bson.D{{Key: "usage_count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var usage []models.TagUsage
+	if err := cursor.All(ctx, &usage); err != nil {
+		return nil, err
+	}
+
+	if usage == nil {
+		usage = []models.TagUsage{}
+	}
+
+	return usage, nil
+}
+
+// RewriteTags replaces the given tags with another tag on every transaction of a workspace, including the trash
+// The transactions are read and rewritten in one MongoDB transaction, so a tag edited meanwhile is not lost and the
+// rewrite applies to all of them or none; the search fields are rebuilt with the new tags. Reconciled transactions
+// are locked, so nothing is rewritten while one of them has a replaced tag. It returns the rewritten transactions
+// before and after the change.
+func (r *TransactionRepository) RewriteTags(workspaceID string, fromTags []string, toTag string) ([]models.Transaction, []models.Transaction, error) {
+	replaced := make(map[string]bool, len(fromTags))
+	for _, tag := range fromTags {
+		replaced[tag] = true
+	}
+
+	var before, after []models.Transaction
+	err := runInTransaction(r.collection.Database().Client(), func(ctx context.Context) error {
+		before, after = nil, nil
+
+		filter := bson.M{"workspace_id": workspaceID, "tags": bson.M{"$in": fromTags}}
+		cursor, err := r.collection.Find(ctx, filter)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		if err := cursor.All(ctx, &before); err != nil {
+			return err
+		}
+
+		locked := 0
+		for _, transaction := range before {
+			if transaction.Status == models.TransactionStatusReconciled {
+				locked++
+			}
+		}
+		if locked > 0 {
+			return fmt.Errorf("%d reconciled transactions use the tag; unlock them before changing it", locked)
+		}
+
+		writes := make([]mongo.WriteModel, 0, len(before))
+		for _, transaction := range before {
+			tags := []string{}
+			seen := map[string]bool{}
+			for _, tag := range transaction.Tags {
+				if replaced[tag] {
+					tag = toTag
+				}
+				if !seen[tag] {
+					seen[tag] = true
+					tags = append(tags, tag)
+				}
+			}
+
+			transaction.Tags = tags
+			transaction.UpdatedAt = time.Now()
+			after = append(after, transaction)
+
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": transaction.ID, "workspace_id": workspaceID}).
+				SetUpdate(bson.M{"$set": bson.M{
+					"tags":       tags,
+					"search":     buildSearchIndex(&transaction),
+					"updated_at": transaction.UpdatedAt,
+				}}))
+		}
+		if len(writes) == 0 {
+			return nil
+		}
+
+		_, err = r.collection.BulkWrite(ctx, writes)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return before, after, nil
+}
+
+// GetRefunds retrieves the refunds and reimbursements of some expenses, oldest first
+func (r *TransactionRepository) GetRefunds(workspaceID string, originalIDs []string) ([]models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
+		"refund_of_id": bson.M{"$in": originalIDs},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "transaction_date", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+
+	if transactions == nil {
+		transactions = []models.Transaction{}
+	}
+
+	return transactions, nil
+}
+
+// SumRefunds totals the refunds whose original expense falls within a date range, optionally for one category
+func (r *TransactionRepository) SumRefunds(workspaceID string, startDate, endDate time.Time, categoryID string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	match := bson.M{
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
+		"refund_of_date": bson.M{
+			"$gte": startDate,
+			"$lte": endDate,
+		},
+	}
+	if categoryID != "" {
+		match["category_id"] = categoryID
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{"_id": nil, "total": bson.M{"$sum": "$amount"}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
 	}
-	if req.Notes != nil {
-		update["$set"].(bson.M)["notes"] = *req.Notes
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total float64 `bson:"total"`
 	}
-	if req.Tags != nil {
-		update["$set"].(bson.M)["tags"] = req.Tags
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
 	}
-	if req.AttachmentURL != nil {
-		update["$set"].(bson.M)["attachment_url"] = *req.AttachmentURL
+	if len(results) == 0 {
+		return 0, nil
 	}
 
-	filter := bson.M{"_id": id, "user_id": userID}
+	return results[0].Total, nil
+}
 
-	var transaction models.Transaction
-	err := r.collection.FindOneAndUpdate(
-		ctx,
-		filter,
-		update,
-		options.FindOneAndUpdate().SetReturnDocument(options.After),
-	).Decode(&transaction)
+// SyncRefunds copies the category and date of an expense to its refunds, including those in the trash
+func (r *TransactionRepository) SyncRefunds(workspaceID, originalID string, categoryID *string, transactionDate time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	if err == mongo.ErrNoDocuments {
-		return nil, nil
+	filter := bson.M{"workspace_id": workspaceID, "refund_of_id": originalID}
+	set := bson.M{"refund_of_date": transactionDate, "updated_at": time.Now()}
+	update := bson.M{"$set": set}
+	if categoryID != nil {
+		set["category_id"] = *categoryID
+	} else {
+		update["$unset"] = bson.M{"category_id": ""}
 	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return &transaction, nil
+	return result.ModifiedCount, nil
 }
 
-// Delete deletes a transaction
-func (r *TransactionRepository) Delete(id, userID string) (*models.Transaction, error) {
+// BulkDelete moves multiple transactions to the trash
+func (r *TransactionRepository) BulkDelete(workspaceID string, transactionIDs []string) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var transaction models.Transaction
-	filter := bson.M{"_id": id, "user_id": userID}
-
-	err := r.collection.FindOneAndDelete(ctx, filter).Decode(&transaction)
-	if err == mongo.ErrNoDocuments {
-		return nil, mongo.ErrNoDocuments
+	filter := bson.M{
+		"_id":          bson.M{"$in": transactionIDs},
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
 	}
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now()}}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return &transaction, nil
+	return result.ModifiedCount, nil
 }
 
-// BulkUpdateCategory updates category for multiple transactions
-func (r *TransactionRepository) BulkUpdateCategory(userID string, transactionIDs []string, categoryID string) (int64, error) {
+// SetStatus sets the reconciliation status of multiple transactions
+func (r *TransactionRepository) SetStatus(workspaceID string, transactionIDs []string, status string) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"_id":     bson.M{"$in": transactionIDs},
-		"user_id": userID,
-		"type":    bson.M{"$ne": "transfer"}, // Don't update transfers
+		"_id":          bson.M{"$in": transactionIDs},
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
 	}
-
 	update := bson.M{
 		"$set": bson.M{
-			"category_id": categoryID,
-			"updated_at":  time.Now(),
+			"status":     status,
+			"updated_at": time.Now(),
 		},
 	}
 
@@ -341,30 +973,104 @@ func (r *TransactionRepository) BulkUpdateCategory(userID string, transactionIDs
 	return result.ModifiedCount, nil
 }
 
-// BulkDelete deletes multiple transactions
-func (r *TransactionRepository) BulkDelete(userID string, transactionIDs []string) (int64, error) {
+// GetUnreconciled retrieves the pending and cleared transactions of an account dated before the cutoff, oldest first
+func (r *TransactionRepository) GetUnreconciled(workspaceID, accountID string, cutoff time.Time) ([]models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"workspace_id":     workspaceID,
+		"deleted_at":       nil,
+		"status":           bson.M{"$ne": models.TransactionStatusReconciled},
+		"transaction_date": bson.M{"$lt": cutoff},
+		"$or": []bson.M{
+			{"account_id": accountID},
+			{"to_account_id": accountID},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "transaction_date", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err = cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+
+	if transactions == nil {
+		transactions = []models.Transaction{}
+	}
+
+	return transactions, nil
+}
+
+// GetUnclearedEffect sums the balance effect on an account of the transactions a statement ending before
+// the cutoff cannot contain: pending ones, and cleared ones dated on or after the cutoff
+func (r *TransactionRepository) GetUnclearedEffect(workspaceID, accountID string, cutoff time.Time) (float64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"_id":     bson.M{"$in": transactionIDs},
-		"user_id": userID,
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
+		"status":       bson.M{"$ne": models.TransactionStatusReconciled},
+		"$and": []bson.M{
+			{"$or": []bson.M{
+				{"account_id": accountID},
+				{"to_account_id": accountID},
+			}},
+			{"$or": []bson.M{
+				{"status": statusCondition(models.TransactionStatusPending)},
+				{"transaction_date": bson.M{"$gte": cutoff}},
+			}},
+		},
+	}
+
+	// Money comes in through income and incoming transfers and goes out through everything else
+	effect := bson.M{"$cond": bson.A{
+		bson.M{"$or": bson.A{
+			bson.M{"$eq": bson.A{"$to_account_id", accountID}},
+			bson.M{"$eq": bson.A{"$type", "income"}},
+		}},
+		"$amount",
+		bson.M{"$multiply": bson.A{"$amount", -1}},
+	}}
+
+	pipeline := []bson.M{
+		{"$match": filter},
+		{"$group": bson.M{"_id": nil, "total": bson.M{"$sum": effect}}},
 	}
 
-	result, err := r.collection.DeleteMany(ctx, filter)
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return 0, err
 	}
+	defer cursor.Close(ctx)
+
+	var total float64
+	if cursor.Next(ctx) {
+		var result struct {
+			Total float64 `bson:"total"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return 0, err
+		}
+		total = result.Total
+	}
 
-	return result.DeletedCount, nil
+	return total, cursor.Err()
 }
 
 // GetRecentTransactions retrieves the most recent transactions
-func (r *TransactionRepository) GetRecentTransactions(userID string, limit int) ([]models.Transaction, error) {
+func (r *TransactionRepository) GetRecentTransactions(workspaceID string, limit int) ([]models.Transaction, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"workspace_id": workspaceID, "deleted_at": nil}
 
 	opts := options.Find()
 	opts.SetSort(bson.D{{Key: "transaction_date", Value: -1}, {Key: "created_at", Value: -1}})
@@ -389,12 +1095,12 @@ func (r *TransactionRepository) GetRecentTransactions(userID string, limit int)
 }
 
 // GetSummary retrieves transaction summary statistics
-func (r *TransactionRepository) GetSummary(userID string, filters models.TransactionFilterQuery) (*models.TransactionSummary, error) {
+func (r *TransactionRepository) GetSummary(workspaceID string, filters models.TransactionFilterQuery) (*models.TransactionSummary, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Build filter (reuse logic from GetAll)
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"workspace_id": workspaceID, "deleted_at": nil}
 
 	// Add date filters if provided
 	if filters.StartDate != "" {
@@ -423,10 +1129,10 @@ func (r *TransactionRepository) GetSummary(userID string, filters models.Transac
 		if len(parts) == 2 {
 			year, _ := strconv.Atoi(parts[0])
 			month, _ := strconv.Atoi(parts[1])
-			
+
 			startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 			endOfMonth := startOfMonth.AddDate(0, 1, 0)
-			
+
 			filter["transaction_date"] = bson.M{
 				"$gte": startOfMonth,
 				"$lt":  endOfMonth,
@@ -434,12 +1140,22 @@ func (r *TransactionRepository) GetSummary(userID string, filters models.Transac
 		}
 	}
 
+	// Refunds are counted at the date of the expense they return, as reports do
+	if dateFilter, ok := filter["transaction_date"]; ok {
+		delete(filter, "transaction_date")
+		filter["$or"] = []bson.M{
+			{"refund_of_id": nil, "transaction_date": dateFilter},
+			{"refund_of_id": bson.M{"$ne": nil}, "refund_of_date": dateFilter},
+		}
+	}
+
 	// Aggregation pipeline
+	// Refunds and reimbursements are grouped on their own so they reduce spending instead of adding to income
 	pipeline := []bson.M{
 		{"$match": filter},
 		{
 			"$group": bson.M{
-				"_id":   "$type",
+				"_id":   bson.M{"$cond": bson.A{bson.M{"$ifNull": bson.A{"$refund_of_id", false}}, "refund", "$type"}},
 				"count": bson.M{"$sum": 1},
 				"total": bson.M{"$sum": "$amount"},
 			},
@@ -474,9 +1190,11 @@ func (r *TransactionRepository) GetSummary(userID string, filters models.Transac
 
 		switch result.ID {
 		case "income":
-			summary.TotalIncome = result.Total
+			summary.TotalIncome += result.Total
 		case "expense":
-			summary.TotalExpense = result.Total
+			summary.TotalExpense += result.Total
+		case "refund":
+			summary.TotalExpense -= result.Total
 		}
 	}
 
@@ -486,12 +1204,13 @@ func (r *TransactionRepository) GetSummary(userID string, filters models.Transac
 }
 
 // GetByAccountID retrieves all transactions for a specific account
-func (r *TransactionRepository) GetByAccountID(userID, accountID string) ([]models.Transaction, error) {
+func (r *TransactionRepository) GetByAccountID(workspaceID, accountID string) ([]models.Transaction, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id": userID,
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
 		"$or": []bson.M{
 			{"account_id": accountID},
 			{"to_account_id": accountID},
@@ -516,13 +1235,22 @@ func (r *TransactionRepository) GetByAccountID(userID, accountID string) ([]mode
 	return transactions, nil
 }
 
+// CountByWorkspace counts all transactions of a workspace, including those in the trash
+func (r *TransactionRepository) CountByWorkspace(workspaceID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return r.collection.CountDocuments(ctx, bson.M{"workspace_id": workspaceID})
+}
+
 // CountByAccountID counts transactions for a specific account
-func (r *TransactionRepository) CountByAccountID(userID, accountID string) (int64, error) {
+func (r *TransactionRepository) CountByAccountID(workspaceID, accountID string) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id": userID,
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
 		"$or": []bson.M{
 			{"account_id": accountID},
 			{"to_account_id": accountID},
@@ -533,13 +1261,14 @@ func (r *TransactionRepository) CountByAccountID(userID, accountID string) (int6
 }
 
 // GetTotalsByAccountID calculates income and expense totals for an account
-func (r *TransactionRepository) GetTotalsByAccountID(userID, accountID string) (income, expense float64, err error) {
+func (r *TransactionRepository) GetTotalsByAccountID(workspaceID, accountID string) (income, expense float64, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Income: transactions where this is the destination account
 	incomeFilter := bson.M{
-		"user_id": userID,
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
 		"$or": []bson.M{
 			{"account_id": accountID, "type": "income"},
 			{"to_account_id": accountID, "type": "transfer"},
@@ -568,9 +1297,10 @@ func (r *TransactionRepository) GetTotalsByAccountID(userID, accountID string) (
 
 	// Expense: transactions where this is the source account
 	expenseFilter := bson.M{
-		"user_id":    userID,
-		"account_id": accountID,
-		"type":       bson.M{"$in": []string{"expense", "transfer"}},
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
+		"account_id":   accountID,
+		"type":         bson.M{"$in": []string{"expense", "transfer"}},
 	}
 
 	expensePipeline := []bson.M{
@@ -597,13 +1327,14 @@ func (r *TransactionRepository) GetTotalsByAccountID(userID, accountID string) (
 }
 
 // CountByCategoryID counts the number of transactions using a category
-func (r *TransactionRepository) CountByCategoryID(categoryID, userID string) (int, error) {
+// Transactions in the trash are counted too, as they still point at the category when restored.
+func (r *TransactionRepository) CountByCategoryID(categoryID, workspaceID string) (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id":     userID,
-		"category_id": categoryID,
+		"workspace_id": workspaceID,
+		"category_id":  categoryID,
 	}
 
 	count, err := r.collection.CountDocuments(ctx, filter)
@@ -614,16 +1345,93 @@ func (r *TransactionRepository) CountByCategoryID(categoryID, userID string) (in
 	return int(count), nil
 }
 
+// GetRepeatedPatterns groups income and expense transactions since a date by account, category, type and merchant
+// Each pattern carries its most common amount; patterns seen fewer than minCount times are dropped.
+func (r *TransactionRepository) GetRepeatedPatterns(workspaceID string, since time.Time, minCount, limit int) ([]models.TransactionPattern, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"workspace_id":     workspaceID,
+			"deleted_at":       nil,
+			"refund_of_id":     nil,
+			"type":             bson.M{"$in": []string{"income", "expense"}},
+			"transaction_date": bson.M{"$gte": since},
+		}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"account_id":  "$account_id",
+				"category_id": "$category_id",
+				"type":        "$type",
+				"merchant":    "$merchant",
+				"amount":      "$amount",
+			},
+			"count":        bson.M{"$sum": 1},
+			"last_used_at": bson.M{"$max": "$transaction_date"},
+		}},
+		// Sorting by count first makes $first pick the most common amount of each pattern
+		{"$sort": bson.M{"count": -1}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"account_id":  "$_id.account_id",
+				"category_id": "$_id.category_id",
+				"type":        "$_id.type",
+				"merchant":    "$_id.merchant",
+			},
+			"amount":       bson.M{"$first": "$_id.amount"},
+			"count":        bson.M{"$sum": "$count"},
+			"last_used_at": bson.M{"$max": "$last_used_at"},
+		}},
+		{"$match": bson.M{"count": bson.M{"$gte": minCount}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "last_used_at", Value: -1}}},
+		{"$limit": limit},
+		{"$project": bson.M{
+			"_id":          0,
+			"account_id":   "$_id.account_id",
+			"category_id":  "$_id.category_id",
+			"type":         "$_id.type",
+			"merchant":     "$_id.merchant",
+			"amount":       1,
+			"count":        1,
+			"last_used_at": 1,
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var patterns []models.TransactionPattern
+	if err := cursor.All(ctx, &patterns); err != nil {
+		return nil, err
+	}
+
+	if patterns == nil {
+		patterns = []models.TransactionPattern{}
+	}
+
+	return patterns, nil
+}
+
 // GetByDateRange retrieves transactions within a date range
-func (r *TransactionRepository) GetByDateRange(userID string, startDate, endDate time.Time) ([]models.Transaction, error) {
+// Refunds are placed at the date of their original expense so reports net them against it
+func (r *TransactionRepository) GetByDateRange(workspaceID string, startDate, endDate time.Time) ([]models.Transaction, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	dateRange := bson.M{
+		"$gte": startDate,
+		"$lte": endDate,
+	}
 	filter := bson.M{
-		"user_id": userID,
-		"transaction_date": bson.M{
-			"$gte": startDate,
-			"$lte": endDate,
+		"workspace_id": workspaceID,
+		"deleted_at":   nil,
+		"$or": []bson.M{
+			{"refund_of_date": nil, "transaction_date": dateRange},
+			{"refund_of_date": dateRange},
 		},
 	}
 
@@ -648,3 +1456,296 @@ func (r *TransactionRepository) GetByDateRange(userID string, startDate, endDate
 	return transactions, nil
 }
 
+// transactionSortField returns the field and direction transactions are primarily sorted by
+func transactionSortField(filters models.TransactionFilterQuery) (string, int) {
+	sortField := "transaction_date"
+	if filters.SortBy == "amount" {
+		sortField = "amount"
+	}
+
+	sortOrder := -1 // Default descending
+	if filters.SortOrder == "asc" {
+		sortOrder = 1
+	}
+
+	return sortField, sortOrder
+}
+
+// transactionSort returns the full sort order of transaction lists
+// created_at and _id break ties so every transaction has a unique position, which cursor pages depend on
+func transactionSort(filters models.TransactionFilterQuery) bson.D {
+	field, order := transactionSortField(filters)
+	return bson.D{{Key: field, Value: order}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+}
+
+// buildFilter builds the transaction query for a workspace from list filters and parsed search terms
+// Conditions that need their own $or are combined under $and so they cannot overwrite each other
+func buildFilter(workspaceID string, filters models.TransactionFilterQuery) (bson.M, error) {
+	filter := bson.M{"workspace_id": workspaceID, "deleted_at": nil}
+	var conditions []bson.M
+
+	// Account filter
+	if filters.AccountID != "" {
+		// Include transactions where AccountID or ToAccountID matches (for transfers)
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"account_id": filters.AccountID},
+			{"to_account_id": filters.AccountID},
+		}})
+	}
+
+	// Category filter
+	if filters.CategoryID != "" {
+		filter["category_id"] = filters.CategoryID
+	}
+
+	// Payee filter
+	if filters.PayeeID != "" {
+		filter["payee_id"] = filters.PayeeID
+	}
+
+	// Type filter
+	if filters.Type != "" {
+		filter["type"] = filters.Type
+	}
+
+	// Status filter
+	if filters.Status != "" {
+		filter["status"] = statusCondition(filters.Status)
+	}
+
+	// Date range filters
+	if filters.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", filters.StartDate)
+		if err == nil {
+			conditions = append(conditions, bson.M{"transaction_date": bson.M{"$gte": startDate}})
+		}
+	}
+
+	if filters.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", filters.EndDate)
+		if err == nil {
+			// Add one day and use $lt to include the entire end date
+			endDate = endDate.AddDate(0, 0, 1)
+			conditions = append(conditions, bson.M{"transaction_date": bson.M{"$lt": endDate}})
+		}
+	}
+
+	// Month filter (YYYY-MM format)
+	if filters.Month != "" {
+		// Parse month string
+		parts := strings.Split(filters.Month, "-")
+		if len(parts) == 2 {
+			year, _ := strconv.Atoi(parts[0])
+			month, _ := strconv.Atoi(parts[1])
+
+			startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+			endOfMonth := startOfMonth.AddDate(0, 1, 0)
+
+			conditions = append(conditions, bson.M{"transaction_date": bson.M{
+				"$gte": startOfMonth,
+				"$lt":  endOfMonth,
+			}})
+		}
+	}
+
+	// Amount range filters
+	if filters.MinAmount != "" {
+		minAmount, err := strconv.ParseFloat(filters.MinAmount, 64)
+		if err == nil {
+			conditions = append(conditions, bson.M{"amount": bson.M{"$gte": minAmount}})
+		}
+	}
+
+	if filters.MaxAmount != "" {
+		maxAmount, err := strconv.ParseFloat(filters.MaxAmount, 64)
+		if err == nil {
+			conditions = append(conditions, bson.M{"amount": bson.M{"$lte": maxAmount}})
+		}
+	}
+
+	// Tags filter: tag_mode picks whether transactions need any (default), all or none of the tags
+	if filters.Tags != "" {
+		tags := strings.Split(filters.Tags, ",")
+		switch filters.TagMode {
+		case "all":
+			filter["tags"] = bson.M{"$all": tags}
+		case "none":
+			filter["tags"] = bson.M{"$nin": tags}
+		default:
+			filter["tags"] = bson.M{"$in": tags}
+		}
+	}
+
+	// Search query
+	textSearch, searchConditions, err := buildSearchConditions(filters.SearchTerms)
+	if err != nil {
+		return nil, err
+	}
+	if textSearch != "" {
+		filter["$text"] = bson.M{"$search": textSearch}
+	}
+	conditions = append(conditions, searchConditions...)
+
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+
+	return filter, nil
+}
+
+// statusCondition matches a transaction status; transactions created before statuses existed count as pending
+func statusCondition(status string) interface{} {
+	if status == models.TransactionStatusPending {
+		return bson.M{"$in": bson.A{models.TransactionStatusPending, nil}}
+	}
+	return status
+}
+
+// buildSearchConditions turns parsed search terms into a $text search string and field conditions
+// User input only ever reaches regular expressions through regexp.QuoteMeta, so it is matched literally
+func buildSearchConditions(terms []models.SearchTerm) (string, []bson.M, error) {
+	var textTerms, excludedText []string
+	var conditions []bson.M
+
+	for _, term := range terms {
+		var condition bson.M
+
+		switch term.Field {
+		case "":
+			// Quoting every word makes the text search require all of them instead of any
+			phrase := "\"" + strings.ReplaceAll(term.Value, "\"", "") + "\""
+			if term.Negated {
+				excludedText = append(excludedText, term.Value)
+			} else {
+				textTerms = append(textTerms, phrase)
+			}
+			continue
+
+		case models.SearchFieldMerchant, models.SearchFieldDescription, models.SearchFieldNotes:
+			condition = bson.M{"search." + term.Field: containsPattern(term.Value)}
+
+		case models.SearchFieldTag:
+			condition = bson.M{"search.tags": term.Value}
+
+		case models.SearchFieldCategory:
+			condition = bson.M{"category_id": bson.M{"$in": nonNilStrings(term.Values)}}
+
+		case models.SearchFieldAccount:
+			ids := nonNilStrings(term.Values)
+			condition = bson.M{"$or": []bson.M{
+				{"account_id": bson.M{"$in": ids}},
+				{"to_account_id": bson.M{"$in": ids}},
+			}}
+
+		case models.SearchFieldType:
+			condition = bson.M{"type": term.Value}
+
+		case models.SearchFieldAmount:
+			amount, err := strconv.ParseFloat(term.Value, 64)
+			if err != nil {
+				return "", nil, err
+			}
+			condition = bson.M{"amount": bson.M{comparisonOperator(term.Operator): amount}}
+
+		case models.SearchFieldDate:
+			start, end, err := utils.SearchDateRange(term.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			switch term.Operator {
+			case ">":
+				condition = bson.M{"transaction_date": bson.M{"$gte": end}}
+			case ">=":
+				condition = bson.M{"transaction_date": bson.M{"$gte": start}}
+			case "<":
+				condition = bson.M{"transaction_date": bson.M{"$lt": start}}
+			case "<=":
+				condition = bson.M{"transaction_date": bson.M{"$lt": end}}
+			default:
+				condition = bson.M{"transaction_date": bson.M{"$gte": start, "$lt": end}}
+			}
+
+		default:
+			continue
+		}
+
+		if term.Negated {
+			condition = bson.M{"$nor": []bson.M{condition}}
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if len(textTerms) == 0 {
+		// A text search needs at least one positive term, so exclusions on their own are matched directly
+		for _, value := range excludedText {
+			conditions = append(conditions, bson.M{"search.text": bson.M{"$not": containsPattern(value)}})
+		}
+		return "", conditions, nil
+	}
+
+	textSearch := strings.Join(textTerms, " ")
+	for _, value := range excludedText {
+		textSearch += " -\"" + strings.ReplaceAll(value, "\"", "") + "\""
+	}
+
+	return textSearch, conditions, nil
+}
+
+// buildSearchIndex builds the normalized search fields of a transaction
+func buildSearchIndex(transaction *models.Transaction) *models.TransactionSearchIndex {
+	index := &models.TransactionSearchIndex{}
+	var parts []string
+
+	if transaction.Merchant != nil {
+		index.Merchant = utils.NormalizeSearchText(*transaction.Merchant)
+		parts = append(parts, index.Merchant)
+	}
+	if transaction.Description != nil {
+		index.Description = utils.NormalizeSearchText(*transaction.Description)
+		parts = append(parts, index.Description)
+	}
+	if transaction.Notes != nil {
+		index.Notes = utils.NormalizeSearchText(*transaction.Notes)
+		parts = append(parts, index.Notes)
+	}
+	for _, tag := range transaction.Tags {
+		normalized := utils.NormalizeSearchText(tag)
+		if normalized == "" {
+			continue
+		}
+		index.Tags = append(index.Tags, normalized)
+		parts = append(parts, normalized)
+	}
+
+	index.Text = strings.Join(parts, " ")
+	return index
+}
+
+// containsPattern matches values containing the given text literally
+func containsPattern(value string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(value)}
+}
+
+// comparisonOperator maps a search query operator to its MongoDB operator
+func comparisonOperator(operator string) string {
+	switch operator {
+	case ">":
+		return "$gt"
+	case ">=":
+		return "$gte"
+	case "<":
+		return "$lt"
+	case "<=":
+		return "$lte"
+	default:
+		return "$eq"
+	}
+}
+
+// nonNilStrings returns an empty slice for nil so that $in matches nothing instead of failing
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
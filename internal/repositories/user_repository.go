@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/utils"
 	"time"
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
@@ -132,6 +134,29 @@ func (r *UserRepository) Update(ctx context.Context, id string, updates bson.M)
 	return nil
 }
 
+// SetAvatar replaces a user's avatar URL, or clears it when avatarURL is nil, and returns the previous one
+func (r *UserRepository) SetAvatar(ctx context.Context, id string, avatarURL *string) (*string, error) {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{
+		"avatar_url": avatarURL,
+		"updated_at": time.Now(),
+	}}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.Before).
+		SetProjection(bson.M{"avatar_url": 1})
+
+	var previous models.User
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return previous.AvatarURL, nil
+}
+
 // UpdatePassword updates user password
 func (r *UserRepository) UpdatePassword(ctx context.Context, id string, hashedPassword string) error {
 	updates := bson.M{
@@ -178,6 +203,7 @@ func (r *UserRepository) UpdateLastLogin(ctx context.Context, id string) error {
 }
 
 // LinkGoogleAccount links a Google account to existing user
+// The Google picture becomes the avatar only when the user has none, so an uploaded avatar is kept.
 func (r *UserRepository) LinkGoogleAccount(ctx context.Context, userID string, googleID string, avatarURL *string) error {
 	updates := bson.M{
 		"google_id":     googleID,
@@ -186,11 +212,12 @@ func (r *UserRepository) LinkGoogleAccount(ctx context.Context, userID string, g
 	}
 
 	if avatarURL != nil && *avatarURL != "" {
-		updates["avatar_url"] = *avatarURL
+		updates["google_picture_url"] = *avatarURL
+		updates["avatar_url"] = bson.M{"$ifNull": bson.A{"$avatar_url", *avatarURL}}
 	}
 
 	filter := bson.M{"_id": userID}
-	update := bson.M{"$set": updates}
+	update := mongo.Pipeline{{{Key: "$set", Value: updates}}}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
@@ -243,15 +270,31 @@ func (r *UserRepository) HardDelete(ctx context.Context, id string) error {
 }
 
 // ToUserProfile converts User to UserProfile (safe for client)
+// Unset locale and timezone are filled with the defaults.
 func ToUserProfile(user *models.User) models.UserProfile {
-	return models.UserProfile{
+	profile := models.UserProfile{
 		ID:            user.ID,
 		Email:         user.Email,
 		FullName:      user.FullName,
 		AvatarURL:     user.AvatarURL,
+		Initials:      utils.Initials(user.FullName),
+		Locale:        user.Locale,
+		Timezone:      user.Timezone,
+		Preferences:   user.Preferences,
 		AuthProvider:  user.AuthProvider,
 		EmailVerified: user.EmailVerified,
 		LastLoginAt:   user.LastLoginAt,
 		CreatedAt:     user.CreatedAt,
 	}
+	if profile.AvatarURL != nil && *profile.AvatarURL == "" {
+		profile.AvatarURL = nil
+	}
+	if profile.Locale == "" {
+		profile.Locale = models.DefaultLocale
+	}
+	if profile.Timezone == "" {
+		profile.Timezone = models.DefaultTimezone
+	}
+
+	return profile
 }
@@ -0,0 +1,436 @@
+package repositories
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WorkspaceRepository handles workspace and membership data operations
+type WorkspaceRepository struct {
+	collection       *mongo.Collection
+	memberCollection *mongo.Collection
+}
+
+// NewWorkspaceRepository creates a new workspace repository
+func NewWorkspaceRepository(db *mongo.Database) *WorkspaceRepository {
+	return &WorkspaceRepository{
+		collection:       db.Collection("workspaces"),
+		memberCollection: db.Collection("workspace_members"),
+	}
+}
+
+// BackfillWorkspaceIDs assigns documents created before workspaces existed to their owner's personal workspace
+func BackfillWorkspaceIDs(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": bson.M{"$exists": false}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{"workspace_id": "$user_id"}}}}
+
+	for _, name := range []string{"accounts", "categories", "budgets", "transactions", "loan_payments", "goals", "settlements", "contacts", "contact_groups", "shared_expenses"} {
+		if _, err := db.Collection(name).UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Create creates a new workspace
+func (r *WorkspaceRepository) Create(ownerID string, req models.CreateWorkspaceRequest) (*models.Workspace, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	workspace := &models.Workspace{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		OwnerID:   ownerID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	_, err := r.collection.InsertOne(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// EnsurePersonal returns the user's personal workspace, creating it on first use
+func (r *WorkspaceRepository) EnsurePersonal(userID string) (*models.Workspace, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{"_id": userID}
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"name":        models.PersonalWorkspaceName,
+			"owner_id":    userID,
+			"is_personal": true,
+			"created_at":  now,
+			"updated_at":  now,
+		},
+	}
+
+	var workspace models.Workspace
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+// GetByID retrieves a workspace by ID
+func (r *WorkspaceRepository) GetByID(id string) (*models.Workspace, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var workspace models.Workspace
+	filter := bson.M{"_id": id}
+
+	err := r.collection.FindOne(ctx, filter).Decode(&workspace)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+// GetAccessible retrieves the workspaces a user owns or has been given by ID, personal workspace first
+func (r *WorkspaceRepository) GetAccessible(userID string, ids []string) ([]models.Workspace, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"$or": []bson.M{
+			{"owner_id": userID},
+			{"_id": bson.M{"$in": ids}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "is_personal", Value: -1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var workspaces []models.Workspace
+	if err = cursor.All(ctx, &workspaces); err != nil {
+		return nil, err
+	}
+
+	if workspaces == nil {
+		workspaces = []models.Workspace{}
+	}
+
+	return workspaces, nil
+}
+
+// Update updates a workspace
+func (r *WorkspaceRepository) Update(id string, req models.UpdateWorkspaceRequest) (*models.Workspace, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	setFields := update["$set"].(bson.M)
+
+	if req.Name != nil {
+		setFields["name"] = *req.Name
+	}
+
+	filter := bson.M{"_id": id}
+
+	var workspace models.Workspace
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&workspace)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+// Delete deletes a workspace and all of its memberships
+func (r *WorkspaceRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_, err = r.memberCollection.DeleteMany(ctx, bson.M{"workspace_id": id})
+	return err
+}
+
+// CreateMember records an invitation to a workspace
+func (r *WorkspaceRepository) CreateMember(member *models.WorkspaceMember) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	member.ID = uuid.New().String()
+	member.InvitedAt = time.Now()
+
+	_, err := r.memberCollection.InsertOne(ctx, member)
+	return err
+}
+
+// GetMemberByID retrieves a membership by ID within a workspace
+func (r *WorkspaceRepository) GetMemberByID(id, workspaceID string) (*models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var member models.WorkspaceMember
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+
+	err := r.memberCollection.FindOne(ctx, filter).Decode(&member)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// GetActiveMember retrieves a user's accepted membership in a workspace
+func (r *WorkspaceRepository) GetActiveMember(workspaceID, userID string) (*models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var member models.WorkspaceMember
+	filter := bson.M{"workspace_id": workspaceID, "user_id": userID, "status": "active"}
+
+	err := r.memberCollection.FindOne(ctx, filter).Decode(&member)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// GetMemberByEmail retrieves a membership or pending invitation by email within a workspace
+func (r *WorkspaceRepository) GetMemberByEmail(workspaceID, email string) (*models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var member models.WorkspaceMember
+	filter := bson.M{"workspace_id": workspaceID, "email": email}
+
+	err := r.memberCollection.FindOne(ctx, filter).Decode(&member)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// GetMembers retrieves all members and pending invitations of a workspace
+func (r *WorkspaceRepository) GetMembers(workspaceID string) ([]models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"workspace_id": workspaceID}
+	opts := options.Find().SetSort(bson.D{{Key: "invited_at", Value: 1}})
+
+	cursor, err := r.memberCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var members []models.WorkspaceMember
+	if err = cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+
+	if members == nil {
+		members = []models.WorkspaceMember{}
+	}
+
+	return members, nil
+}
+
+// GetActiveMemberships retrieves all accepted memberships of a user
+func (r *WorkspaceRepository) GetActiveMemberships(userID string) ([]models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID, "status": "active"}
+
+	cursor, err := r.memberCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var members []models.WorkspaceMember
+	if err = cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+
+	if members == nil {
+		members = []models.WorkspaceMember{}
+	}
+
+	return members, nil
+}
+
+// GetPendingInvitations retrieves the invitations sent to an email that have not been accepted yet
+func (r *WorkspaceRepository) GetPendingInvitations(email string) ([]models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"email": email, "status": "pending"}
+	opts := options.Find().SetSort(bson.D{{Key: "invited_at", Value: -1}})
+
+	cursor, err := r.memberCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var members []models.WorkspaceMember
+	if err = cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+
+	if members == nil {
+		members = []models.WorkspaceMember{}
+	}
+
+	return members, nil
+}
+
+// GetPendingInvitation retrieves a pending invitation by ID
+func (r *WorkspaceRepository) GetPendingInvitation(id string) (*models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var member models.WorkspaceMember
+	filter := bson.M{"_id": id, "status": "pending"}
+
+	err := r.memberCollection.FindOne(ctx, filter).Decode(&member)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// UpdateMemberRole changes the role of a member
+func (r *WorkspaceRepository) UpdateMemberRole(id, workspaceID, role string) (*models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	update := bson.M{"$set": bson.M{"role": role}}
+
+	var member models.WorkspaceMember
+	err := r.memberCollection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&member)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// ActivateMember marks an invitation as accepted by a user
+func (r *WorkspaceRepository) ActivateMember(id, userID string) (*models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "status": "pending"}
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":   userID,
+			"status":    "active",
+			"joined_at": time.Now(),
+		},
+	}
+
+	var member models.WorkspaceMember
+	err := r.memberCollection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&member)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// DeleteMember removes a member or pending invitation
+func (r *WorkspaceRepository) DeleteMember(id, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "workspace_id": workspaceID}
+	result, err := r.memberCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
@@ -5,32 +5,49 @@ import (
 	"finance-hub-api/internal/repositories"
 	"finance-hub-api/internal/utils"
 	"fmt"
+	"time"
 )
 
 // AccountService handles business logic for accounts
 type AccountService struct {
-	repo         *repositories.AccountRepository
-	vietQRService *utils.VietQRService
+	repo            *repositories.AccountRepository
+	transactionRepo *repositories.TransactionRepository
+	investmentRepo  *repositories.InvestmentRepository
+	securityRepo    *repositories.SecurityRepository
+	auditService    *AuditService
+	vietQRService   *utils.VietQRService
 }
 
 // NewAccountService creates a new account service
-func NewAccountService(repo *repositories.AccountRepository) *AccountService {
+func NewAccountService(
+	repo *repositories.AccountRepository,
+	transactionRepo *repositories.TransactionRepository,
+	investmentRepo *repositories.InvestmentRepository,
+	securityRepo *repositories.SecurityRepository,
+	auditService *AuditService,
+) *AccountService {
 	return &AccountService{
-		repo:         repo,
-		vietQRService: utils.NewVietQRService(),
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		investmentRepo:  investmentRepo,
+		securityRepo:    securityRepo,
+		auditService:    auditService,
+		vietQRService:   utils.NewVietQRService(),
 	}
 }
 
 // CreateAccount creates a new account
-func (s *AccountService) CreateAccount(userID string, req models.CreateAccountRequest) (*models.Account, error) {
+func (s *AccountService) CreateAccount(workspaceID, userID string, req models.CreateAccountRequest) (*models.Account, error) {
 	// Validate account type
 	validTypes := map[string]bool{
-		"cash":   true,
-		"bank":   true,
-		"credit": true,
+		"cash":       true,
+		"bank":       true,
+		"credit":     true,
+		"loan":       true,
+		"investment": true,
 	}
 	if !validTypes[req.Type] {
-		return nil, fmt.Errorf("invalid account type: must be cash, bank, or credit")
+		return nil, fmt.Errorf("invalid account type: must be cash, bank, credit, loan, or investment")
 	}
 
 	// Validate bank-specific fields
@@ -53,6 +70,13 @@ func (s *AccountService) CreateAccount(userID string, req models.CreateAccountRe
 		}
 	}
 
+	// Validate loan fields
+	if req.Type == "loan" {
+		if err := prepareLoanAccount(&req); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set default icon and color if not provided
 	if req.Icon == nil {
 		icon := getDefaultIcon(req.Type)
@@ -65,12 +89,25 @@ func (s *AccountService) CreateAccount(userID string, req models.CreateAccountRe
 	}
 
 	// Create account
-	return s.repo.Create(userID, req)
+	account, err := s.repo.Create(workspaceID, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityAccount,
+		EntityID:    account.ID,
+		Action:      models.AuditActionCreate,
+		ActorID:     userID,
+	}, nil, account)
+
+	return account, nil
 }
 
 // GetAccount retrieves an account by ID
-func (s *AccountService) GetAccount(id, userID string) (*models.Account, error) {
-	account, err := s.repo.GetByID(id, userID)
+func (s *AccountService) GetAccount(id, workspaceID string) (*models.Account, error) {
+	account, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -80,11 +117,11 @@ func (s *AccountService) GetAccount(id, userID string) (*models.Account, error)
 	return account, nil
 }
 
-// GetAllAccounts retrieves all accounts for a user
-func (s *AccountService) GetAllAccounts(userID string, pagination models.PaginationQuery) (*models.PaginatedResponse, error) {
+// GetAllAccounts retrieves all accounts in a workspace
+func (s *AccountService) GetAllAccounts(workspaceID string, pagination models.PaginationQuery) (*models.PaginatedResponse, error) {
 	pagination.SetDefaults()
 	
-	accounts, totalCount, err := s.repo.GetAll(userID, pagination)
+	accounts, totalCount, err := s.repo.GetAll(workspaceID, pagination)
 	if err != nil {
 		return nil, err
 	}
@@ -101,9 +138,9 @@ func (s *AccountService) GetAllAccounts(userID string, pagination models.Paginat
 }
 
 // UpdateAccount updates an account
-func (s *AccountService) UpdateAccount(id, userID string, req models.UpdateAccountRequest) (*models.Account, error) {
+func (s *AccountService) UpdateAccount(id, workspaceID, actorID string, req models.UpdateAccountRequest) (*models.Account, error) {
 	// Check if account exists
-	existing, err := s.repo.GetByID(id, userID)
+	existing, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -121,14 +158,38 @@ func (s *AccountService) UpdateAccount(id, userID string, req models.UpdateAccou
 		}
 	}
 
+	// Validate loan fields
+	if existing.Type == "loan" {
+		if req.Balance != nil && existing.LoanDirection != nil {
+			if err := checkLoanBalance(*existing.LoanDirection, *req.Balance); err != nil {
+				return nil, err
+			}
+		}
+	} else if req.InterestRate != nil || req.TermPeriods != nil || req.PaymentFrequency != nil {
+		return nil, fmt.Errorf("interest_rate, term_periods and payment_frequency only apply to loan accounts")
+	}
+
 	// Update account
-	return s.repo.Update(id, userID, req)
+	updated, err := s.repo.Update(id, workspaceID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityAccount,
+		EntityID:    id,
+		Action:      models.AuditActionUpdate,
+		ActorID:     actorID,
+	}, existing, updated)
+
+	return updated, nil
 }
 
 // DeleteAccount deletes an account
-func (s *AccountService) DeleteAccount(id, userID string) error {
+func (s *AccountService) DeleteAccount(id, workspaceID, actorID string) error {
 	// Check if account exists
-	existing, err := s.repo.GetByID(id, userID)
+	existing, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return err
 	}
@@ -136,20 +197,67 @@ func (s *AccountService) DeleteAccount(id, userID string) error {
 		return fmt.Errorf("account not found")
 	}
 
-	// TODO: Check if account has transactions before deleting
-	// For now, just delete
-	return s.repo.Delete(id, userID)
+	// Accounts still referenced by transactions or investment trades cannot be deleted
+	count, err := s.transactionRepo.CountByAccountID(workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("account has %d transactions; delete them first", count)
+	}
+
+	if existing.Type == "investment" {
+		count, err := s.investmentRepo.CountByAccountID(id, workspaceID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("account has %d investment transactions; delete them first", count)
+		}
+	}
+
+	if err := s.repo.Delete(id, workspaceID); err != nil {
+		return err
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityAccount,
+		EntityID:    id,
+		Action:      models.AuditActionDelete,
+		ActorID:     actorID,
+	}, existing, nil)
+
+	return nil
 }
 
-// GetAccountSummary retrieves account summary statistics
-func (s *AccountService) GetAccountSummary(userID string) (*models.AccountSummary, error) {
-	return s.repo.GetSummary(userID)
+// GetAccountSummary retrieves account summary statistics, including the market value of investment holdings
+func (s *AccountService) GetAccountSummary(workspaceID string) (*models.AccountSummary, error) {
+	investmentAccounts, err := s.repo.GetByType(workspaceID, "investment")
+	if err != nil {
+		return nil, err
+	}
+
+	var marketValues map[string]float64
+	if len(investmentAccounts) > 0 {
+		transactions, err := s.investmentRepo.GetByWorkspace(workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		securities, err := s.securityRepo.GetAll(workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		marketValues = portfolioMarketValues(investmentAccounts, transactions, securities)
+	}
+
+	return s.repo.GetSummary(workspaceID, marketValues)
 }
 
 // UpdateBalance updates account balance (used by transactions)
-func (s *AccountService) UpdateBalance(accountID, userID string, amount float64) error {
-	// Verify account exists and belongs to user
-	account, err := s.repo.GetByID(accountID, userID)
+func (s *AccountService) UpdateBalance(accountID, workspaceID string, amount float64) error {
+	// Verify account exists and belongs to the workspace
+	account, err := s.repo.GetByID(accountID, workspaceID)
 	if err != nil {
 		return err
 	}
@@ -157,7 +265,7 @@ func (s *AccountService) UpdateBalance(accountID, userID string, amount float64)
 		return fmt.Errorf("account not found")
 	}
 
-	return s.repo.UpdateBalance(accountID, userID, amount)
+	return s.repo.UpdateBalance(accountID, workspaceID, amount)
 }
 
 // GetBanks retrieves list of all banks from VietQR
@@ -173,9 +281,11 @@ func (s *AccountService) SearchBanks(query string) ([]utils.VietQRBank, error) {
 // Helper functions
 func getDefaultIcon(accountType string) string {
 	icons := map[string]string{
-		"cash":   "💵",
-		"bank":   "🏦",
-		"credit": "💳",
+		"cash":       "💵",
+		"bank":       "🏦",
+		"credit":     "💳",
+		"loan":       "🤝",
+		"investment": "📈",
 	}
 	if icon, ok := icons[accountType]; ok {
 		return icon
@@ -185,12 +295,60 @@ func getDefaultIcon(accountType string) string {
 
 func getDefaultColor(accountType string) string {
 	colors := map[string]string{
-		"cash":   "#10B981", // Green
-		"bank":   "#3B82F6", // Blue
-		"credit": "#F59E0B", // Orange
+		"cash":       "#10B981", // Green
+		"bank":       "#3B82F6", // Blue
+		"credit":     "#F59E0B", // Orange
+		"loan":       "#8B5CF6", // Purple
+		"investment": "#14B8A6", // Teal
 	}
 	if color, ok := colors[accountType]; ok {
 		return color
 	}
 	return "#6B7280" // Gray
 }
+
+// prepareLoanAccount validates loan terms and sets defaults for a new loan account
+func prepareLoanAccount(req *models.CreateAccountRequest) error {
+	if req.LoanDirection == nil || (*req.LoanDirection != "borrowed" && *req.LoanDirection != "lent") {
+		return fmt.Errorf("loan_direction must be borrowed or lent for loan accounts")
+	}
+	if req.Principal == nil || *req.Principal <= 0 {
+		return fmt.Errorf("principal is required for loan accounts")
+	}
+	if req.TermPeriods == nil || *req.TermPeriods <= 0 {
+		return fmt.Errorf("term_periods is required for loan accounts")
+	}
+	if req.InterestRate == nil {
+		rate := 0.0
+		req.InterestRate = &rate
+	}
+	if req.PaymentFrequency == nil {
+		frequency := "monthly"
+		req.PaymentFrequency = &frequency
+	}
+	if req.LoanStartDate == nil {
+		now := time.Now()
+		req.LoanStartDate = &now
+	}
+
+	// Opening balance is the outstanding principal: a liability when borrowed, a receivable when lent
+	if req.Balance == 0 {
+		req.Balance = *req.Principal
+		if *req.LoanDirection == "borrowed" {
+			req.Balance = -*req.Principal
+		}
+	}
+
+	return checkLoanBalance(*req.LoanDirection, req.Balance)
+}
+
+// checkLoanBalance checks that a loan balance has the sign of its direction
+func checkLoanBalance(direction string, balance float64) error {
+	if direction == "borrowed" && balance > 0 {
+		return fmt.Errorf("balance of a borrowed loan must be zero or negative")
+	}
+	if direction == "lent" && balance < 0 {
+		return fmt.Errorf("balance of a lent loan must be zero or positive")
+	}
+	return nil
+}
@@ -0,0 +1,543 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"finance-hub-api/internal/config"
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// attachmentFolder is the storage prefix of transaction attachments
+	attachmentFolder = "attachments"
+	// maxAttachmentsPerTransaction caps the files stored for a single transaction
+	maxAttachmentsPerTransaction = 10
+)
+
+// AttachmentService handles business logic for transaction attachments
+type AttachmentService struct {
+	repo              *repositories.AttachmentRepository
+	transactionRepo   *repositories.TransactionRepository
+	storage           utils.Storage
+	allowedTypes      []string
+	maxImageDimension int
+	maxImagePixels    int
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(
+	repo *repositories.AttachmentRepository,
+	transactionRepo *repositories.TransactionRepository,
+	storage utils.Storage,
+	cfg *config.Config,
+) *AttachmentService {
+	return &AttachmentService{
+		repo:              repo,
+		transactionRepo:   transactionRepo,
+		storage:           storage,
+		allowedTypes:      cfg.Storage.AllowedFileTypes,
+		maxImageDimension: cfg.Storage.MaxImageDimension,
+		maxImagePixels:    cfg.Storage.MaxImagePixels,
+	}
+}
+
+// Upload stores a file and records it, attached to a transaction or, without one, as an upload to link later
+// The content type must be the one sniffed from the content. Images go through the image pipeline first.
+func (s *AttachmentService) Upload(workspaceID, userID string, transactionID *string, fileName, contentType string, content []byte) (*models.Attachment, error) {
+	if transactionID != nil {
+		if err := s.checkRoom(workspaceID, *transactionID, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	key := attachmentKey(workspaceID, contentType)
+	attachment := &models.Attachment{
+		WorkspaceID:   workspaceID,
+		UserID:        userID,
+		TransactionID: transactionID,
+		Key:           key,
+		URL:           s.storage.URLForKey(key),
+		FileName:      filepath.Base(fileName),
+		ContentType:   contentType,
+	}
+
+	if err := s.storeFile(attachment, content); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(attachment); err != nil {
+		// Without its record the files would only be found by the garbage collector
+		if deleteErr := s.deleteFiles(attachment); deleteErr != nil {
+			fmt.Printf("Warning: failed to delete unrecorded attachment %s: %v\n", key, deleteErr)
+		}
+		return nil, err
+	}
+
+	s.sign(attachment)
+	return attachment, nil
+}
+
+// CompleteUpload checks and processes a file uploaded directly through a presigned URL
+// Files whose content does not match the declared type are deleted along with their record.
+func (s *AttachmentService) CompleteUpload(id, workspaceID string) (*models.Attachment, error) {
+	attachment, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, fmt.Errorf("attachment not found")
+	}
+
+	if attachment.Checksum == "" {
+		if err := s.complete(attachment); err != nil {
+			return nil, err
+		}
+	}
+
+	s.sign(attachment)
+	return attachment, nil
+}
+
+// PresignUpload records an attachment and returns a signed URL to upload its file straight to storage
+// The file never passes through the API; an upload that never arrives is removed by the garbage collector.
+func (s *AttachmentService) PresignUpload(workspaceID, userID string, transactionID *string, req models.PresignAttachmentRequest) (*models.PresignedUpload, error) {
+	if transactionID != nil {
+		if err := s.checkRoom(workspaceID, *transactionID, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	key := attachmentKey(workspaceID, req.ContentType)
+	uploadURL, expiresAt, err := s.storage.PresignPut(key, req.ContentType, req.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		WorkspaceID:   workspaceID,
+		UserID:        userID,
+		TransactionID: transactionID,
+		Key:           key,
+		URL:           s.storage.URLForKey(key),
+		FileName:      filepath.Base(req.FileName),
+		ContentType:   req.ContentType,
+		Size:          req.Size,
+	}
+	if err := s.repo.Create(attachment); err != nil {
+		return nil, err
+	}
+
+	return &models.PresignedUpload{
+		Attachment: attachment,
+		UploadURL:  uploadURL,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// GetAttachments retrieves the attachments of a transaction with signed download URLs
+func (s *AttachmentService) GetAttachments(workspaceID, transactionID string) ([]models.Attachment, error) {
+	attachments, err := s.repo.GetByTransactionID(workspaceID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range attachments {
+		s.sign(&attachments[i])
+	}
+	return attachments, nil
+}
+
+// AttachFiles signs the attachment_url of transactions and adds the thumbnail of their first image attachment
+func (s *AttachmentService) AttachFiles(workspaceID string, transactions []models.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(transactions))
+	for i := range transactions {
+		ids[i] = transactions[i].ID
+		if transactions[i].AttachmentURL != nil {
+			signed := s.storage.SignURL(*transactions[i].AttachmentURL)
+			transactions[i].AttachmentURL = &signed
+		}
+	}
+
+	attachments, err := s.repo.GetByTransactionIDs(workspaceID, ids)
+	if err != nil {
+		return err
+	}
+
+	thumbnails := make(map[string]string)
+	for _, attachment := range attachments {
+		if attachment.ThumbnailKey == "" || thumbnails[*attachment.TransactionID] != "" {
+			continue
+		}
+		thumbnailURL, _, err := s.storage.PresignGet(attachment.ThumbnailKey)
+		if err != nil {
+			fmt.Printf("Warning: failed to sign thumbnail of attachment %s: %v\n", attachment.ID, err)
+			continue
+		}
+		thumbnails[*attachment.TransactionID] = thumbnailURL
+	}
+
+	for i := range transactions {
+		if thumbnailURL, ok := thumbnails[transactions[i].ID]; ok {
+			transactions[i].ThumbnailURL = &thumbnailURL
+		}
+	}
+
+	return nil
+}
+
+// ValidateUploads checks that uploads exist in the workspace, are not linked yet and fit on one transaction
+// Direct uploads that were not completed yet are completed here.
+func (s *AttachmentService) ValidateUploads(workspaceID string, attachmentIDs []string) error {
+	if len(attachmentIDs) > maxAttachmentsPerTransaction {
+		return fmt.Errorf("a transaction can have at most %d attachments", maxAttachmentsPerTransaction)
+	}
+
+	for _, id := range attachmentIDs {
+		attachment, err := s.repo.GetByID(id, workspaceID)
+		if err != nil {
+			return err
+		}
+		if attachment == nil {
+			return fmt.Errorf("attachment %s not found", id)
+		}
+		if attachment.TransactionID != nil {
+			return fmt.Errorf("attachment %s already belongs to a transaction", id)
+		}
+		if attachment.Checksum == "" {
+			if err := s.complete(attachment); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LinkUploads attaches validated uploads to a transaction
+func (s *AttachmentService) LinkUploads(workspaceID, transactionID string, attachmentIDs []string) error {
+	for _, id := range attachmentIDs {
+		if err := s.repo.Link(id, workspaceID, transactionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteAttachment deletes an attachment of a transaction and its stored file
+func (s *AttachmentService) DeleteAttachment(id, workspaceID, transactionID string) error {
+	attachment, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if attachment == nil || attachment.TransactionID == nil || *attachment.TransactionID != transactionID {
+		return fmt.Errorf("attachment not found")
+	}
+
+	return s.remove(attachment)
+}
+
+// DeleteUpload deletes a file by URL for a member of the workspace that owns it
+// The file must be recorded as an attachment of the workspace, or, for files uploaded before attachments
+// were recorded, be the attachment_url of one of its transactions.
+func (s *AttachmentService) DeleteUpload(workspaceID, fileURL string) error {
+	key, ok := s.storage.KeyFromURL(fileURL)
+	if !ok || !strings.HasPrefix(key, attachmentFolder+"/") {
+		return fmt.Errorf("attachment not found")
+	}
+
+	attachment, err := s.repo.GetByKey(key, workspaceID)
+	if err != nil {
+		return err
+	}
+	if attachment != nil {
+		return s.remove(attachment)
+	}
+
+	transaction, err := s.transactionRepo.GetByAttachmentURL(workspaceID, s.storage.URLForKey(key))
+	if err != nil {
+		return err
+	}
+	if transaction == nil {
+		return fmt.Errorf("attachment not found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return s.storage.DeleteObject(ctx, key)
+}
+
+// DeleteForTransaction deletes every attachment of a transaction and their stored files
+func (s *AttachmentService) DeleteForTransaction(workspaceID, transactionID string) error {
+	attachments, err := s.repo.GetByTransactionID(workspaceID, transactionID)
+	if err != nil {
+		return err
+	}
+
+	for i := range attachments {
+		if err := s.remove(&attachments[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteOrphaned deletes the attachments whose transaction no longer exists, not even in the trash
+func (s *AttachmentService) DeleteOrphaned() (int, error) {
+	attachments, err := s.repo.GetOrphaned()
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for i := range attachments {
+		if err := s.remove(&attachments[i]); err != nil {
+			fmt.Printf("Warning: failed to delete orphaned attachment %s: %v\n", attachments[i].ID, err)
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// CollectGarbage removes stored files nothing refers to any more
+// Uploads never linked to a transaction are linked when a transaction still points at them through
+// attachment_url, and deleted otherwise. Objects in the attachment folder without a record are deleted
+// unless a transaction points at them. Only files older than the grace period are touched, so uploads
+// waiting for their transaction to be saved are left alone.
+func (s *AttachmentService) CollectGarbage(gracePeriod time.Duration) (int, error) {
+	cutoff := time.Now().Add(-gracePeriod)
+
+	deleted, err := s.DeleteOrphaned()
+	if err != nil {
+		return deleted, err
+	}
+
+	unlinked, err := s.repo.GetUnlinkedBefore(cutoff)
+	if err != nil {
+		return deleted, err
+	}
+	for i := range unlinked {
+		attachment := &unlinked[i]
+
+		transaction, err := s.transactionRepo.GetByAttachmentURL(attachment.WorkspaceID, attachment.URL)
+		if err != nil {
+			return deleted, err
+		}
+		if transaction != nil {
+			if err := s.repo.Link(attachment.ID, attachment.WorkspaceID, transaction.ID); err != nil {
+				fmt.Printf("Warning: failed to link attachment %s: %v\n", attachment.ID, err)
+			}
+			continue
+		}
+
+		if err := s.remove(attachment); err != nil {
+			fmt.Printf("Warning: failed to delete unlinked attachment %s: %v\n", attachment.ID, err)
+			continue
+		}
+		deleted++
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	objects, err := s.storage.ListObjects(ctx, attachmentFolder+"/")
+	if err != nil {
+		return deleted, err
+	}
+	for _, object := range objects {
+		if object.LastModified.After(cutoff) {
+			continue
+		}
+
+		recorded, err := s.repo.ExistsByKey(object.Key)
+		if err != nil {
+			return deleted, err
+		}
+		if recorded {
+			continue
+		}
+
+		// Files uploaded before attachments were recorded are only known through attachment_url
+		inUse, err := s.transactionRepo.AttachmentURLInUse(s.storage.URLForKey(object.Key))
+		if err != nil {
+			return deleted, err
+		}
+		if inUse {
+			continue
+		}
+
+		if err := s.storage.DeleteObject(ctx, object.Key); err != nil {
+			fmt.Printf("Warning: failed to delete unreferenced object %s: %v\n", object.Key, err)
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// RunGarbageCollector collects unreferenced attachments now and then on every interval
+// It blocks, so callers run it in its own goroutine
+func (s *AttachmentService) RunGarbageCollector(gracePeriod, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.CollectGarbage(gracePeriod); err != nil {
+			fmt.Printf("Warning: failed to collect attachment garbage: %v\n", err)
+		} else if count > 0 {
+			fmt.Printf("Deleted %d unreferenced attachments\n", count)
+		}
+		<-ticker.C
+	}
+}
+
+// checkRoom checks that a live transaction exists and can take more attachments
+func (s *AttachmentService) checkRoom(workspaceID, transactionID string, adding int) error {
+	transaction, err := s.transactionRepo.GetByID(transactionID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if transaction == nil {
+		return fmt.Errorf("transaction not found")
+	}
+
+	count, err := s.repo.CountByTransactionID(workspaceID, transactionID)
+	if err != nil {
+		return err
+	}
+	if int(count)+adding > maxAttachmentsPerTransaction {
+		return fmt.Errorf("a transaction can have at most %d attachments", maxAttachmentsPerTransaction)
+	}
+
+	return nil
+}
+
+// storeFile runs content through the image pipeline, stores it with a thumbnail when it is an image,
+// and records the stored file on the attachment
+func (s *AttachmentService) storeFile(attachment *models.Attachment, content []byte) error {
+	processed, err := utils.ProcessImage(content, attachment.ContentType, s.maxImageDimension, s.maxImagePixels)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if _, err := s.storage.PutObject(ctx, attachment.Key, processed.Content, processed.ContentType); err != nil {
+		return err
+	}
+
+	checksum := sha256.Sum256(processed.Content)
+	attachment.ContentType = processed.ContentType
+	attachment.Size = int64(len(processed.Content))
+	attachment.Checksum = hex.EncodeToString(checksum[:])
+	attachment.Width = processed.Width
+	attachment.Height = processed.Height
+
+	thumbnail, err := utils.MakeThumbnail(processed.Content, processed.ContentType, utils.ThumbnailSize, s.maxImagePixels)
+	if errors.Is(err, utils.ErrUnsupportedImage) {
+		return nil
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to create thumbnail of %s: %v\n", attachment.Key, err)
+		return nil
+	}
+
+	thumbnailKey := utils.VariantKey(attachment.Key, "thumb", thumbnail.ContentType)
+	if _, err := s.storage.PutObject(ctx, thumbnailKey, thumbnail.Content, thumbnail.ContentType); err != nil {
+		fmt.Printf("Warning: failed to store thumbnail of %s: %v\n", attachment.Key, err)
+		return nil
+	}
+	attachment.ThumbnailKey = thumbnailKey
+
+	return nil
+}
+
+// complete checks the content of a direct upload against its declared type and processes it like an API upload
+func (s *AttachmentService) complete(attachment *models.Attachment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	content, _, err := s.storage.GetObject(ctx, attachment.Key)
+	if errors.Is(err, utils.ErrObjectNotFound) {
+		return fmt.Errorf("attachment %s has not been uploaded yet", attachment.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	contentType, err := utils.CheckContentType(content, attachment.ContentType, s.allowedTypes)
+	if err != nil {
+		if removeErr := s.remove(attachment); removeErr != nil {
+			fmt.Printf("Warning: failed to delete rejected attachment %s: %v\n", attachment.ID, removeErr)
+		}
+		return err
+	}
+	attachment.ContentType = contentType
+
+	if err := s.storeFile(attachment, content); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateFile(attachment)
+}
+
+// sign fills in the signed download and thumbnail URLs of an attachment
+func (s *AttachmentService) sign(attachment *models.Attachment) {
+	downloadURL, _, err := s.storage.PresignGet(attachment.Key)
+	if err != nil {
+		fmt.Printf("Warning: failed to sign attachment %s: %v\n", attachment.ID, err)
+		return
+	}
+	attachment.DownloadURL = downloadURL
+
+	if attachment.ThumbnailKey != "" {
+		if thumbnailURL, _, err := s.storage.PresignGet(attachment.ThumbnailKey); err == nil {
+			attachment.ThumbnailURL = thumbnailURL
+		}
+	}
+}
+
+// attachmentKey builds a unique storage key for a file of a workspace, with the extension of its content type
+func attachmentKey(workspaceID, contentType string) string {
+	return utils.ObjectKey(attachmentFolder+"/"+workspaceID, contentType)
+}
+
+// remove deletes the stored files of an attachment and then its record
+func (s *AttachmentService) remove(attachment *models.Attachment) error {
+	if err := s.deleteFiles(attachment); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(attachment.ID, attachment.WorkspaceID)
+}
+
+// deleteFiles deletes the stored file of an attachment and its thumbnail
+func (s *AttachmentService) deleteFiles(attachment *models.Attachment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if attachment.ThumbnailKey != "" {
+		if err := s.storage.DeleteObject(ctx, attachment.ThumbnailKey); err != nil {
+			return err
+		}
+	}
+
+	return s.storage.DeleteObject(ctx, attachment.Key)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// auditIgnoredFields are stored fields that change as a side effect and would only add noise to a diff
+var auditIgnoredFields = map[string]bool{
+	"_id":        true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+	"search":     true,
+	"spent":      true, // Budget spending is recalculated from transactions
+}
+
+// AuditService records and reads the change history of transactions, accounts, categories and budgets
+type AuditService struct {
+	repo *repositories.AuditRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repo *repositories.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record stores a change as the next version of the entity
+// The entry carries the metadata (entity, action, actor, source); before is nil for creates and after is nil for deletes.
+// Failures are logged instead of returned so that a missing audit entry never undoes the change itself.
+func (s *AuditService) Record(entry models.AuditEntry, before, after interface{}) {
+	if entry.Source == "" {
+		entry.Source = models.AuditSourceAPI
+	}
+
+	beforeDoc, err := auditDocument(before)
+	if err != nil {
+		fmt.Printf("Warning: failed to record %s %s audit entry: %v\n", entry.EntityType, entry.EntityID, err)
+		return
+	}
+	afterDoc, err := auditDocument(after)
+	if err != nil {
+		fmt.Printf("Warning: failed to record %s %s audit entry: %v\n", entry.EntityType, entry.EntityID, err)
+		return
+	}
+
+	entry.Changes = diffAuditDocuments(beforeDoc, afterDoc)
+	if len(entry.Changes) == 0 && entry.Action == models.AuditActionUpdate {
+		// Nothing actually changed
+		return
+	}
+
+	entry.Snapshot = afterDoc
+	if afterDoc == nil {
+		entry.Snapshot = beforeDoc
+	}
+	delete(entry.Snapshot, "search")
+
+	if err := s.repo.Create(&entry); err != nil {
+		fmt.Printf("Warning: failed to record %s %s audit entry: %v\n", entry.EntityType, entry.EntityID, err)
+	}
+}
+
+// GetHistory retrieves the versions of an entity, newest first
+func (s *AuditService) GetHistory(workspaceID, entityType, entityID string) ([]models.AuditEntry, error) {
+	return s.repo.GetByEntity(workspaceID, entityType, entityID)
+}
+
+// GetVersion retrieves one version of an entity
+func (s *AuditService) GetVersion(workspaceID, entityType, entityID string, version int) (*models.AuditEntry, error) {
+	entry, err := s.repo.GetVersion(workspaceID, entityType, entityID, version)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("version %d not found", version)
+	}
+	return entry, nil
+}
+
+// DecodeSnapshot decodes the entity state stored in an audit entry into out
+func (s *AuditService) DecodeSnapshot(entry *models.AuditEntry, out interface{}) error {
+	if entry.Snapshot == nil {
+		return fmt.Errorf("version %d has no snapshot", entry.Version)
+	}
+
+	data, err := bson.Marshal(entry.Snapshot)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, out)
+}
+
+// auditDocument converts an entity into its stored BSON form, or nil when there is no entity
+func auditDocument(entity interface{}) (bson.M, error) {
+	if entity == nil {
+		return nil, nil
+	}
+	if value := reflect.ValueOf(entity); value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil, nil
+	}
+
+	data, err := bson.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// diffAuditDocuments lists the fields whose values differ between two documents, in field order
+func diffAuditDocuments(before, after bson.M) []models.AuditFieldChange {
+	fields := make(map[string]bool)
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		if !auditIgnoredFields[field] {
+			names = append(names, field)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []models.AuditFieldChange{}
+	for _, field := range names {
+		beforeValue, afterValue := before[field], after[field]
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		changes = append(changes, models.AuditFieldChange{
+			Field:  field,
+			Before: beforeValue,
+			After:  afterValue,
+		})
+	}
+
+	return changes
+}
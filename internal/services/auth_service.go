@@ -8,7 +8,10 @@ import (
 	"finance-hub-api/internal/repositories"
 	"finance-hub-api/internal/utils"
 	"fmt"
+	"strings"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 var (
@@ -22,22 +25,31 @@ var (
 	ErrEmailNotVerified    = errors.New("email not verified")
 	ErrTokenExpired        = errors.New("token has expired")
 	ErrTokenAlreadyUsed    = errors.New("token already used")
+	ErrInvalidTimezone     = errors.New("invalid timezone")
+	ErrEmptyFullName       = errors.New("full name cannot be empty")
+	ErrInvalidAvatar       = errors.New("invalid avatar image")
+	ErrInvalidAvatarURL    = errors.New("avatar files must be uploaded through /uploads/avatar")
 )
 
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo      *repositories.UserRepository
-	tokenRepo     *repositories.VerificationTokenRepository
-	googleClient  *utils.GoogleOAuthClient
-	emailService  *utils.EmailService
-	jwtSecret     string
-	jwtExpiresIn  string
+	userRepo        *repositories.UserRepository
+	tokenRepo       *repositories.VerificationTokenRepository
+	googleClient    *utils.GoogleOAuthClient
+	emailService    *utils.EmailService
+	storage         utils.Storage
+	categoryService *CategoryService
+	maxImagePixels  int
+	jwtSecret       string
+	jwtExpiresIn    string
 }
 
 // NewAuthService creates a new auth service
 func NewAuthService(
 	userRepo *repositories.UserRepository,
 	tokenRepo *repositories.VerificationTokenRepository,
+	storage utils.Storage,
+	categoryService *CategoryService,
 	cfg *config.Config,
 ) *AuthService {
 	googleClient := utils.NewGoogleOAuthClient(
@@ -49,12 +61,15 @@ func NewAuthService(
 	emailService := utils.NewEmailService(cfg)
 
 	return &AuthService{
-		userRepo:     userRepo,
-		tokenRepo:    tokenRepo,
-		googleClient: googleClient,
-		emailService: emailService,
-		jwtSecret:    cfg.JWT.Secret,
-		jwtExpiresIn: cfg.JWT.ExpiresIn,
+		userRepo:        userRepo,
+		tokenRepo:       tokenRepo,
+		googleClient:    googleClient,
+		emailService:    emailService,
+		storage:         storage,
+		categoryService: categoryService,
+		maxImagePixels:  cfg.Storage.MaxImagePixels,
+		jwtSecret:       cfg.JWT.Secret,
+		jwtExpiresIn:    cfg.JWT.ExpiresIn,
 	}
 }
 
@@ -87,6 +102,7 @@ func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest)
 		Email:         req.Email,
 		PasswordHash:  &hashedPassword,
 		FullName:      req.FullName,
+		Locale:        CategoryLocale(req.Locale),
 		AuthProvider:  "email",
 		EmailVerified: false,
 		IsActive:      true,
@@ -99,6 +115,8 @@ func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest)
 		return nil, err
 	}
 
+	s.seedPersonalWorkspace(user)
+
 	// Update last login
 	_ = s.userRepo.UpdateLastLogin(ctx, user.ID)
 
@@ -211,13 +229,15 @@ func (s *AuthService) VerifyGoogleToken(ctx context.Context, idToken string) (*m
 			// Create new user
 			fmt.Println("Creating new user...")
 			user = &models.User{
-				Email:         googleUserInfo.Email,
-				FullName:      googleUserInfo.Name,
-				AvatarURL:     &googleUserInfo.Picture,
-				GoogleID:      &googleUserInfo.Sub,
-				AuthProvider:  "google",
-				EmailVerified: googleUserInfo.EmailVerified,
-				IsActive:      true,
+				Email:            googleUserInfo.Email,
+				FullName:         googleUserInfo.Name,
+				AvatarURL:        &googleUserInfo.Picture,
+				GooglePictureURL: &googleUserInfo.Picture,
+				Locale:           CategoryLocale(googleUserInfo.Locale),
+				GoogleID:         &googleUserInfo.Sub,
+				AuthProvider:     "google",
+				EmailVerified:    googleUserInfo.EmailVerified,
+				IsActive:         true,
 			}
 
 			if err := s.userRepo.Create(ctx, user); err != nil {
@@ -226,6 +246,7 @@ func (s *AuthService) VerifyGoogleToken(ctx context.Context, idToken string) (*m
 			}
 
 			fmt.Printf("New user created with ID: %s\n", user.ID)
+			s.seedPersonalWorkspace(user)
 			isNewUser = true
 		} else if err == nil {
 			// Link Google to existing email user
@@ -301,10 +322,182 @@ func (s *AuthService) GetUserProfile(ctx context.Context, userID string) (*model
 		return nil, ErrUserNotFound
 	}
 
-	profile := repositories.ToUserProfile(user)
+	profile := s.toUserProfile(user)
 	return &profile, nil
 }
 
+// seedPersonalWorkspace creates the default categories in a new user's personal workspace, in the user's locale
+// The personal workspace has the user's ID; registration does not fail when seeding does.
+func (s *AuthService) seedPersonalWorkspace(user *models.User) {
+	if _, err := s.categoryService.SeedDefaultCategories(user.ID, user.ID, user.Locale); err != nil {
+		fmt.Printf("Warning: failed to seed default categories for user %s: %v\n", user.ID, err)
+	}
+}
+
+// UpdateProfile updates the fields set in the request and returns the new profile
+func (s *AuthService) UpdateProfile(ctx context.Context, userID string, req *models.UpdateProfileRequest) (*models.UserProfile, error) {
+	updates := bson.M{}
+	if req.FullName != nil {
+		fullName := strings.TrimSpace(*req.FullName)
+		if fullName == "" {
+			return nil, ErrEmptyFullName
+		}
+		updates["full_name"] = fullName
+	}
+	if req.Locale != nil {
+		updates["locale"] = *req.Locale
+	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" || *req.Timezone == "Local" {
+			return nil, ErrInvalidTimezone
+		}
+		updates["timezone"] = *req.Timezone
+	}
+	if req.Preferences != nil {
+		preferences := map[string]string{
+			"currency":      req.Preferences.Currency,
+			"date_format":   req.Preferences.DateFormat,
+			"theme":         req.Preferences.Theme,
+			"start_of_week": req.Preferences.StartOfWeek,
+		}
+		for field, value := range preferences {
+			if value != "" {
+				updates["preferences."+field] = value
+			}
+		}
+	}
+	if req.AvatarURL != nil {
+		if _, ok := s.storage.KeyFromURL(*req.AvatarURL); ok {
+			return nil, ErrInvalidAvatarURL
+		}
+	}
+
+	if err := s.userRepo.Update(ctx, userID, updates); err != nil {
+		if err == repositories.ErrUserNotFound {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if req.RemoveAvatar {
+		return s.RemoveAvatar(ctx, userID)
+	}
+	if req.AvatarURL != nil {
+		if err := s.replaceAvatar(ctx, userID, req.AvatarURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetUserProfile(ctx, userID)
+}
+
+// SetAvatar stores an uploaded image as the user's avatar and deletes the previous uploaded one
+// Images are cropped to a square, with a small variant next to them; WebP cannot be decoded, so it is only stripped of metadata.
+func (s *AuthService) SetAvatar(ctx context.Context, userID string, content []byte, contentType string) (*models.UserProfile, error) {
+	var avatar, thumbnail *utils.ProcessedImage
+	var err error
+	key := ""
+	if contentType == "image/webp" {
+		avatar, err = utils.ProcessImage(content, contentType, utils.AvatarSize, s.maxImagePixels)
+		key = utils.ObjectKey("avatars", contentType)
+	} else if avatar, err = utils.SquareCrop(content, contentType, utils.AvatarSize, s.maxImagePixels); err == nil {
+		thumbnail, err = utils.SquareCrop(content, contentType, utils.AvatarThumbnailSize, s.maxImagePixels)
+		key = utils.NewAvatarKey(avatar.ContentType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAvatar, err)
+	}
+
+	avatarURL, err := s.storage.PutObject(ctx, key, avatar.Content, avatar.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload avatar: %w", err)
+	}
+	if thumbnailKey, ok := utils.AvatarThumbnailKey(key); ok && thumbnail != nil {
+		if _, err := s.storage.PutObject(ctx, thumbnailKey, thumbnail.Content, thumbnail.ContentType); err != nil {
+			s.deleteStoredAvatar(ctx, &avatarURL)
+			return nil, fmt.Errorf("failed to upload avatar: %w", err)
+		}
+	}
+
+	if err := s.replaceAvatar(ctx, userID, &avatarURL); err != nil {
+		s.deleteStoredAvatar(ctx, &avatarURL)
+		return nil, err
+	}
+
+	return s.GetUserProfile(ctx, userID)
+}
+
+// RemoveAvatar removes the user's avatar, going back to the Google picture when there is one and to initials otherwise
+func (s *AuthService) RemoveAvatar(ctx context.Context, userID string) (*models.UserProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	var restored *string
+	if user.GooglePictureURL != nil && *user.GooglePictureURL != "" {
+		restored = user.GooglePictureURL
+	}
+	if err := s.replaceAvatar(ctx, userID, restored); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserProfile(ctx, userID)
+}
+
+// replaceAvatar saves the new avatar URL and deletes the files of the previous avatar if it was uploaded
+func (s *AuthService) replaceAvatar(ctx context.Context, userID string, avatarURL *string) error {
+	previous, err := s.userRepo.SetAvatar(ctx, userID, avatarURL)
+	if err != nil {
+		if err == repositories.ErrUserNotFound {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if previous != nil && (avatarURL == nil || *previous != *avatarURL) {
+		s.deleteStoredAvatar(ctx, previous)
+	}
+	return nil
+}
+
+// deleteStoredAvatar deletes an uploaded avatar and its small variant; other URLs, such as Google pictures, are ignored
+func (s *AuthService) deleteStoredAvatar(ctx context.Context, avatarURL *string) {
+	key, ok := s.storage.KeyFromURL(*avatarURL)
+	if !ok {
+		return
+	}
+
+	keys := []string{key}
+	if thumbnailKey, ok := utils.AvatarThumbnailKey(key); ok {
+		keys = append(keys, thumbnailKey)
+	}
+	for _, key := range keys {
+		if err := s.storage.DeleteObject(ctx, key); err != nil {
+			fmt.Printf("Warning: failed to delete avatar file %s: %v\n", key, err)
+		}
+	}
+}
+
+// toUserProfile converts a user to a profile whose uploaded avatar and its small variant have signed download URLs
+func (s *AuthService) toUserProfile(user *models.User) models.UserProfile {
+	profile := repositories.ToUserProfile(user)
+	if profile.AvatarURL == nil {
+		return profile
+	}
+
+	if key, ok := s.storage.KeyFromURL(*profile.AvatarURL); ok {
+		if thumbnailKey, ok := utils.AvatarThumbnailKey(key); ok {
+			if thumbnailURL, _, err := s.storage.PresignGet(thumbnailKey); err == nil {
+				profile.AvatarThumbnailURL = &thumbnailURL
+			}
+		}
+	}
+	signed := s.storage.SignURL(*profile.AvatarURL)
+	profile.AvatarURL = &signed
+	return profile
+}
+
 // generateAuthResponse generates authentication response with tokens
 func (s *AuthService) generateAuthResponse(user *models.User, isNewUser bool) (*models.AuthResponse, error) {
 	// Parse JWT expiration duration
@@ -326,7 +519,7 @@ func (s *AuthService) generateAuthResponse(user *models.User, isNewUser bool) (*
 	}
 
 	// Create user profile
-	profile := repositories.ToUserProfile(user)
+	profile := s.toUserProfile(user)
 
 	return &models.AuthResponse{
 		User:         profile,
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// fallbackCategoryNames are tried, normalized, when no category can be guessed from a notification
+var fallbackCategoryNames = []string{"khac", "chi phi khac", "thu nhap khac", "chua phan loai", "other", "others", "uncategorized"}
+
+// BankNotificationService handles turning bank SMS and push notifications into transactions
+type BankNotificationService struct {
+	repo               *repositories.BankNotificationRepository
+	accountRepo        *repositories.AccountRepository
+	categoryRepo       *repositories.CategoryRepository
+	transactionService *TransactionService
+}
+
+// NewBankNotificationService creates a new bank notification service
+func NewBankNotificationService(
+	repo *repositories.BankNotificationRepository,
+	accountRepo *repositories.AccountRepository,
+	categoryRepo *repositories.CategoryRepository,
+	transactionService *TransactionService,
+) *BankNotificationService {
+	return &BankNotificationService{
+		repo:               repo,
+		accountRepo:        accountRepo,
+		categoryRepo:       categoryRepo,
+		transactionService: transactionService,
+	}
+}
+
+// IngestNotification parses a notification, records its transaction against the matching account and
+// flags it when the balance the bank reports differs from the account's tracked balance
+func (s *BankNotificationService) IngestNotification(workspaceID, userID string, req models.IngestBankNotificationRequest) (*models.BankNotification, error) {
+	text := strings.TrimSpace(req.Text)
+	hash := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	textHash := hex.EncodeToString(hash[:])
+
+	existing, err := s.repo.GetByTextHash(workspaceID, textHash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.TransactionID != "" {
+		return nil, fmt.Errorf("notification was already recorded as transaction %s", existing.TransactionID)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("notification was already recorded")
+	}
+
+	account, notification, err := s.matchAccount(workspaceID, text, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionType := "expense"
+	if notification.Direction == models.BankNotificationDirectionIn {
+		transactionType = "income"
+	}
+
+	categoryID := req.CategoryID
+	if categoryID == nil || *categoryID == "" {
+		category, err := s.guessCategory(workspaceID, transactionType, notification.Reference)
+		if err != nil {
+			return nil, err
+		}
+		categoryID = &category.ID
+	}
+
+	transactionDate := time.Now()
+	if notification.OccurredAt != nil {
+		transactionDate = *notification.OccurredAt
+	}
+
+	// The notification is stored before its transaction, so the unique text index turns away a concurrent
+	// duplicate before it can move the balance
+	notification.WorkspaceID = workspaceID
+	notification.UserID = userID
+	notification.AccountID = account.ID
+	notification.Text = text
+	notification.TextHash = textHash
+	created, err := s.repo.Create(notification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store bank notification: %v", err)
+	}
+	if !created {
+		return nil, fmt.Errorf("notification was already recorded")
+	}
+
+	// The bank has already settled the money, so the transaction starts out cleared
+	transaction, err := s.transactionService.CreateTransaction(workspaceID, userID, models.CreateTransactionRequest{
+		AccountID:       account.ID,
+		CategoryID:      categoryID,
+		Type:            transactionType,
+		Amount:          notification.Amount,
+		Description:     notification.Reference,
+		TransactionDate: transactionDate,
+		Status:          models.TransactionStatusCleared,
+		Source:          models.AuditSourceImport,
+	})
+	if err != nil {
+		// Free the text so the notification can be sent again
+		if deleteErr := s.repo.Delete(notification.ID, workspaceID); deleteErr != nil {
+			fmt.Printf("Warning: failed to delete bank notification %s: %v\n", notification.ID, deleteErr)
+		}
+		return nil, err
+	}
+
+	updatedAccount, err := s.accountRepo.GetByID(account.ID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if updatedAccount != nil {
+		account = updatedAccount
+	}
+
+	notification.TransactionID = transaction.ID
+	notification.AccountBalance = account.Balance
+	if notification.Balance != nil {
+		notification.BalanceDifference = utils.RoundToTwoDecimals(*notification.Balance - account.Balance)
+		// VND has no minor unit, so anything under one dong is rounding
+		notification.BalanceMismatch = math.Abs(notification.BalanceDifference) >= 1
+	}
+
+	if err := s.repo.LinkTransaction(notification); err != nil {
+		fmt.Printf("Warning: failed to link bank notification %s to transaction %s: %v\n", notification.ID, transaction.ID, err)
+	}
+
+	return notification, nil
+}
+
+// GetNotifications retrieves the ingested notifications of a workspace
+func (s *BankNotificationService) GetNotifications(workspaceID string, query models.BankNotificationFilterQuery) ([]models.BankNotification, error) {
+	return s.repo.GetAll(workspaceID, query)
+}
+
+// GetSupportedBanks returns the bank codes whose notifications can be parsed
+func (s *BankNotificationService) GetSupportedBanks() []string {
+	return utils.SupportedNotificationBanks()
+}
+
+// matchAccount finds the bank account a notification belongs to and parses it with that bank's template
+// Without an explicit account, every active bank account whose template reads the text is a candidate;
+// the account number printed in the message settles ties.
+func (s *BankNotificationService) matchAccount(workspaceID, text string, accountID *string) (*models.Account, *models.BankNotification, error) {
+	location := utils.VietnamLocation()
+
+	if accountID != nil && *accountID != "" {
+		account, err := s.accountRepo.GetByID(*accountID, workspaceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if account == nil {
+			return nil, nil, fmt.Errorf("account not found")
+		}
+		if account.BankCode == nil || *account.BankCode == "" {
+			return nil, nil, fmt.Errorf("account %s has no bank code", account.Name)
+		}
+
+		notification, err := utils.ParseBankNotification(*account.BankCode, text, location)
+		if err != nil {
+			return nil, nil, err
+		}
+		return account, notification, nil
+	}
+
+	accounts, err := s.accountRepo.GetAllInWorkspace(workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type candidate struct {
+		account      *models.Account
+		notification *models.BankNotification
+		confirmed    bool
+	}
+	var candidates []candidate
+	for i := range accounts {
+		account := &accounts[i]
+		if !account.IsActive || account.BankCode == nil || !utils.HasNotificationTemplate(*account.BankCode) {
+			continue
+		}
+
+		notification, err := utils.ParseBankNotification(*account.BankCode, text, location)
+		if err != nil {
+			continue
+		}
+
+		confirmed := false
+		if notification.AccountHint != "" && account.AccountNumber != nil && *account.AccountNumber != "" {
+			if !utils.MatchAccountNumber(notification.AccountHint, *account.AccountNumber) {
+				continue
+			}
+			confirmed = true
+		}
+		candidates = append(candidates, candidate{account: account, notification: notification, confirmed: confirmed})
+	}
+
+	if len(candidates) > 1 {
+		var confirmed []candidate
+		for _, c := range candidates {
+			if c.confirmed {
+				confirmed = append(confirmed, c)
+			}
+		}
+		if len(confirmed) > 0 {
+			candidates = confirmed
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil, fmt.Errorf("notification does not match any bank account in this workspace")
+	case 1:
+		return candidates[0].account, candidates[0].notification, nil
+	default:
+		return nil, nil, fmt.Errorf("notification matches %d bank accounts; specify account_id", len(candidates))
+	}
+}
+
+// guessCategory picks a category from the transfer content, falling back to a catch-all category
+func (s *BankNotificationService) guessCategory(workspaceID, transactionType string, reference *string) (*models.Category, error) {
+	categories, err := s.categoryRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reference != nil {
+		if category := utils.GuessCategory(*reference, transactionType, categories); category != nil {
+			return category, nil
+		}
+	}
+
+	for _, name := range fallbackCategoryNames {
+		for i := range categories {
+			category := &categories[i]
+			if category.Type != transactionType && category.Type != "both" {
+				continue
+			}
+			if utils.NormalizeSearchText(category.Name) == name {
+				return category, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("could not pick a category for this notification; specify category_id")
+}
@@ -12,6 +12,7 @@ type BudgetService struct {
 	repo            *repositories.BudgetRepository
 	transactionRepo *repositories.TransactionRepository
 	categoryRepo    *repositories.CategoryRepository
+	auditService    *AuditService
 }
 
 // NewBudgetService creates a new budget service
@@ -19,16 +20,18 @@ func NewBudgetService(
 	repo *repositories.BudgetRepository,
 	transactionRepo *repositories.TransactionRepository,
 	categoryRepo *repositories.CategoryRepository,
+	auditService *AuditService,
 ) *BudgetService {
 	return &BudgetService{
 		repo:            repo,
 		transactionRepo: transactionRepo,
 		categoryRepo:    categoryRepo,
+		auditService:    auditService,
 	}
 }
 
 // CreateOrUpdateBudget creates a new budget or updates if exists
-func (s *BudgetService) CreateOrUpdateBudget(userID string, req models.CreateBudgetRequest) (*models.Budget, error) {
+func (s *BudgetService) CreateOrUpdateBudget(workspaceID, userID string, req models.CreateBudgetRequest) (*models.Budget, error) {
 	// Validate scope and categoryID
 	if req.Scope == "category" && req.CategoryID == nil {
 		return nil, fmt.Errorf("category_id is required for category scope")
@@ -40,7 +43,7 @@ func (s *BudgetService) CreateOrUpdateBudget(userID string, req models.CreateBud
 
 	// Validate category exists if provided
 	if req.CategoryID != nil && *req.CategoryID != "" {
-		category, err := s.categoryRepo.GetByID(*req.CategoryID, userID)
+		category, err := s.categoryRepo.GetByID(*req.CategoryID, workspaceID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to validate category: %v", err)
 		}
@@ -50,7 +53,7 @@ func (s *BudgetService) CreateOrUpdateBudget(userID string, req models.CreateBud
 	}
 
 	// Check if budget already exists
-	existing, err := s.repo.GetByMonthAndScope(userID, req.Month, req.Scope, req.CategoryID)
+	existing, err := s.repo.GetByMonthAndScope(workspaceID, req.Month, req.Scope, req.CategoryID)
 	if err != nil {
 		return nil, err
 	}
@@ -62,36 +65,52 @@ func (s *BudgetService) CreateOrUpdateBudget(userID string, req models.CreateBud
 			AlertEnabled:   &req.AlertEnabled,
 			AlertThreshold: req.AlertThreshold,
 		}
-		updated, err := s.repo.Update(existing.ID, userID, updateReq)
+		updated, err := s.repo.Update(existing.ID, workspaceID, updateReq)
 		if err != nil {
 			return nil, err
 		}
 
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityBudget,
+			EntityID:    existing.ID,
+			Action:      models.AuditActionUpdate,
+			ActorID:     userID,
+		}, existing, updated)
+
 		// Recalculate spent
-		if err := s.UpdateBudgetSpent(updated.ID, userID); err != nil {
+		if err := s.UpdateBudgetSpent(updated.ID, workspaceID); err != nil {
 			return nil, err
 		}
 
-		return s.repo.GetByID(updated.ID, userID)
+		return s.repo.GetByID(updated.ID, workspaceID)
 	}
 
 	// Create new budget
-	budget, err := s.repo.Create(userID, req)
+	budget, err := s.repo.Create(workspaceID, userID, req)
 	if err != nil {
 		return nil, err
 	}
 
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityBudget,
+		EntityID:    budget.ID,
+		Action:      models.AuditActionCreate,
+		ActorID:     userID,
+	}, nil, budget)
+
 	// Calculate initial spent
-	if err := s.UpdateBudgetSpent(budget.ID, userID); err != nil {
+	if err := s.UpdateBudgetSpent(budget.ID, workspaceID); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetByID(budget.ID, userID)
+	return s.repo.GetByID(budget.ID, workspaceID)
 }
 
 // GetBudget retrieves a budget by ID
-func (s *BudgetService) GetBudget(id, userID string) (*models.Budget, error) {
-	budget, err := s.repo.GetByID(id, userID)
+func (s *BudgetService) GetBudget(id, workspaceID string) (*models.Budget, error) {
+	budget, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -100,36 +119,36 @@ func (s *BudgetService) GetBudget(id, userID string) (*models.Budget, error) {
 	}
 
 	// Update spent before returning
-	if err := s.UpdateBudgetSpent(budget.ID, userID); err != nil {
+	if err := s.UpdateBudgetSpent(budget.ID, workspaceID); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetByID(budget.ID, userID)
+	return s.repo.GetByID(budget.ID, workspaceID)
 }
 
 // GetBudgetsByMonth retrieves all budgets for a specific month
-func (s *BudgetService) GetBudgetsByMonth(userID, month string) ([]models.Budget, error) {
-	budgets, err := s.repo.GetByMonth(userID, month)
+func (s *BudgetService) GetBudgetsByMonth(workspaceID, month string) ([]models.Budget, error) {
+	budgets, err := s.repo.GetByMonth(workspaceID, month)
 	if err != nil {
 		return nil, err
 	}
 
 	// Update spent for all budgets
 	for _, budget := range budgets {
-		if err := s.UpdateBudgetSpent(budget.ID, userID); err != nil {
+		if err := s.UpdateBudgetSpent(budget.ID, workspaceID); err != nil {
 			// Log error but continue
 			continue
 		}
 	}
 
 	// Fetch updated budgets
-	return s.repo.GetByMonth(userID, month)
+	return s.repo.GetByMonth(workspaceID, month)
 }
 
 // UpdateBudget updates a budget
-func (s *BudgetService) UpdateBudget(id, userID string, req models.UpdateBudgetRequest) (*models.Budget, error) {
+func (s *BudgetService) UpdateBudget(id, workspaceID, actorID string, req models.UpdateBudgetRequest) (*models.Budget, error) {
 	// Check if budget exists
-	existing, err := s.repo.GetByID(id, userID)
+	existing, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -137,18 +156,26 @@ func (s *BudgetService) UpdateBudget(id, userID string, req models.UpdateBudgetR
 		return nil, fmt.Errorf("budget not found")
 	}
 
-	updated, err := s.repo.Update(id, userID, req)
+	updated, err := s.repo.Update(id, workspaceID, req)
 	if err != nil {
 		return nil, err
 	}
 
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityBudget,
+		EntityID:    id,
+		Action:      models.AuditActionUpdate,
+		ActorID:     actorID,
+	}, existing, updated)
+
 	return updated, nil
 }
 
 // DeleteBudget deletes a budget
-func (s *BudgetService) DeleteBudget(id, userID string) error {
+func (s *BudgetService) DeleteBudget(id, workspaceID, actorID string) error {
 	// Check if budget exists
-	existing, err := s.repo.GetByID(id, userID)
+	existing, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return err
 	}
@@ -156,12 +183,24 @@ func (s *BudgetService) DeleteBudget(id, userID string) error {
 		return fmt.Errorf("budget not found")
 	}
 
-	return s.repo.Delete(id, userID)
+	if err := s.repo.Delete(id, workspaceID); err != nil {
+		return err
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityBudget,
+		EntityID:    id,
+		Action:      models.AuditActionDelete,
+		ActorID:     actorID,
+	}, existing, nil)
+
+	return nil
 }
 
 // UpdateBudgetSpent calculates and updates the spent amount for a budget
-func (s *BudgetService) UpdateBudgetSpent(budgetID, userID string) error {
-	budget, err := s.repo.GetByID(budgetID, userID)
+func (s *BudgetService) UpdateBudgetSpent(budgetID, workspaceID string) error {
+	budget, err := s.repo.GetByID(budgetID, workspaceID)
 	if err != nil {
 		return err
 	}
@@ -176,6 +215,7 @@ func (s *BudgetService) UpdateBudgetSpent(budgetID, userID string) error {
 	}
 
 	var spent float64
+	var refundCategoryID string
 
 	if budget.Scope == "total" {
 		// Sum all expense transactions for the month
@@ -185,7 +225,7 @@ func (s *BudgetService) UpdateBudgetSpent(budgetID, userID string) error {
 			Type:      "expense",
 		}
 
-		transactions, _, err := s.transactionRepo.GetAll(userID, filters)
+		transactions, _, err := s.transactionRepo.GetAll(workspaceID, filters)
 		if err != nil {
 			return err
 		}
@@ -194,6 +234,8 @@ func (s *BudgetService) UpdateBudgetSpent(budgetID, userID string) error {
 			spent += tx.Amount
 		}
 	} else if budget.Scope == "category" && budget.CategoryID != nil {
+		refundCategoryID = *budget.CategoryID
+
 		// Sum expense transactions for the specific category
 		filters := models.TransactionFilterQuery{
 			StartDate:  startDate.Format("2006-01-02"),
@@ -202,7 +244,7 @@ func (s *BudgetService) UpdateBudgetSpent(budgetID, userID string) error {
 			CategoryID: *budget.CategoryID,
 		}
 
-		transactions, _, err := s.transactionRepo.GetAll(userID, filters)
+		transactions, _, err := s.transactionRepo.GetAll(workspaceID, filters)
 		if err != nil {
 			return err
 		}
@@ -212,8 +254,18 @@ func (s *BudgetService) UpdateBudgetSpent(budgetID, userID string) error {
 		}
 	}
 
+	// Refunds of expenses from this month give the money back to the budget
+	refunded, err := s.transactionRepo.SumRefunds(workspaceID, startDate, endDate, refundCategoryID)
+	if err != nil {
+		return err
+	}
+	spent -= refunded
+	if spent < 0 {
+		spent = 0
+	}
+
 	// Update spent in budget
-	return s.repo.UpdateSpent(budgetID, userID, spent)
+	return s.repo.UpdateSpent(budgetID, workspaceID, spent)
 }
 
 // parseMonthRange converts YYYY-MM format to start and end dates
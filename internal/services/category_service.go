@@ -1,27 +1,34 @@
 package services
 
 import (
+	"context"
 	"finance-hub-api/internal/models"
 	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
 	"fmt"
+	"strings"
 )
 
 // CategoryService handles business logic for categories
 type CategoryService struct {
-	repo        *repositories.CategoryRepository
+	repo            *repositories.CategoryRepository
 	transactionRepo *repositories.TransactionRepository
+	budgetRepo      *repositories.BudgetRepository
+	auditService    *AuditService
 }
 
 // NewCategoryService creates a new category service
-func NewCategoryService(repo *repositories.CategoryRepository, transactionRepo *repositories.TransactionRepository) *CategoryService {
+func NewCategoryService(repo *repositories.CategoryRepository, transactionRepo *repositories.TransactionRepository, budgetRepo *repositories.BudgetRepository, auditService *AuditService) *CategoryService {
 	return &CategoryService{
 		repo: repo,
 		transactionRepo: transactionRepo,
+		budgetRepo: budgetRepo,
+		auditService: auditService,
 	}
 }
 
 // CreateCategory creates a new category
-func (s *CategoryService) CreateCategory(userID string, req models.CreateCategoryRequest) (*models.Category, error) {
+func (s *CategoryService) CreateCategory(workspaceID, userID string, req models.CreateCategoryRequest) (*models.Category, error) {
 	// Validate category type
 	validTypes := map[string]bool{
 		"income":  true,
@@ -41,18 +48,31 @@ func (s *CategoryService) CreateCategory(userID string, req models.CreateCategor
 		if parent == nil {
 			return nil, fmt.Errorf("parent category not found")
 		}
-		// Parent must belong to the same user
-		if parent.UserID != userID {
+		// Parent must belong to the same workspace
+		if parent.WorkspaceID != workspaceID {
 			return nil, fmt.Errorf("parent category not found")
 		}
 	}
 
-	return s.repo.Create(userID, req)
+	category, err := s.repo.Create(workspaceID, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityCategory,
+		EntityID:    category.ID,
+		Action:      models.AuditActionCreate,
+		ActorID:     userID,
+	}, nil, category)
+
+	return category, nil
 }
 
 // GetCategory retrieves a category by ID
-func (s *CategoryService) GetCategory(id, userID string) (*models.Category, error) {
-	category, err := s.repo.GetByID(id, userID)
+func (s *CategoryService) GetCategory(id, workspaceID string) (*models.Category, error) {
+	category, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -62,13 +82,13 @@ func (s *CategoryService) GetCategory(id, userID string) (*models.Category, erro
 	return category, nil
 }
 
-// GetAllCategories retrieves all categories for a user
-func (s *CategoryService) GetAllCategories(userID string) ([]models.Category, error) {
-	return s.repo.GetAll(userID)
+// GetAllCategories retrieves all categories in a workspace
+func (s *CategoryService) GetAllCategories(workspaceID string) ([]models.Category, error) {
+	return s.repo.GetAll(workspaceID)
 }
 
 // GetCategoriesByType retrieves categories by type
-func (s *CategoryService) GetCategoriesByType(userID string, categoryType string) ([]models.Category, error) {
+func (s *CategoryService) GetCategoriesByType(workspaceID string, categoryType string) ([]models.Category, error) {
 	validTypes := map[string]bool{
 		"income":  true,
 		"expense": true,
@@ -78,18 +98,18 @@ func (s *CategoryService) GetCategoriesByType(userID string, categoryType string
 		return nil, fmt.Errorf("invalid category type")
 	}
 
-	return s.repo.GetByType(userID, categoryType)
+	return s.repo.GetByType(workspaceID, categoryType)
 }
 
 // GetParentCategories retrieves all parent categories
-func (s *CategoryService) GetParentCategories(userID string) ([]models.Category, error) {
-	return s.repo.GetParentCategories(userID)
+func (s *CategoryService) GetParentCategories(workspaceID string) ([]models.Category, error) {
+	return s.repo.GetParentCategories(workspaceID)
 }
 
 // GetChildCategories retrieves all child categories of a parent
-func (s *CategoryService) GetChildCategories(userID, parentID string) ([]models.Category, error) {
+func (s *CategoryService) GetChildCategories(workspaceID, parentID string) ([]models.Category, error) {
 	// Validate parent exists
-	parent, err := s.repo.GetByID(parentID, userID)
+	parent, err := s.repo.GetByID(parentID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -97,24 +117,19 @@ func (s *CategoryService) GetChildCategories(userID, parentID string) ([]models.
 		return nil, fmt.Errorf("parent category not found")
 	}
 
-	return s.repo.GetChildCategories(userID, parentID)
+	return s.repo.GetChildCategories(workspaceID, parentID)
 }
 
 // UpdateCategory updates a category
-func (s *CategoryService) UpdateCategory(id, userID string, req models.UpdateCategoryRequest) (*models.Category, error) {
+func (s *CategoryService) UpdateCategory(id, workspaceID, actorID string, req models.UpdateCategoryRequest) (*models.Category, error) {
 	// Check if category exists
-	existing, err := s.repo.GetByID(id, userID)
+	existing, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 	if existing == nil {
 		return nil, fmt.Errorf("category not found")
 	}
-	
-	// Cannot update default categories
-	if existing.IsDefault {
-		return nil, fmt.Errorf("cannot update default category")
-	}
 
 	// Validate type if provided
 	if req.Type != nil {
@@ -142,8 +157,8 @@ func (s *CategoryService) UpdateCategory(id, userID string, req models.UpdateCat
 		if parent == nil {
 			return nil, fmt.Errorf("parent category not found")
 		}
-		// Parent must belong to the same user
-		if parent.UserID != userID {
+		// Parent must belong to the same workspace
+		if parent.WorkspaceID != workspaceID {
 			return nil, fmt.Errorf("parent category not found")
 		}
 		
@@ -153,25 +168,35 @@ func (s *CategoryService) UpdateCategory(id, userID string, req models.UpdateCat
 		}
 	}
 
-	return s.repo.Update(id, userID, req)
+	updated, err := s.repo.Update(id, workspaceID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityCategory,
+		EntityID:    id,
+		Action:      models.AuditActionUpdate,
+		ActorID:     actorID,
+	}, existing, updated)
+
+	return updated, nil
 }
 
 // DeleteCategory deletes a category
-func (s *CategoryService) DeleteCategory(id, userID string) error {
+func (s *CategoryService) DeleteCategory(id, workspaceID, actorID string) error {
 	// Check if category exists
-	existing, err := s.repo.GetByID(id, userID)
+	existing, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return err
 	}
 	if existing == nil {
 		return fmt.Errorf("category not found")
 	}
-	if existing.IsDefault {
-		return fmt.Errorf("cannot delete default category")
-	}
 
 	// Check if category has children
-	childCount, err := s.repo.CountChildCategories(userID, id)
+	childCount, err := s.repo.CountChildCategories(workspaceID, id)
 	if err != nil {
 		return fmt.Errorf("failed to check child categories: %v", err)
 	}
@@ -180,33 +205,151 @@ func (s *CategoryService) DeleteCategory(id, userID string) error {
 	}
 
 	// Check if category is used in transactions
-	transactionCount, err := s.transactionRepo.CountByCategoryID(id, userID)
+	transactionCount, err := s.transactionRepo.CountByCategoryID(id, workspaceID)
 	if err != nil {
 		return fmt.Errorf("failed to check category usage: %v", err)
 	}
 	if transactionCount > 0 {
-		return fmt.Errorf("cannot delete category that is used in transactions")
+		return fmt.Errorf("cannot delete category that is used in transactions, including those in the trash")
+	}
+
+	if err := s.repo.Delete(context.Background(), id, workspaceID); err != nil {
+		return err
 	}
 
-	return s.repo.Delete(id, userID)
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityCategory,
+		EntityID:    id,
+		Action:      models.AuditActionDelete,
+		ActorID:     actorID,
+	}, existing, nil)
+
+	return nil
+}
+
+// MergeCategory folds a category into another one of a compatible type
+// Its transactions, including those in the trash, child categories and budgets move to the target and the
+// category is deleted, all in one MongoDB transaction. A budget for a month the target already has a budget
+// for is added to that budget. Nothing is merged while a reconciled transaction uses the category.
+func (s *CategoryService) MergeCategory(id, targetID, workspaceID, actorID string) (*models.MergeCategoryResult, error) {
+	if id == targetID {
+		return nil, fmt.Errorf("cannot merge a category into itself")
+	}
+
+	source, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, fmt.Errorf("category not found")
+	}
+
+	target, err := s.repo.GetByID(targetID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, fmt.Errorf("target category not found")
+	}
+	if target.Type != "both" && target.Type != source.Type {
+		return nil, fmt.Errorf("cannot merge %s category into %s category", source.Type, target.Type)
+	}
+
+	// A target below the category first takes the place of its ancestor, so the hierarchy does not become circular
+	var lifted *models.Category
+	ancestor := target
+	for depth := 0; depth < 10 && ancestor.ParentID != nil && *ancestor.ParentID != ""; depth++ {
+		if *ancestor.ParentID == id {
+			lifted = ancestor
+			break
+		}
+		if ancestor, err = s.repo.GetByID(*ancestor.ParentID, workspaceID); err != nil || ancestor == nil {
+			break
+		}
+	}
+
+	result := &models.MergeCategoryResult{}
+	var moved []models.Transaction
+	err = s.repo.RunInTransaction(func(ctx context.Context) error {
+		if lifted != nil {
+			var parentID *string
+			if source.ParentID != nil && *source.ParentID != "" {
+				parentID = source.ParentID
+			}
+			if err := s.repo.SetParent(ctx, lifted.ID, workspaceID, parentID); err != nil {
+				return fmt.Errorf("failed to move category: %v", err)
+			}
+		}
+
+		childrenMoved, err := s.repo.MoveChildren(ctx, workspaceID, id, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to move child categories: %v", err)
+		}
+		result.ChildrenMoved = childrenMoved
+
+		if moved, err = s.transactionRepo.ReassignCategory(ctx, workspaceID, id, targetID); err != nil {
+			return fmt.Errorf("failed to move transactions: %v", err)
+		}
+		for i := range moved {
+			if err := checkUnlocked(&moved[i]); err != nil {
+				return err
+			}
+		}
+		result.TransactionsUpdated = int64(len(moved))
+
+		if result.BudgetsMoved, result.BudgetsMerged, err = s.budgetRepo.MoveToCategory(ctx, workspaceID, id, targetID); err != nil {
+			return fmt.Errorf("failed to move budgets: %v", err)
+		}
+
+		return s.repo.Delete(ctx, id, workspaceID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range moved {
+		updated := moved[i]
+		updated.CategoryID = &targetID
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityTransaction,
+			EntityID:    moved[i].ID,
+			Action:      models.AuditActionUpdate,
+			ActorID:     actorID,
+		}, &moved[i], &updated)
+	}
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityCategory,
+		EntityID:    id,
+		Action:      models.AuditActionDelete,
+		ActorID:     actorID,
+	}, source, nil)
+
+	if result.Category, err = s.repo.GetByID(targetID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // IsCategoryInUse checks if a category is being used
-func (s *CategoryService) IsCategoryInUse(id, userID string) (*models.CategoryUsageResponse, error) {
+func (s *CategoryService) IsCategoryInUse(id, workspaceID string) (*models.CategoryUsageResponse, error) {
 	// Check if category exists
-	_, err := s.repo.GetByID(id, userID)
+	_, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Count child categories
-	childCount, err := s.repo.CountChildCategories(userID, id)
+	childCount, err := s.repo.CountChildCategories(workspaceID, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check child categories: %v", err)
 	}
 
 	// Count transactions using this category
-	transactionCount, err := s.transactionRepo.CountByCategoryID(id, userID)
+	transactionCount, err := s.transactionRepo.CountByCategoryID(id, workspaceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check category usage: %v", err)
 	}
@@ -220,3 +363,193 @@ func (s *CategoryService) IsCategoryInUse(id, userID string) (*models.CategoryUs
 		ChildCount:       childCount,
 	}, nil
 }
+
+// GetCategoryTemplates returns the default categories in every supported locale
+func (s *CategoryService) GetCategoryTemplates() []models.CategoryTemplate {
+	templates := make([]models.CategoryTemplate, 0, len(categoryTemplateLocales))
+	for _, locale := range categoryTemplateLocales {
+		templates = append(templates, categoryTemplate(locale))
+	}
+	return templates
+}
+
+// SeedDefaultCategories creates the default categories a workspace does not have yet
+// Defaults are matched by template key, so seeding again, in any locale, only fills in what is missing.
+func (s *CategoryService) SeedDefaultCategories(workspaceID, userID, locale string) ([]models.Category, error) {
+	existing, err := s.repo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	seeded := make(map[string]string)
+	for _, category := range existing {
+		if category.TemplateKey != nil {
+			seeded[*category.TemplateKey] = category.ID
+		}
+	}
+
+	created := []models.Category{}
+	for _, item := range categoryTemplate(CategoryLocale(locale)).Categories {
+		parentID, ok := seeded[item.Key]
+		if !ok {
+			category, err := s.repo.CreateDefault(workspaceID, userID, item.Key, templateCategoryRequest(item, nil))
+			if err != nil {
+				return created, fmt.Errorf("failed to create default category: %v", err)
+			}
+			parentID = category.ID
+			created = append(created, *category)
+		}
+
+		for _, child := range item.Children {
+			if _, ok := seeded[child.Key]; ok {
+				continue
+			}
+			category, err := s.repo.CreateDefault(workspaceID, userID, child.Key, templateCategoryRequest(child, &parentID))
+			if err != nil {
+				return created, fmt.Errorf("failed to create default category: %v", err)
+			}
+			created = append(created, *category)
+		}
+	}
+
+	return created, nil
+}
+
+// ResetToDefaults deletes the custom categories of a workspace and restores missing default ones
+// Categories seeded from the template are kept, even when renamed. Nothing is deleted while a custom
+// category is still used by transactions.
+func (s *CategoryService) ResetToDefaults(workspaceID, actorID, locale string) (*models.CategoryResetResult, error) {
+	categories, err := s.repo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var custom []models.Category
+	var inUse []string
+	for _, category := range categories {
+		if category.IsDefault || category.TemplateKey != nil {
+			continue
+		}
+		custom = append(custom, category)
+
+		count, err := s.transactionRepo.CountByCategoryID(category.ID, workspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check category usage: %v", err)
+		}
+		if count > 0 {
+			inUse = append(inUse, category.Name)
+		}
+	}
+	if len(inUse) > 0 {
+		return nil, fmt.Errorf("cannot reset categories while these are used in transactions: %s", strings.Join(inUse, ", "))
+	}
+
+	deletedCount, err := s.repo.DeleteCustom(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range custom {
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityCategory,
+			EntityID:    custom[i].ID,
+			Action:      models.AuditActionDelete,
+			ActorID:     actorID,
+		}, &custom[i], nil)
+	}
+
+	created, err := s.SeedDefaultCategories(workspaceID, actorID, locale)
+	s.recordCreated(workspaceID, actorID, created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CategoryResetResult{
+		DeletedCount: deletedCount,
+		Created:      created,
+	}, nil
+}
+
+// ImportTemplate adds the categories of a locale's template as custom categories
+// Template categories the workspace already has, by name and type under the same parent, are skipped.
+func (s *CategoryService) ImportTemplate(workspaceID, userID, locale string) (*models.CategoryImportResult, error) {
+	if !utils.Contains(categoryTemplateLocales, locale) {
+		return nil, fmt.Errorf("unsupported template locale")
+	}
+
+	existing, err := s.repo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]string)
+	for _, category := range existing {
+		byName[templateMatchKey(category.Name, category.Type, category.ParentID)] = category.ID
+	}
+
+	result := &models.CategoryImportResult{Locale: locale, Created: []models.Category{}}
+	importItem := func(item models.CategoryTemplateItem, parentID *string) (string, error) {
+		if id, ok := byName[templateMatchKey(item.Name, item.Type, parentID)]; ok {
+			result.SkippedCount++
+			return id, nil
+		}
+		category, err := s.repo.Create(workspaceID, userID, templateCategoryRequest(item, parentID))
+		if err != nil {
+			return "", fmt.Errorf("failed to create category: %v", err)
+		}
+		result.Created = append(result.Created, *category)
+		return category.ID, nil
+	}
+
+	for _, item := range categoryTemplate(locale).Categories {
+		parentID, err := importItem(item, nil)
+		if err != nil {
+			s.recordCreated(workspaceID, userID, result.Created)
+			return nil, err
+		}
+		for _, child := range item.Children {
+			if _, err := importItem(child, &parentID); err != nil {
+				s.recordCreated(workspaceID, userID, result.Created)
+				return nil, err
+			}
+		}
+	}
+
+	s.recordCreated(workspaceID, userID, result.Created)
+	return result, nil
+}
+
+// recordCreated records the creation of categories in the audit log
+func (s *CategoryService) recordCreated(workspaceID, actorID string, categories []models.Category) {
+	for i := range categories {
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityCategory,
+			EntityID:    categories[i].ID,
+			Action:      models.AuditActionCreate,
+			ActorID:     actorID,
+		}, nil, &categories[i])
+	}
+}
+
+// templateCategoryRequest builds the request creating a template category
+func templateCategoryRequest(item models.CategoryTemplateItem, parentID *string) models.CreateCategoryRequest {
+	icon := item.Icon
+	color := item.Color
+	return models.CreateCategoryRequest{
+		ParentID: parentID,
+		Name:     item.Name,
+		Type:     item.Type,
+		Icon:     &icon,
+		Color:    &color,
+	}
+}
+
+// templateMatchKey identifies a category by name and type under its parent when importing a template
+func templateMatchKey(name, categoryType string, parentID *string) string {
+	parent := ""
+	if parentID != nil {
+		parent = *parentID
+	}
+	return strings.ToLower(strings.TrimSpace(name)) + "|" + categoryType + "|" + parent
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"strings"
+)
+
+// defaultCategory is an entry of the default category template
+// Names are given per locale so every locale shares the same keys, and a workspace seeded in one
+// locale is not seeded again in another.
+type defaultCategory struct {
+	Key      string
+	Type     string
+	Icon     string
+	Color    string
+	Names    map[string]string
+	Children []defaultCategory
+}
+
+// categoryTemplateLocales lists the locales the default categories are translated to
+var categoryTemplateLocales = []string{"vi", "en"}
+
+// defaultCategories are the categories every personal workspace starts with
+// Sub-categories take the type and color of their parent.
+var defaultCategories = []defaultCategory{
+	{Key: "salary", Type: "income", Icon: "💰", Color: "#10B981", Names: map[string]string{"vi": "Lương", "en": "Salary"}},
+	{Key: "bonus", Type: "income", Icon: "🎁", Color: "#F59E0B", Names: map[string]string{"vi": "Thưởng", "en": "Bonus"}},
+	{Key: "investment", Type: "income", Icon: "📈", Color: "#3B82F6", Names: map[string]string{"vi": "Đầu tư", "en": "Investment"}},
+	{Key: "other_income", Type: "income", Icon: "💵", Color: "#6B7280", Names: map[string]string{"vi": "Thu nhập khác", "en": "Other income"}},
+	{Key: "food", Type: "expense", Icon: "🍜", Color: "#EF4444", Names: map[string]string{"vi": "Ăn uống", "en": "Food & drink"}, Children: []defaultCategory{
+		{Key: "food.breakfast", Icon: "🍳", Names: map[string]string{"vi": "Ăn sáng", "en": "Breakfast"}},
+		{Key: "food.lunch", Icon: "🍱", Names: map[string]string{"vi": "Ăn trưa", "en": "Lunch"}},
+		{Key: "food.dinner", Icon: "🍲", Names: map[string]string{"vi": "Ăn tối", "en": "Dinner"}},
+		{Key: "food.coffee", Icon: "☕", Names: map[string]string{"vi": "Cà phê", "en": "Coffee"}},
+	}},
+	{Key: "transport", Type: "expense", Icon: "🚗", Color: "#3B82F6", Names: map[string]string{"vi": "Di chuyển", "en": "Transport"}, Children: []defaultCategory{
+		{Key: "transport.fuel", Icon: "⛽", Names: map[string]string{"vi": "Xăng xe", "en": "Fuel"}},
+		{Key: "transport.parking", Icon: "🅿️", Names: map[string]string{"vi": "Đỗ xe", "en": "Parking"}},
+		{Key: "transport.taxi", Icon: "🚕", Names: map[string]string{"vi": "Taxi", "en": "Taxi"}},
+	}},
+	{Key: "shopping", Type: "expense", Icon: "🛍️", Color: "#EC4899", Names: map[string]string{"vi": "Mua sắm", "en": "Shopping"}, Children: []defaultCategory{
+		{Key: "shopping.clothes", Icon: "👕", Names: map[string]string{"vi": "Quần áo", "en": "Clothes"}},
+		{Key: "shopping.beauty", Icon: "💄", Names: map[string]string{"vi": "Làm đẹp", "en": "Beauty"}},
+	}},
+	{Key: "housing", Type: "expense", Icon: "🏠", Color: "#F59E0B", Names: map[string]string{"vi": "Nhà cửa", "en": "Housing"}, Children: []defaultCategory{
+		{Key: "housing.rent", Icon: "🛏️", Names: map[string]string{"vi": "Tiền nhà", "en": "Rent"}},
+		{Key: "housing.utilities", Icon: "💡", Names: map[string]string{"vi": "Điện nước", "en": "Utilities"}},
+		{Key: "housing.furniture", Icon: "🛋️", Names: map[string]string{"vi": "Nội thất", "en": "Furniture"}},
+	}},
+	{Key: "entertainment", Type: "expense", Icon: "🎮", Color: "#8B5CF6", Names: map[string]string{"vi": "Giải trí", "en": "Entertainment"}, Children: []defaultCategory{
+		{Key: "entertainment.movies", Icon: "🎬", Names: map[string]string{"vi": "Phim ảnh", "en": "Movies"}},
+	}},
+	{Key: "health", Type: "expense", Icon: "⚕️", Color: "#10B981", Names: map[string]string{"vi": "Sức khỏe", "en": "Health"}, Children: []defaultCategory{
+		{Key: "health.medicine", Icon: "💊", Names: map[string]string{"vi": "Thuốc", "en": "Medicine"}},
+		{Key: "health.doctor", Icon: "🏥", Names: map[string]string{"vi": "Khám bệnh", "en": "Doctor visits"}},
+	}},
+	{Key: "education", Type: "expense", Icon: "📚", Color: "#EAB308", Names: map[string]string{"vi": "Giáo dục", "en": "Education"}, Children: []defaultCategory{
+		{Key: "education.books", Icon: "📖", Names: map[string]string{"vi": "Sách", "en": "Books"}},
+		{Key: "education.courses", Icon: "🎓", Names: map[string]string{"vi": "Khóa học", "en": "Courses"}},
+	}},
+	{Key: "other_expense", Type: "expense", Icon: "💸", Color: "#6B7280", Names: map[string]string{"vi": "Chi phí khác", "en": "Other expenses"}},
+}
+
+// CategoryLocale maps a language tag such as "en-US" to a locale the default categories are translated to
+func CategoryLocale(locale string) string {
+	language := strings.ToLower(strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)[0])
+	for _, supported := range categoryTemplateLocales {
+		if language == supported {
+			return supported
+		}
+	}
+	return models.DefaultLocale
+}
+
+// categoryTemplate returns the default categories translated to a locale
+func categoryTemplate(locale string) models.CategoryTemplate {
+	return models.CategoryTemplate{
+		Locale:     locale,
+		Categories: templateItems(defaultCategories, locale, "", ""),
+	}
+}
+
+// templateItems translates template entries, filling in the type and color sub-categories inherit
+func templateItems(entries []defaultCategory, locale, parentType, parentColor string) []models.CategoryTemplateItem {
+	items := make([]models.CategoryTemplateItem, 0, len(entries))
+	for _, entry := range entries {
+		item := models.CategoryTemplateItem{
+			Key:   entry.Key,
+			Name:  entry.Names[locale],
+			Type:  entry.Type,
+			Icon:  entry.Icon,
+			Color: entry.Color,
+		}
+		if item.Type == "" {
+			item.Type = parentType
+		}
+		if item.Color == "" {
+			item.Color = parentColor
+		}
+		if len(entry.Children) > 0 {
+			item.Children = templateItems(entry.Children, locale, item.Type, item.Color)
+		}
+		items = append(items, item)
+	}
+	return items
+}
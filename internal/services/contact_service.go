@@ -0,0 +1,160 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"fmt"
+)
+
+// ContactService handles business logic for contacts and contact groups
+type ContactService struct {
+	repo              *repositories.ContactRepository
+	sharedExpenseRepo *repositories.SharedExpenseRepository
+}
+
+// NewContactService creates a new contact service
+func NewContactService(repo *repositories.ContactRepository, sharedExpenseRepo *repositories.SharedExpenseRepository) *ContactService {
+	return &ContactService{
+		repo:              repo,
+		sharedExpenseRepo: sharedExpenseRepo,
+	}
+}
+
+// CreateContact creates a new contact
+func (s *ContactService) CreateContact(workspaceID, userID string, req models.CreateContactRequest) (*models.Contact, error) {
+	return s.repo.Create(workspaceID, userID, req)
+}
+
+// GetContact retrieves a contact by ID
+func (s *ContactService) GetContact(id, workspaceID string) (*models.Contact, error) {
+	contact, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if contact == nil {
+		return nil, fmt.Errorf("contact not found")
+	}
+	return contact, nil
+}
+
+// GetAllContacts retrieves all contacts of a workspace
+func (s *ContactService) GetAllContacts(workspaceID string) ([]models.Contact, error) {
+	return s.repo.GetAll(workspaceID)
+}
+
+// UpdateContact updates a contact
+func (s *ContactService) UpdateContact(id, workspaceID string, req models.UpdateContactRequest) (*models.Contact, error) {
+	existing, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("contact not found")
+	}
+
+	return s.repo.Update(id, workspaceID, req)
+}
+
+// DeleteContact deletes a contact that has no shared expense history
+func (s *ContactService) DeleteContact(id, workspaceID string) error {
+	existing, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("contact not found")
+	}
+
+	expenses, err := s.sharedExpenseRepo.GetAll(workspaceID, id, "")
+	if err != nil {
+		return fmt.Errorf("failed to check contact usage: %v", err)
+	}
+	settlements, err := s.sharedExpenseRepo.GetSettlements(workspaceID, id, "")
+	if err != nil {
+		return fmt.Errorf("failed to check contact usage: %v", err)
+	}
+	if len(expenses) > 0 || len(settlements) > 0 {
+		return fmt.Errorf("cannot delete contact with shared expense history")
+	}
+
+	return s.repo.Delete(id, workspaceID)
+}
+
+// CreateGroup creates a new contact group
+func (s *ContactService) CreateGroup(workspaceID, userID string, req models.CreateContactGroupRequest) (*models.ContactGroup, error) {
+	if err := s.validateContactIDs(workspaceID, req.ContactIDs); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateGroup(workspaceID, userID, req)
+}
+
+// GetGroup retrieves a contact group by ID
+func (s *ContactService) GetGroup(id, workspaceID string) (*models.ContactGroup, error) {
+	group, err := s.repo.GetGroupByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("group not found")
+	}
+	return group, nil
+}
+
+// GetAllGroups retrieves all contact groups of a workspace
+func (s *ContactService) GetAllGroups(workspaceID string) ([]models.ContactGroup, error) {
+	return s.repo.GetAllGroups(workspaceID)
+}
+
+// UpdateGroup updates a contact group
+func (s *ContactService) UpdateGroup(id, workspaceID string, req models.UpdateContactGroupRequest) (*models.ContactGroup, error) {
+	existing, err := s.repo.GetGroupByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("group not found")
+	}
+
+	if req.ContactIDs != nil {
+		if err := s.validateContactIDs(workspaceID, req.ContactIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.UpdateGroup(id, workspaceID, req)
+}
+
+// DeleteGroup deletes a contact group, keeping its expenses as ungrouped history
+func (s *ContactService) DeleteGroup(id, workspaceID string) error {
+	existing, err := s.repo.GetGroupByID(id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("group not found")
+	}
+
+	return s.repo.DeleteGroup(id, workspaceID)
+}
+
+// validateContactIDs makes sure every ID is unique and belongs to one of the workspace's contacts
+func (s *ContactService) validateContactIDs(workspaceID string, contactIDs []string) error {
+	seen := make(map[string]bool)
+	for _, id := range contactIDs {
+		if seen[id] {
+			return fmt.Errorf("duplicate contact in group")
+		}
+		seen[id] = true
+	}
+
+	count, err := s.repo.CountByIDs(workspaceID, contactIDs)
+	if err != nil {
+		return err
+	}
+	if count != len(contactIDs) {
+		return fmt.Errorf("contact not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// savingLookbackMonths is how many past months of overview data feed the completion projection
+const savingLookbackMonths = 3
+
+// averageMonthHours is the mean length of a Gregorian month in hours
+const averageMonthHours = 365.2425 / 12 * 24
+
+// maxProjectionMonths is how far ahead a completion date is projected; slower savings give no projected date
+const maxProjectionMonths = 100 * 12
+
+// GoalService handles business logic for savings goals
+type GoalService struct {
+	repo               *repositories.GoalRepository
+	accountRepo        *repositories.AccountRepository
+	transactionService *TransactionService
+	reportService      *ReportService
+}
+
+// NewGoalService creates a new goal service
+func NewGoalService(
+	repo *repositories.GoalRepository,
+	accountRepo *repositories.AccountRepository,
+	transactionService *TransactionService,
+	reportService *ReportService,
+) *GoalService {
+	return &GoalService{
+		repo:               repo,
+		accountRepo:        accountRepo,
+		transactionService: transactionService,
+		reportService:      reportService,
+	}
+}
+
+// CreateGoal creates a new savings goal
+func (s *GoalService) CreateGoal(workspaceID, userID string, req models.CreateGoalRequest) (*models.Goal, error) {
+	if req.TargetDate != nil && req.TargetDate.Before(time.Now()) {
+		return nil, fmt.Errorf("target date must be in the future")
+	}
+
+	// Verify linked account belongs to the workspace
+	if req.AccountID != nil && *req.AccountID != "" {
+		account, err := s.accountRepo.GetByID(*req.AccountID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if account == nil {
+			return nil, fmt.Errorf("account not found")
+		}
+	} else {
+		// No account means the goal is a virtual envelope
+		req.AccountID = nil
+	}
+
+	if req.Icon == nil {
+		icon := "🎯"
+		req.Icon = &icon
+	}
+
+	return s.repo.Create(workspaceID, userID, req)
+}
+
+// GetGoal retrieves a goal with its computed progress
+func (s *GoalService) GetGoal(id, workspaceID string) (*models.GoalProgress, error) {
+	goal, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if goal == nil {
+		return nil, fmt.Errorf("goal not found")
+	}
+
+	return s.buildProgress(workspaceID, goal)
+}
+
+// GetAllGoals retrieves all goals of a workspace with their computed progress
+func (s *GoalService) GetAllGoals(workspaceID, status string) ([]models.GoalProgress, error) {
+	goals, err := s.repo.GetAll(workspaceID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.GoalProgress, 0, len(goals))
+	for i := range goals {
+		progress, err := s.buildProgress(workspaceID, &goals[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *progress)
+	}
+
+	return result, nil
+}
+
+// UpdateGoal updates a goal
+func (s *GoalService) UpdateGoal(id, workspaceID string, req models.UpdateGoalRequest) (*models.Goal, error) {
+	existing, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("goal not found")
+	}
+
+	if req.TargetDate != nil && req.TargetDate.Before(time.Now()) {
+		return nil, fmt.Errorf("target date must be in the future")
+	}
+
+	return s.repo.Update(id, workspaceID, req)
+}
+
+// DeleteGoal deletes a goal and its contribution history
+// Transfers already made to a linked account are kept as regular transactions
+func (s *GoalService) DeleteGoal(id, workspaceID string) error {
+	existing, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("goal not found")
+	}
+
+	return s.repo.Delete(id, workspaceID)
+}
+
+// AddContribution records a contribution toward a goal
+// Transfer contributions move money from a source account into the goal's linked account,
+// allocations only earmark money already saved elsewhere
+func (s *GoalService) AddContribution(goalID, workspaceID, userID string, req models.CreateGoalContributionRequest) (*models.GoalContribution, error) {
+	goal, err := s.repo.GetByID(goalID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if goal == nil {
+		return nil, fmt.Errorf("goal not found")
+	}
+	if goal.Status == "archived" {
+		return nil, fmt.Errorf("cannot contribute to an archived goal")
+	}
+
+	method := req.Method
+	if method == "" {
+		method = "allocation"
+		if req.FromAccountID != nil && *req.FromAccountID != "" {
+			method = "transfer"
+		}
+	}
+
+	contributedAt := time.Now()
+	if req.ContributedAt != nil {
+		contributedAt = *req.ContributedAt
+	}
+
+	contribution := &models.GoalContribution{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		GoalID:        goalID,
+		Method:        method,
+		Amount:        req.Amount,
+		Notes:         req.Notes,
+		ContributedAt: contributedAt,
+	}
+
+	if method == "transfer" {
+		if goal.AccountID == nil {
+			return nil, fmt.Errorf("transfer contributions require a goal linked to an account")
+		}
+		if req.FromAccountID == nil || *req.FromAccountID == "" {
+			return nil, fmt.Errorf("from_account_id is required for transfer contributions")
+		}
+
+		description := fmt.Sprintf("Goal contribution: %s", goal.Name)
+		transaction, err := s.transactionService.CreateTransaction(workspaceID, userID, models.CreateTransactionRequest{
+			AccountID:       *req.FromAccountID,
+			ToAccountID:     goal.AccountID,
+			Type:            "transfer",
+			Amount:          req.Amount,
+			Description:     &description,
+			TransactionDate: contributedAt,
+			Notes:           req.Notes,
+			LinkedTo:        &models.TransactionLink{Type: models.LinkTypeGoalContribution, ID: contribution.ID},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		contribution.FromAccountID = req.FromAccountID
+		contribution.TransactionID = &transaction.ID
+	}
+
+	if err := s.repo.CreateContribution(contribution); err != nil {
+		// Undo the transfer so balances stay consistent with the goal
+		if contribution.TransactionID != nil {
+			_ = s.transactionService.ReverseTransaction(*contribution.TransactionID, workspaceID, userID)
+		}
+		return nil, err
+	}
+
+	// Mark the goal completed once the target is reached
+	if goal.Status == "active" {
+		saved, err := s.savedAmount(goalID)
+		if err == nil && saved >= goal.TargetAmount {
+			status := "completed"
+			_, _ = s.repo.Update(goalID, workspaceID, models.UpdateGoalRequest{Status: &status})
+		}
+	}
+
+	return contribution, nil
+}
+
+// GetContributions retrieves the contribution history of a goal
+func (s *GoalService) GetContributions(goalID, workspaceID string) ([]models.GoalContribution, error) {
+	goal, err := s.repo.GetByID(goalID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if goal == nil {
+		return nil, fmt.Errorf("goal not found")
+	}
+
+	return s.repo.GetContributions(goalID)
+}
+
+// DeleteContribution removes a contribution and reverts its transfer, if any
+func (s *GoalService) DeleteContribution(goalID, contributionID, workspaceID, userID string) error {
+	goal, err := s.repo.GetByID(goalID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if goal == nil {
+		return fmt.Errorf("goal not found")
+	}
+
+	contribution, err := s.repo.GetContributionByID(contributionID, goalID)
+	if err != nil {
+		return err
+	}
+	if contribution == nil {
+		return fmt.Errorf("contribution not found")
+	}
+
+	if contribution.TransactionID != nil {
+		if err := s.transactionService.ReverseTransaction(*contribution.TransactionID, workspaceID, userID); err != nil {
+			return fmt.Errorf("failed to revert contribution transfer: %v", err)
+		}
+	}
+
+	if err := s.repo.DeleteContribution(contributionID, goalID); err != nil {
+		return err
+	}
+
+	// Reopen a completed goal that falls back below its target
+	if goal.Status == "completed" {
+		saved, err := s.savedAmount(goalID)
+		if err == nil && saved < goal.TargetAmount {
+			status := "active"
+			_, _ = s.repo.Update(goalID, workspaceID, models.UpdateGoalRequest{Status: &status})
+		}
+	}
+
+	return nil
+}
+
+// savedAmount sums all contributions of a goal
+func (s *GoalService) savedAmount(goalID string) (float64, error) {
+	contributions, err := s.repo.GetContributions(goalID)
+	if err != nil {
+		return 0, err
+	}
+
+	var saved float64
+	for _, contribution := range contributions {
+		saved += contribution.Amount
+	}
+	return saved, nil
+}
+
+// buildProgress computes progress, required contribution and projected completion for a goal
+func (s *GoalService) buildProgress(workspaceID string, goal *models.Goal) (*models.GoalProgress, error) {
+	contributions, err := s.repo.GetContributions(goal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	progress := &models.GoalProgress{
+		Goal:              *goal,
+		ContributionCount: len(contributions),
+	}
+
+	// Contributions are sorted newest first, so the oldest is last
+	var firstContribution time.Time
+	for i, contribution := range contributions {
+		progress.SavedAmount += contribution.Amount
+		if i == len(contributions)-1 {
+			firstContribution = contribution.ContributedAt
+		}
+	}
+
+	progress.RemainingAmount = math.Max(goal.TargetAmount-progress.SavedAmount, 0)
+	if goal.TargetAmount > 0 {
+		progress.Percentage = math.Min(progress.SavedAmount/goal.TargetAmount*100, 100)
+	}
+
+	// Average monthly contribution over the life of the goal
+	if len(contributions) > 0 {
+		progress.AvgMonthlyContribution = progress.SavedAmount / math.Max(monthsBetween(firstContribution, now), 1)
+	}
+
+	// Required monthly contribution to hit the target date
+	if goal.TargetDate != nil && progress.RemainingAmount > 0 {
+		required := progress.RemainingAmount / math.Max(monthsBetween(now, *goal.TargetDate), 1)
+		progress.RequiredMonthlyContribution = &required
+	}
+
+	// Average monthly saving from the overview report of the last few months
+	overview, err := s.reportService.GetOverview(workspaceID, now.AddDate(0, -savingLookbackMonths, 0), now)
+	if err == nil {
+		progress.AvgMonthlySaving = overview.NetSaving / savingLookbackMonths
+	}
+
+	// Project the completion date
+	if progress.RemainingAmount == 0 {
+		if len(contributions) > 0 {
+			completedAt := contributions[0].ContributedAt
+			progress.ProjectedCompletionDate = &completedAt
+		}
+	} else if progress.AvgMonthlySaving > 0 {
+		// Whole months are added by calendar so a far-off date cannot overflow a time.Duration
+		monthsNeeded := progress.RemainingAmount / progress.AvgMonthlySaving
+		if monthsNeeded <= maxProjectionMonths {
+			wholeMonths := math.Floor(monthsNeeded)
+			projected := now.AddDate(0, int(wholeMonths), 0).Add(time.Duration((monthsNeeded - wholeMonths) * averageMonthHours * float64(time.Hour)))
+			progress.ProjectedCompletionDate = &projected
+		} else if goal.TargetDate != nil {
+			onTrack := false
+			progress.OnTrack = &onTrack
+		}
+	}
+
+	if goal.TargetDate != nil && progress.ProjectedCompletionDate != nil {
+		onTrack := !progress.ProjectedCompletionDate.After(*goal.TargetDate)
+		progress.OnTrack = &onTrack
+	}
+
+	return progress, nil
+}
+
+// monthsBetween returns the fractional number of months between two dates
+func monthsBetween(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / averageMonthHours
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"time"
+)
+
+// InstallmentService handles business logic for credit card installment plans
+type InstallmentService struct {
+	repo               *repositories.InstallmentRepository
+	accountRepo        *repositories.AccountRepository
+	categoryRepo       *repositories.CategoryRepository
+	transactionService *TransactionService
+}
+
+// NewInstallmentService creates a new installment service
+func NewInstallmentService(
+	repo *repositories.InstallmentRepository,
+	accountRepo *repositories.AccountRepository,
+	categoryRepo *repositories.CategoryRepository,
+	transactionService *TransactionService,
+) *InstallmentService {
+	return &InstallmentService{
+		repo:               repo,
+		accountRepo:        accountRepo,
+		categoryRepo:       categoryRepo,
+		transactionService: transactionService,
+	}
+}
+
+// CreatePlan records an installment purchase on a credit card account
+// The purchase itself is not booked; each installment is posted as an expense on its statement date,
+// and installments whose statement date has already passed are posted right away.
+func (s *InstallmentService) CreatePlan(accountID, workspaceID, userID string, req models.CreateInstallmentPlanRequest) (*models.InstallmentPlanSummary, error) {
+	account, err := s.getCreditAccount(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepo.GetByID(req.CategoryID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, fmt.Errorf("category not found")
+	}
+	if category.Type != "expense" && category.Type != "both" {
+		return nil, fmt.Errorf("category type does not match transaction type")
+	}
+
+	loc := utils.VietnamLocation()
+	purchaseDate := time.Now()
+	if req.PurchaseDate != nil {
+		purchaseDate = *req.PurchaseDate
+	}
+	purchaseDate = purchaseDate.In(loc)
+
+	statementDay := *account.StatementDate
+	purchaseMonth := time.Date(purchaseDate.Year(), purchaseDate.Month(), 1, 0, 0, 0, 0, loc)
+
+	var startMonth time.Time
+	if req.StartMonth != "" {
+		startMonth, err = time.ParseInLocation("2006-01", req.StartMonth, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start month format, use YYYY-MM")
+		}
+		if startMonth.Before(purchaseMonth) {
+			return nil, fmt.Errorf("start month cannot be before the purchase month")
+		}
+	} else {
+		// Purchases after the statement closing date fall on the next statement
+		startMonth = purchaseMonth
+		if purchaseDate.Day() > statementDay {
+			startMonth = startMonth.AddDate(0, 1, 0)
+		}
+	}
+
+	plan := &models.InstallmentPlan{
+		WorkspaceID:  workspaceID,
+		UserID:       userID,
+		AccountID:    accountID,
+		CategoryID:   req.CategoryID,
+		Description:  req.Description,
+		Merchant:     req.Merchant,
+		PurchaseDate: purchaseDate,
+		Principal:    req.Principal,
+		Installments: req.Installments,
+		InterestRate: req.InterestRate,
+		Fee:          req.Fee,
+		StartMonth:   startMonth.Format("2006-01"),
+		Status:       models.InstallmentStatusActive,
+		Schedule:     buildInstallmentSchedule(req.Principal, req.InterestRate, req.Fee, req.Installments, startMonth, statementDay, account.DueDate),
+	}
+
+	if err := s.repo.Create(plan); err != nil {
+		return nil, err
+	}
+
+	if err := s.postDueCharges(plan, time.Now(), models.AuditSourceAPI); err != nil {
+		fmt.Printf("Warning: failed to post due installments of plan %s: %v\n", plan.ID, err)
+	}
+
+	return buildInstallmentSummary(plan), nil
+}
+
+// GetPlans retrieves the installment plans of a credit card account
+func (s *InstallmentService) GetPlans(accountID, workspaceID string) ([]models.InstallmentPlanSummary, error) {
+	if _, err := s.getCreditAccount(accountID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	plans, err := s.repo.GetByAccountID(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.InstallmentPlanSummary, len(plans))
+	for i := range plans {
+		summaries[i] = *buildInstallmentSummary(&plans[i])
+	}
+
+	return summaries, nil
+}
+
+// GetPlan retrieves an installment plan with its remaining installments and outstanding principal
+func (s *InstallmentService) GetPlan(id, accountID, workspaceID string) (*models.InstallmentPlanSummary, error) {
+	plan, err := s.getPlan(id, accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildInstallmentSummary(plan), nil
+}
+
+// PayOff settles the rest of an installment plan early
+// The outstanding principal, any fee not billed yet and the early settlement fee are posted as one expense;
+// interest of the remaining installments is not charged.
+func (s *InstallmentService) PayOff(id, accountID, workspaceID, userID string, req models.PayoffInstallmentPlanRequest) (*models.InstallmentPlanSummary, error) {
+	plan, err := s.getPlan(id, accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.InstallmentStatusActive {
+		return nil, fmt.Errorf("installment plan is already paid off")
+	}
+
+	payoffDate := time.Now()
+	if req.PayoffDate != nil {
+		payoffDate = *req.PayoffDate
+	}
+	if payoffDate.Before(plan.PurchaseDate) {
+		return nil, fmt.Errorf("payoff date cannot be before the purchase date")
+	}
+
+	// Installments billed up to the payoff date are charged as scheduled
+	if err := s.postDueCharges(plan, payoffDate, models.AuditSourceAPI); err != nil {
+		return nil, err
+	}
+	if plan.Status != models.InstallmentStatusActive {
+		return buildInstallmentSummary(plan), nil
+	}
+
+	amount := req.Fee
+	for _, charge := range plan.Schedule {
+		if charge.TransactionID == nil && !charge.SettledEarly {
+			amount += charge.Principal + charge.Fee
+		}
+	}
+	amount = utils.RoundToTwoDecimals(amount)
+
+	description := fmt.Sprintf("Installment payoff: %s", plan.Description)
+	transaction, err := s.transactionService.CreateTransaction(workspaceID, userID, models.CreateTransactionRequest{
+		AccountID:       plan.AccountID,
+		CategoryID:      &plan.CategoryID,
+		Type:            "expense",
+		Amount:          amount,
+		Merchant:        plan.Merchant,
+		Description:     &description,
+		TransactionDate: payoffDate,
+		Status:          models.TransactionStatusCleared,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range plan.Schedule {
+		if plan.Schedule[i].TransactionID == nil {
+			plan.Schedule[i].SettledEarly = true
+		}
+	}
+	plan.Status = models.InstallmentStatusPaidOff
+	plan.PayoffTransactionID = &transaction.ID
+	plan.PaidOffAt = &payoffDate
+
+	if err := s.repo.Update(plan); err != nil {
+		if revertErr := s.transactionService.ReverseTransaction(transaction.ID, workspaceID, userID); revertErr != nil {
+			fmt.Printf("Warning: failed to revert payoff transaction %s: %v\n", transaction.ID, revertErr)
+		}
+		return nil, err
+	}
+
+	return buildInstallmentSummary(plan), nil
+}
+
+// DeletePlan deletes an installment plan and moves the transactions it posted to the trash
+func (s *InstallmentService) DeletePlan(id, accountID, workspaceID, userID string) error {
+	plan, err := s.getPlan(id, accountID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	var transactionIDs []string
+	for _, charge := range plan.Schedule {
+		if charge.TransactionID != nil {
+			transactionIDs = append(transactionIDs, *charge.TransactionID)
+		}
+	}
+	if plan.PayoffTransactionID != nil {
+		transactionIDs = append(transactionIDs, *plan.PayoffTransactionID)
+	}
+
+	for _, transactionID := range transactionIDs {
+		if err := s.transactionService.DeleteTransaction(transactionID, workspaceID, userID); err != nil {
+			fmt.Printf("Warning: failed to delete installment transaction %s: %v\n", transactionID, err)
+		}
+	}
+
+	return s.repo.Delete(id, workspaceID)
+}
+
+// PostDueCharges posts every installment whose statement date has passed
+func (s *InstallmentService) PostDueCharges() (int, error) {
+	now := time.Now()
+	plans, err := s.repo.GetDue(now)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for i := range plans {
+		before := countPostedCharges(&plans[i])
+		if err := s.postDueCharges(&plans[i], now, models.AuditSourceRecurring); err != nil {
+			fmt.Printf("Warning: failed to post installments of plan %s: %v\n", plans[i].ID, err)
+		}
+		posted += countPostedCharges(&plans[i]) - before
+	}
+
+	return posted, nil
+}
+
+// RunInstallmentCharges posts due installments now and then on every interval
+// It blocks, so callers run it in its own goroutine
+func (s *InstallmentService) RunInstallmentCharges(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.PostDueCharges(); err != nil {
+			fmt.Printf("Warning: failed to post installment charges: %v\n", err)
+		} else if count > 0 {
+			fmt.Printf("Posted %d installment charges\n", count)
+		}
+		<-ticker.C
+	}
+}
+
+// postDueCharges posts the unbilled installments of a plan due on or before the given time and saves the plan
+// Charges posted before a failure are kept, so the next run continues where this one stopped. When the plan
+// was changed by another run in the meantime, the charges posted here are reversed, as that run has posted them too.
+func (s *InstallmentService) postDueCharges(plan *models.InstallmentPlan, until time.Time, source string) error {
+	var postErr error
+	var posted []int
+
+	for i := range plan.Schedule {
+		charge := &plan.Schedule[i]
+		if charge.TransactionID != nil || charge.SettledEarly {
+			continue
+		}
+		if charge.ChargeDate.After(until) {
+			break
+		}
+
+		description := fmt.Sprintf("Installment %d/%d: %s", charge.Period, plan.Installments, plan.Description)
+		transaction, err := s.transactionService.CreateTransaction(plan.WorkspaceID, plan.UserID, models.CreateTransactionRequest{
+			AccountID:       plan.AccountID,
+			CategoryID:      &plan.CategoryID,
+			Type:            "expense",
+			Amount:          charge.Amount,
+			Merchant:        plan.Merchant,
+			Description:     &description,
+			TransactionDate: charge.ChargeDate,
+			Status:          models.TransactionStatusCleared,
+			Source:          source,
+		})
+		if err != nil {
+			postErr = err
+			break
+		}
+
+		charge.TransactionID = &transaction.ID
+		posted = append(posted, i)
+	}
+
+	if len(posted) == 0 {
+		return postErr
+	}
+
+	status, paidOffAt := plan.Status, plan.PaidOffAt
+	if countPostedCharges(plan) == len(plan.Schedule) {
+		lastCharge := plan.Schedule[len(plan.Schedule)-1].ChargeDate
+		plan.Status = models.InstallmentStatusPaidOff
+		plan.PaidOffAt = &lastCharge
+	}
+
+	if err := s.repo.Update(plan); err != nil {
+		for _, i := range posted {
+			transactionID := *plan.Schedule[i].TransactionID
+			if revertErr := s.transactionService.ReverseTransaction(transactionID, plan.WorkspaceID, plan.UserID); revertErr != nil {
+				fmt.Printf("Warning: failed to revert installment transaction %s: %v\n", transactionID, revertErr)
+			}
+			plan.Schedule[i].TransactionID = nil
+		}
+		plan.Status, plan.PaidOffAt = status, paidOffAt
+		return err
+	}
+
+	return postErr
+}
+
+// getCreditAccount retrieves an account and checks that installments can be billed to it
+func (s *InstallmentService) getCreditAccount(accountID, workspaceID string) (*models.Account, error) {
+	account, err := s.accountRepo.GetByID(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found")
+	}
+	if account.Type != "credit" {
+		return nil, fmt.Errorf("installment plans are only available for credit card accounts")
+	}
+	if account.StatementDate == nil {
+		return nil, fmt.Errorf("account has no statement date set")
+	}
+
+	return account, nil
+}
+
+// getPlan retrieves an installment plan of an account
+func (s *InstallmentService) getPlan(id, accountID, workspaceID string) (*models.InstallmentPlan, error) {
+	plan, err := s.repo.GetByID(id, accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("installment plan not found")
+	}
+
+	return plan, nil
+}
+
+// buildInstallmentSchedule splits a purchase into monthly charges billed on the statement closing date
+// Principal is split evenly with the last installment absorbing rounding, interest is flat on the original
+// principal and the conversion fee is billed with the first installment.
+func buildInstallmentSchedule(principal, interestRate, fee float64, installments int, startMonth time.Time, statementDay int, dueDay *int) []models.InstallmentCharge {
+	principalPart := utils.RoundToTwoDecimals(principal / float64(installments))
+	interest := utils.RoundToTwoDecimals(principal * interestRate / 100 / 12)
+
+	schedule := make([]models.InstallmentCharge, 0, installments)
+	remaining := principal
+	for period := 1; period <= installments; period++ {
+		month := startMonth.AddDate(0, period-1, 0)
+		chargeDate := dayInMonth(month, statementDay)
+
+		charge := models.InstallmentCharge{
+			Period:     period,
+			ChargeDate: chargeDate,
+			DueDate:    chargeDate.AddDate(0, 0, 15),
+			Principal:  principalPart,
+			Interest:   interest,
+		}
+		if dueDay != nil {
+			// A due day on or before the closing day falls in the following month
+			dueMonth := month
+			if *dueDay <= statementDay {
+				dueMonth = month.AddDate(0, 1, 0)
+			}
+			charge.DueDate = dayInMonth(dueMonth, *dueDay)
+		}
+		if period == installments {
+			charge.Principal = utils.RoundToTwoDecimals(remaining)
+		}
+		if period == 1 {
+			charge.Fee = fee
+		}
+		charge.Amount = utils.RoundToTwoDecimals(charge.Principal + charge.Interest + charge.Fee)
+
+		remaining -= charge.Principal
+		schedule = append(schedule, charge)
+	}
+
+	return schedule
+}
+
+// dayInMonth returns the given day of a month, capped at the month's last day
+func dayInMonth(month time.Time, day int) time.Time {
+	lastDay := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, month.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, month.Location())
+}
+
+// countPostedCharges counts the installments of a plan that have been posted or settled early
+func countPostedCharges(plan *models.InstallmentPlan) int {
+	count := 0
+	for _, charge := range plan.Schedule {
+		if charge.TransactionID != nil || charge.SettledEarly {
+			count++
+		}
+	}
+	return count
+}
+
+// buildInstallmentSummary computes what is left to pay on an installment plan
+func buildInstallmentSummary(plan *models.InstallmentPlan) *models.InstallmentPlanSummary {
+	summary := &models.InstallmentPlanSummary{InstallmentPlan: *plan}
+
+	for i := range plan.Schedule {
+		charge := plan.Schedule[i]
+		if charge.TransactionID != nil || charge.SettledEarly {
+			continue
+		}
+		summary.RemainingInstallments++
+		summary.OutstandingPrincipal += charge.Principal
+		summary.RemainingAmount += charge.Amount
+		if summary.NextCharge == nil {
+			summary.NextCharge = &charge
+		}
+	}
+	summary.OutstandingPrincipal = utils.RoundToTwoDecimals(summary.OutstandingPrincipal)
+	summary.RemainingAmount = utils.RoundToTwoDecimals(summary.RemainingAmount)
+
+	return summary
+}
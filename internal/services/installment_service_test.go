@@ -0,0 +1,92 @@
+package services
+
+import (
+	"finance-hub-api/internal/utils"
+	"testing"
+	"time"
+)
+
+func TestBuildInstallmentSchedule(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	intPtr := func(value int) *int { return &value }
+
+	type charge struct {
+		principal, interest, fee, amount float64
+		chargeDate, dueDate              time.Time
+	}
+
+	tests := []struct {
+		name         string
+		principal    float64
+		interestRate float64
+		fee          float64
+		installments int
+		startMonth   time.Time
+		statementDay int
+		dueDay       *int
+		want         []charge
+	}{
+		{
+			name:      "last installment absorbs the rounding remainder",
+			principal: 10000000, installments: 3, startMonth: date(2025, time.January, 1), statementDay: 20,
+			want: []charge{
+				{principal: 3333333.33, amount: 3333333.33, chargeDate: date(2025, time.January, 20), dueDate: date(2025, time.February, 4)},
+				{principal: 3333333.33, amount: 3333333.33, chargeDate: date(2025, time.February, 20), dueDate: date(2025, time.March, 7)},
+				{principal: 3333333.34, amount: 3333333.34, chargeDate: date(2025, time.March, 20), dueDate: date(2025, time.April, 4)},
+			},
+		},
+		{
+			name:      "last installment can also be smaller",
+			principal: 100, installments: 7, startMonth: date(2025, time.January, 1), statementDay: 10, dueDay: intPtr(25),
+			want: []charge{
+				{principal: 14.29, amount: 14.29, chargeDate: date(2025, time.January, 10), dueDate: date(2025, time.January, 25)},
+				{principal: 14.29, amount: 14.29, chargeDate: date(2025, time.February, 10), dueDate: date(2025, time.February, 25)},
+				{principal: 14.29, amount: 14.29, chargeDate: date(2025, time.March, 10), dueDate: date(2025, time.March, 25)},
+				{principal: 14.29, amount: 14.29, chargeDate: date(2025, time.April, 10), dueDate: date(2025, time.April, 25)},
+				{principal: 14.29, amount: 14.29, chargeDate: date(2025, time.May, 10), dueDate: date(2025, time.May, 25)},
+				{principal: 14.29, amount: 14.29, chargeDate: date(2025, time.June, 10), dueDate: date(2025, time.June, 25)},
+				{principal: 14.26, amount: 14.26, chargeDate: date(2025, time.July, 10), dueDate: date(2025, time.July, 25)},
+			},
+		},
+		{
+			name:      "flat interest on every period and the fee on the first",
+			principal: 1000, interestRate: 12, fee: 50, installments: 3, startMonth: date(2025, time.January, 1), statementDay: 31, dueDay: intPtr(5),
+			want: []charge{
+				{principal: 333.33, interest: 10, fee: 50, amount: 393.33, chargeDate: date(2025, time.January, 31), dueDate: date(2025, time.February, 5)},
+				{principal: 333.33, interest: 10, amount: 343.33, chargeDate: date(2025, time.February, 28), dueDate: date(2025, time.March, 5)},
+				{principal: 333.34, interest: 10, amount: 343.34, chargeDate: date(2025, time.March, 31), dueDate: date(2025, time.April, 5)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := buildInstallmentSchedule(tt.principal, tt.interestRate, tt.fee, tt.installments, tt.startMonth, tt.statementDay, tt.dueDay)
+			if len(schedule) != len(tt.want) {
+				t.Fatalf("got %d charges, want %d", len(schedule), len(tt.want))
+			}
+
+			var totalPrincipal float64
+			for i, want := range tt.want {
+				got := schedule[i]
+				if got.Period != i+1 {
+					t.Errorf("charge %d: period = %d", i+1, got.Period)
+				}
+				if got.Principal != want.principal || got.Interest != want.interest || got.Fee != want.fee || got.Amount != want.amount {
+					t.Errorf("charge %d: principal %v interest %v fee %v amount %v, want %v %v %v %v",
+						i+1, got.Principal, got.Interest, got.Fee, got.Amount, want.principal, want.interest, want.fee, want.amount)
+				}
+				if !got.ChargeDate.Equal(want.chargeDate) || !got.DueDate.Equal(want.dueDate) {
+					t.Errorf("charge %d: charged %v due %v, want %v due %v", i+1, got.ChargeDate, got.DueDate, want.chargeDate, want.dueDate)
+				}
+				totalPrincipal += got.Principal
+			}
+
+			if utils.RoundToTwoDecimals(totalPrincipal) != tt.principal {
+				t.Errorf("principal adds up to %v, want %v", totalPrincipal, tt.principal)
+			}
+		})
+	}
+}
@@ -0,0 +1,642 @@
+package services
+
+import (
+	"encoding/csv"
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// quantityTolerance absorbs floating point noise when matching fractional fund units against lots
+const quantityTolerance = 1e-9
+
+// priceHistoryLimit caps the number of prices returned for a security
+const priceHistoryLimit = 365
+
+// priceDateLayouts lists the date formats accepted in price CSV files
+var priceDateLayouts = []string{"2006-01-02", "02/01/2006", "2/1/2006", time.RFC3339}
+
+// InvestmentService handles business logic for securities, prices and investment accounts
+type InvestmentService struct {
+	repo               *repositories.InvestmentRepository
+	securityRepo       *repositories.SecurityRepository
+	accountRepo        *repositories.AccountRepository
+	transactionService *TransactionService
+}
+
+// NewInvestmentService creates a new investment service
+func NewInvestmentService(
+	repo *repositories.InvestmentRepository,
+	securityRepo *repositories.SecurityRepository,
+	accountRepo *repositories.AccountRepository,
+	transactionService *TransactionService,
+) *InvestmentService {
+	return &InvestmentService{
+		repo:               repo,
+		securityRepo:       securityRepo,
+		accountRepo:        accountRepo,
+		transactionService: transactionService,
+	}
+}
+
+// positionState tracks the open lots and results of one security while replaying transactions
+type positionState struct {
+	lots         []models.InvestmentLot
+	realizedGain float64
+	dividends    float64
+}
+
+// CreateSecurity creates a security, optionally with an initial price
+func (s *InvestmentService) CreateSecurity(workspaceID, userID string, req models.CreateSecurityRequest) (*models.Security, error) {
+	existing, err := s.securityRepo.GetBySymbol(req.Symbol, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("security with symbol %s already exists", existing.Symbol)
+	}
+
+	security, err := s.securityRepo.Create(workspaceID, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Price != nil {
+		return s.UpdatePrice(security.ID, workspaceID, models.UpdateSecurityPriceRequest{
+			Price:     *req.Price,
+			PriceDate: req.PriceDate,
+		})
+	}
+
+	return security, nil
+}
+
+// GetSecurities retrieves all securities in a workspace
+func (s *InvestmentService) GetSecurities(workspaceID string) ([]models.Security, error) {
+	return s.securityRepo.GetAll(workspaceID)
+}
+
+// GetSecurity retrieves a security by ID
+func (s *InvestmentService) GetSecurity(id, workspaceID string) (*models.Security, error) {
+	security, err := s.securityRepo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if security == nil {
+		return nil, fmt.Errorf("security not found")
+	}
+	return security, nil
+}
+
+// UpdateSecurity updates a security
+func (s *InvestmentService) UpdateSecurity(id, workspaceID string, req models.UpdateSecurityRequest) (*models.Security, error) {
+	security, err := s.securityRepo.Update(id, workspaceID, req)
+	if err != nil {
+		return nil, err
+	}
+	if security == nil {
+		return nil, fmt.Errorf("security not found")
+	}
+	return security, nil
+}
+
+// DeleteSecurity deletes a security that no investment transaction references
+func (s *InvestmentService) DeleteSecurity(id, workspaceID string) error {
+	if _, err := s.GetSecurity(id, workspaceID); err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountBySecurity(id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("cannot delete security with %d investment transactions", count)
+	}
+
+	return s.securityRepo.Delete(id, workspaceID)
+}
+
+// UpdatePrice records a manual price for a security
+func (s *InvestmentService) UpdatePrice(id, workspaceID string, req models.UpdateSecurityPriceRequest) (*models.Security, error) {
+	if _, err := s.GetSecurity(id, workspaceID); err != nil {
+		return nil, err
+	}
+
+	priceDate := time.Now()
+	if req.PriceDate != nil {
+		priceDate = *req.PriceDate
+	}
+
+	if err := s.securityRepo.SavePrice(id, workspaceID, req.Price, priceDate, "manual"); err != nil {
+		return nil, err
+	}
+
+	return s.GetSecurity(id, workspaceID)
+}
+
+// GetPrices retrieves the price history of a security
+func (s *InvestmentService) GetPrices(id, workspaceID string) ([]models.SecurityPrice, error) {
+	if _, err := s.GetSecurity(id, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return s.securityRepo.GetPrices(id, workspaceID, priceHistoryLimit)
+}
+
+// ImportPrices imports prices from a CSV file with symbol, price and optional date columns
+// A header row is detected and skipped; rows for unknown symbols are reported instead of failing the import
+func (s *InvestmentService) ImportPrices(workspaceID string, file io.Reader) (*models.PriceImportResult, error) {
+	securities, err := s.securityRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	bySymbol := make(map[string]models.Security, len(securities))
+	for _, security := range securities {
+		bySymbol[security.Symbol] = security
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	result := &models.PriceImportResult{Errors: []models.PriceImportError{}}
+	now := time.Now()
+	line := 0
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV at line %d: %v", line, err)
+		}
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+
+		fail := func(symbol, message string) {
+			result.Skipped++
+			result.Errors = append(result.Errors, models.PriceImportError{Line: line, Symbol: symbol, Message: message})
+		}
+
+		if len(record) < 2 {
+			fail("", "expected symbol and price columns")
+			continue
+		}
+
+		symbol := strings.ToUpper(strings.TrimSpace(record[0]))
+		price, err := parsePrice(record[1])
+		if err != nil {
+			if line == 1 {
+				// Header row
+				continue
+			}
+			fail(symbol, "invalid price")
+			continue
+		}
+
+		priceDate := now
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			priceDate, err = parsePriceDate(record[2])
+			if err != nil {
+				fail(symbol, "invalid date, expected YYYY-MM-DD or DD/MM/YYYY")
+				continue
+			}
+		}
+
+		security, ok := bySymbol[symbol]
+		if !ok {
+			fail(symbol, "unknown symbol")
+			continue
+		}
+
+		if err := s.securityRepo.SavePrice(security.ID, workspaceID, price, priceDate, "import"); err != nil {
+			fail(symbol, err.Error())
+			continue
+		}
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+// RecordTransaction records a buy, sell, dividend or fee in an investment account
+// The cash side of every trade is booked as a transaction on the account: buys and fees as expenses,
+// sales and dividends as income
+func (s *InvestmentService) RecordTransaction(accountID, workspaceID, userID string, req models.CreateInvestmentTransactionRequest) (*models.InvestmentTransaction, error) {
+	if _, err := s.getInvestmentAccount(accountID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	tradeDate := time.Now()
+	if req.TradeDate != nil {
+		tradeDate = *req.TradeDate
+	}
+
+	transaction := &models.InvestmentTransaction{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		AccountID:   accountID,
+		SecurityID:  req.SecurityID,
+		Type:        req.Type,
+		TradeDate:   tradeDate,
+		Notes:       req.Notes,
+	}
+
+	var security *models.Security
+	var err error
+	if req.SecurityID != nil && *req.SecurityID != "" {
+		security, err = s.GetSecurity(*req.SecurityID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		transaction.SecurityID = nil
+	}
+
+	if req.Type != "buy" && req.Type != "sell" && req.Type != "dividend" && req.Type != "fee" {
+		return nil, fmt.Errorf("invalid investment transaction type")
+	}
+	if req.CategoryID == nil || *req.CategoryID == "" {
+		return nil, fmt.Errorf("category_id is required for %s transactions", req.Type)
+	}
+
+	var transactionType, description string
+	switch req.Type {
+	case "buy", "sell":
+		if security == nil {
+			return nil, fmt.Errorf("security_id is required for %s transactions", req.Type)
+		}
+		if req.Quantity == nil || req.Price == nil {
+			return nil, fmt.Errorf("quantity and price are required for %s transactions", req.Type)
+		}
+		transaction.Quantity = *req.Quantity
+		transaction.Price = *req.Price
+		if req.Fee != nil {
+			transaction.Fee = *req.Fee
+		}
+
+		gross := transaction.Quantity * transaction.Price
+		if req.Type == "buy" {
+			transaction.Amount = utils.RoundToTwoDecimals(gross + transaction.Fee)
+			transactionType = "expense"
+			description = fmt.Sprintf("Buy: %s", security.Symbol)
+		} else {
+			transaction.Amount = utils.RoundToTwoDecimals(gross - transaction.Fee)
+			if transaction.Amount <= 0 {
+				return nil, fmt.Errorf("fee must be less than the sale proceeds")
+			}
+			transactionType = "income"
+			description = fmt.Sprintf("Sell: %s", security.Symbol)
+		}
+
+		// Replay the account history with the new trade to make sure no sale exceeds the holding
+		existing, err := s.repo.GetByAccountID(accountID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		transaction.CreatedAt = time.Now()
+		if _, _, err := replayLots(append(existing, *transaction)); err != nil {
+			return nil, err
+		}
+
+	case "dividend", "fee":
+		if req.Type == "dividend" && security == nil {
+			return nil, fmt.Errorf("security_id is required for dividend transactions")
+		}
+		if req.Amount == nil {
+			return nil, fmt.Errorf("amount is required for %s transactions", req.Type)
+		}
+		transaction.Amount = *req.Amount
+
+		transactionType = "income"
+		description = "Investment fee"
+		if req.Type == "dividend" {
+			description = fmt.Sprintf("Dividend: %s", security.Symbol)
+		} else {
+			transactionType = "expense"
+			if security != nil {
+				description = fmt.Sprintf("Investment fee: %s", security.Symbol)
+			}
+		}
+	}
+
+	cashTransaction, err := s.transactionService.CreateTransaction(workspaceID, userID, models.CreateTransactionRequest{
+		AccountID:       accountID,
+		CategoryID:      req.CategoryID,
+		Type:            transactionType,
+		Amount:          transaction.Amount,
+		Description:     &description,
+		TransactionDate: tradeDate,
+		Notes:           req.Notes,
+		LinkedTo:        &models.TransactionLink{Type: models.LinkTypeInvestmentTransaction, ID: transaction.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	transaction.TransactionID = &cashTransaction.ID
+
+	if err := s.repo.Create(transaction); err != nil {
+		if revertErr := s.transactionService.ReverseTransaction(cashTransaction.ID, workspaceID, userID); revertErr != nil {
+			fmt.Printf("Warning: failed to revert investment transaction %s: %v\n", cashTransaction.ID, revertErr)
+		}
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// GetTransactions retrieves the investment transactions of an account
+func (s *InvestmentService) GetTransactions(accountID, workspaceID string) ([]models.InvestmentTransaction, error) {
+	if _, err := s.getInvestmentAccount(accountID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByAccountID(accountID, workspaceID)
+}
+
+// DeleteTransaction removes an investment transaction and reverts its cash effect
+func (s *InvestmentService) DeleteTransaction(accountID, id, workspaceID, userID string) error {
+	transaction, err := s.repo.GetByID(id, accountID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if transaction == nil {
+		return fmt.Errorf("investment transaction not found")
+	}
+
+	switch transaction.Type {
+	case "buy", "sell":
+		if transaction.Type == "buy" {
+			// Later sales may have consumed this lot
+			existing, err := s.repo.GetByAccountID(accountID, workspaceID)
+			if err != nil {
+				return err
+			}
+			remaining := make([]models.InvestmentTransaction, 0, len(existing))
+			for _, t := range existing {
+				if t.ID != id {
+					remaining = append(remaining, t)
+				}
+			}
+			if _, _, err := replayLots(remaining); err != nil {
+				return fmt.Errorf("cannot delete a purchase that later sales depend on")
+			}
+		}
+
+		// Trades recorded before their cash side was booked as a transaction moved the balance directly
+		if transaction.TransactionID == nil {
+			delta := transaction.Amount
+			if transaction.Type == "sell" {
+				delta = -delta
+			}
+			if err := s.accountRepo.UpdateBalance(accountID, workspaceID, delta); err != nil {
+				return fmt.Errorf("failed to update account balance: %v", err)
+			}
+		}
+	}
+
+	if transaction.TransactionID != nil {
+		if err := s.transactionService.ReverseTransaction(*transaction.TransactionID, workspaceID, userID); err != nil {
+			return fmt.Errorf("failed to revert the cash transaction of the trade: %v", err)
+		}
+	}
+
+	return s.repo.Delete(id, accountID, workspaceID)
+}
+
+// GetPortfolio returns the holdings, lots and gains of an investment account
+func (s *InvestmentService) GetPortfolio(accountID, workspaceID string) (*models.Portfolio, error) {
+	account, err := s.getInvestmentAccount(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.repo.GetByAccountID(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	securities, err := s.securityRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildPortfolio(account, transactions, securities)
+}
+
+// getInvestmentAccount retrieves an account and makes sure it is an investment account
+func (s *InvestmentService) getInvestmentAccount(accountID, workspaceID string) (*models.Account, error) {
+	account, err := s.accountRepo.GetByID(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found")
+	}
+	if account.Type != "investment" {
+		return nil, fmt.Errorf("account is not an investment account")
+	}
+	return account, nil
+}
+
+// buildPortfolio values the holdings of an account at the last known security prices
+func buildPortfolio(account *models.Account, transactions []models.InvestmentTransaction, securities []models.Security) (*models.Portfolio, error) {
+	positions, fees, err := replayLots(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio := &models.Portfolio{
+		AccountID:   account.ID,
+		CashBalance: account.Balance,
+		Fees:        fees,
+		Holdings:    []models.Holding{},
+	}
+
+	for _, security := range securities {
+		position, ok := positions[security.ID]
+		if !ok {
+			continue
+		}
+
+		holding := models.Holding{
+			Security:     security,
+			Price:        security.LastPrice,
+			PriceDate:    security.LastPriceDate,
+			RealizedGain: utils.RoundToTwoDecimals(position.realizedGain),
+			Dividends:    utils.RoundToTwoDecimals(position.dividends),
+			Lots:         position.lots,
+		}
+		for _, lot := range position.lots {
+			holding.Quantity += lot.Quantity
+			holding.CostBasis += lot.CostBasis
+		}
+		if holding.Lots == nil {
+			holding.Lots = []models.InvestmentLot{}
+		}
+
+		// Unpriced securities are carried at cost so they still count towards net worth
+		holding.MarketValue = holding.CostBasis
+		if security.LastPrice != nil {
+			holding.MarketValue = holding.Quantity * *security.LastPrice
+		}
+		holding.CostBasis = utils.RoundToTwoDecimals(holding.CostBasis)
+		holding.MarketValue = utils.RoundToTwoDecimals(holding.MarketValue)
+		holding.UnrealizedGain = utils.RoundToTwoDecimals(holding.MarketValue - holding.CostBasis)
+		if holding.Quantity > quantityTolerance {
+			holding.AverageCost = utils.RoundToTwoDecimals(holding.CostBasis / holding.Quantity)
+		}
+		if holding.CostBasis > 0 {
+			holding.UnrealizedGainPercent = utils.RoundToTwoDecimals(holding.UnrealizedGain / holding.CostBasis * 100)
+		}
+
+		portfolio.MarketValue += holding.MarketValue
+		portfolio.CostBasis += holding.CostBasis
+		portfolio.UnrealizedGain += holding.UnrealizedGain
+		portfolio.RealizedGain += holding.RealizedGain
+		portfolio.Dividends += holding.Dividends
+		portfolio.Holdings = append(portfolio.Holdings, holding)
+	}
+
+	portfolio.MarketValue = utils.RoundToTwoDecimals(portfolio.MarketValue)
+	portfolio.CostBasis = utils.RoundToTwoDecimals(portfolio.CostBasis)
+	portfolio.UnrealizedGain = utils.RoundToTwoDecimals(portfolio.UnrealizedGain)
+	portfolio.RealizedGain = utils.RoundToTwoDecimals(portfolio.RealizedGain)
+	portfolio.Dividends = utils.RoundToTwoDecimals(portfolio.Dividends)
+	portfolio.TotalValue = utils.RoundToTwoDecimals(portfolio.CashBalance + portfolio.MarketValue)
+
+	return portfolio, nil
+}
+
+// portfolioMarketValues returns the market value of the holdings of every investment account, keyed by account ID
+func portfolioMarketValues(accounts []models.Account, transactions []models.InvestmentTransaction, securities []models.Security) map[string]float64 {
+	byAccount := make(map[string][]models.InvestmentTransaction)
+	for _, transaction := range transactions {
+		byAccount[transaction.AccountID] = append(byAccount[transaction.AccountID], transaction)
+	}
+
+	values := make(map[string]float64)
+	for i := range accounts {
+		account := &accounts[i]
+		if account.Type != "investment" {
+			continue
+		}
+		portfolio, err := buildPortfolio(account, byAccount[account.ID], securities)
+		if err != nil {
+			fmt.Printf("Warning: failed to value investment account %s: %v\n", account.ID, err)
+			continue
+		}
+		values[account.ID] = portfolio.MarketValue
+	}
+
+	return values
+}
+
+// replayLots replays investment transactions in trade order, matching sales against the oldest lots first
+// It returns the positions keyed by security ID and the total fees paid
+func replayLots(transactions []models.InvestmentTransaction) (map[string]*positionState, float64, error) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].TradeDate.Equal(transactions[j].TradeDate) {
+			return transactions[i].TradeDate.Before(transactions[j].TradeDate)
+		}
+		return transactions[i].CreatedAt.Before(transactions[j].CreatedAt)
+	})
+
+	positions := make(map[string]*positionState)
+	fees := 0.0
+
+	for _, transaction := range transactions {
+		if transaction.Type == "fee" {
+			fees += transaction.Amount
+			continue
+		}
+		if transaction.SecurityID == nil {
+			continue
+		}
+
+		position, ok := positions[*transaction.SecurityID]
+		if !ok {
+			position = &positionState{}
+			positions[*transaction.SecurityID] = position
+		}
+
+		switch transaction.Type {
+		case "buy":
+			fees += transaction.Fee
+			position.lots = append(position.lots, models.InvestmentLot{
+				TransactionID: transaction.ID,
+				TradeDate:     transaction.TradeDate,
+				Quantity:      transaction.Quantity,
+				UnitCost:      transaction.Amount / transaction.Quantity,
+				CostBasis:     transaction.Amount,
+			})
+
+		case "sell":
+			fees += transaction.Fee
+			remaining := transaction.Quantity
+			cost := 0.0
+			for remaining > quantityTolerance && len(position.lots) > 0 {
+				lot := &position.lots[0]
+				taken := math.Min(lot.Quantity, remaining)
+				cost += taken * lot.UnitCost
+				remaining -= taken
+				lot.Quantity -= taken
+				lot.CostBasis = lot.Quantity * lot.UnitCost
+				if lot.Quantity <= quantityTolerance {
+					position.lots = position.lots[1:]
+				}
+			}
+			if remaining > quantityTolerance {
+				return nil, 0, fmt.Errorf("sell quantity exceeds the holding on %s", transaction.TradeDate.Format("2006-01-02"))
+			}
+			position.realizedGain += transaction.Amount - cost
+
+		case "dividend":
+			position.dividends += transaction.Amount
+		}
+	}
+
+	for _, position := range positions {
+		for i := range position.lots {
+			position.lots[i].UnitCost = utils.RoundToTwoDecimals(position.lots[i].UnitCost)
+			position.lots[i].CostBasis = utils.RoundToTwoDecimals(position.lots[i].CostBasis)
+		}
+	}
+
+	return positions, utils.RoundToTwoDecimals(fees), nil
+}
+
+// parsePrice parses a CSV price, ignoring thousand separators and spaces
+func parsePrice(value string) (float64, error) {
+	cleaned := strings.NewReplacer(",", "", " ", "").Replace(strings.TrimSpace(value))
+	price, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || price <= 0 {
+		return 0, fmt.Errorf("invalid price")
+	}
+	return price, nil
+}
+
+// parsePriceDate parses a CSV price date in one of the accepted layouts
+func parsePriceDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range priceDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date")
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxAmortizationPeriods caps payoff simulations so a payment that barely covers interest cannot loop forever
+const maxAmortizationPeriods = 1200
+
+// LoanService handles business logic for loan and debt accounts
+type LoanService struct {
+	repo               *repositories.LoanRepository
+	accountRepo        *repositories.AccountRepository
+	transactionService *TransactionService
+}
+
+// NewLoanService creates a new loan service
+func NewLoanService(
+	repo *repositories.LoanRepository,
+	accountRepo *repositories.AccountRepository,
+	transactionService *TransactionService,
+) *LoanService {
+	return &LoanService{
+		repo:               repo,
+		accountRepo:        accountRepo,
+		transactionService: transactionService,
+	}
+}
+
+// GetSchedule returns the amortization schedule and current status of a loan account
+func (s *LoanService) GetSchedule(accountID, workspaceID string) (*models.LoanSchedule, error) {
+	account, err := s.getLoanAccount(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.repo.GetPaymentsByAccountID(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	frequency := *account.PaymentFrequency
+	rate := periodicRate(*account.InterestRate, frequency)
+	schedule, periodicPayment := buildAmortizationSchedule(*account.Principal, rate, *account.TermPeriods, frequency, *account.LoanStartDate)
+
+	result := &models.LoanSchedule{
+		AccountID:        account.ID,
+		Direction:        *account.LoanDirection,
+		Principal:        *account.Principal,
+		InterestRate:     *account.InterestRate,
+		TermPeriods:      *account.TermPeriods,
+		PaymentFrequency: frequency,
+		PeriodicPayment:  periodicPayment,
+		RemainingBalance: outstandingBalance(account),
+		PaymentsMade:     len(payments),
+		Schedule:         schedule,
+	}
+
+	for _, entry := range schedule {
+		result.TotalInterest += entry.Interest
+	}
+	result.TotalInterest = utils.RoundToTwoDecimals(result.TotalInterest)
+
+	for _, payment := range payments {
+		result.PrincipalPaid += payment.Principal
+		result.InterestPaid += payment.Interest
+	}
+
+	if result.RemainingBalance > 0 {
+		next := addPeriods(*account.LoanStartDate, frequency, len(payments)+1)
+		result.NextPaymentDate = &next
+
+		// Project the payoff date from the current balance at the scheduled payment
+		balance := result.RemainingBalance
+		for period := 0; period < maxAmortizationPeriods; period++ {
+			balance -= periodicPayment - balance*rate
+			if balance <= 0 {
+				payoff := addPeriods(next, frequency, period)
+				result.PayoffDate = &payoff
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// RecordPayment records a loan payment as a principal transfer plus an interest transaction
+func (s *LoanService) RecordPayment(accountID, workspaceID, userID string, req models.CreateLoanPaymentRequest) (*models.LoanPayment, error) {
+	account, err := s.getLoanAccount(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if req.PaymentAccountID == accountID {
+		return nil, fmt.Errorf("payment account must be different from the loan account")
+	}
+
+	remaining := outstandingBalance(account)
+	if remaining <= 0 {
+		return nil, fmt.Errorf("loan is already paid off")
+	}
+
+	frequency := *account.PaymentFrequency
+	rate := periodicRate(*account.InterestRate, frequency)
+	interest := utils.RoundToTwoDecimals(remaining * rate)
+
+	// Default to the scheduled payment, never more than what is owed
+	amount := 0.0
+	if req.Amount != nil {
+		amount = *req.Amount
+	} else {
+		_, amount = buildAmortizationSchedule(*account.Principal, rate, *account.TermPeriods, frequency, *account.LoanStartDate)
+		amount = math.Min(amount, remaining+interest)
+	}
+
+	if amount > remaining+interest {
+		return nil, fmt.Errorf("payment exceeds outstanding balance plus interest")
+	}
+	if amount < interest {
+		interest = amount
+	}
+	principal := utils.RoundToTwoDecimals(amount - interest)
+
+	paymentDate := time.Now()
+	if req.PaymentDate != nil {
+		paymentDate = *req.PaymentDate
+	}
+
+	borrowed := *account.LoanDirection == "borrowed"
+	payment := &models.LoanPayment{
+		ID:               uuid.New().String(),
+		WorkspaceID:      workspaceID,
+		UserID:           userID,
+		AccountID:        accountID,
+		PaymentAccountID: req.PaymentAccountID,
+		Amount:           amount,
+		Principal:        principal,
+		Interest:         interest,
+		RemainingBalance: utils.RoundToTwoDecimals(remaining - principal),
+		PaymentDate:      paymentDate,
+	}
+
+	// Interest is an expense when borrowing and income when lending
+	if interest > 0 {
+		if req.CategoryID == nil || *req.CategoryID == "" {
+			return nil, fmt.Errorf("category_id is required for the interest portion of the payment")
+		}
+
+		interestType := "income"
+		if borrowed {
+			interestType = "expense"
+		}
+		description := fmt.Sprintf("Loan interest: %s", account.Name)
+		transaction, err := s.transactionService.CreateTransaction(workspaceID, userID, models.CreateTransactionRequest{
+			AccountID:       req.PaymentAccountID,
+			CategoryID:      req.CategoryID,
+			Type:            interestType,
+			Amount:          interest,
+			Description:     &description,
+			TransactionDate: paymentDate,
+			Notes:           req.Notes,
+			LinkedTo:        &models.TransactionLink{Type: models.LinkTypeLoanPayment, ID: payment.ID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		payment.InterestTransactionID = &transaction.ID
+	}
+
+	// Principal moves between the payment account and the loan account
+	if principal > 0 {
+		fromAccountID, toAccountID := accountID, req.PaymentAccountID
+		if borrowed {
+			fromAccountID, toAccountID = req.PaymentAccountID, accountID
+		}
+		description := fmt.Sprintf("Loan principal: %s", account.Name)
+		transaction, err := s.transactionService.CreateTransaction(workspaceID, userID, models.CreateTransactionRequest{
+			AccountID:       fromAccountID,
+			ToAccountID:     &toAccountID,
+			Type:            "transfer",
+			Amount:          principal,
+			Description:     &description,
+			TransactionDate: paymentDate,
+			Notes:           req.Notes,
+			LinkedTo:        &models.TransactionLink{Type: models.LinkTypeLoanPayment, ID: payment.ID},
+		})
+		if err != nil {
+			if revertErr := s.revertPaymentTransactions(workspaceID, userID, payment); revertErr != nil {
+				fmt.Printf("Warning: %v\n", revertErr)
+			}
+			return nil, err
+		}
+		payment.PrincipalTransactionID = &transaction.ID
+	}
+
+	if err := s.repo.CreatePayment(payment); err != nil {
+		if revertErr := s.revertPaymentTransactions(workspaceID, userID, payment); revertErr != nil {
+			fmt.Printf("Warning: %v\n", revertErr)
+		}
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// GetPayments retrieves the payment history of a loan account
+func (s *LoanService) GetPayments(accountID, workspaceID string) ([]models.LoanPayment, error) {
+	if _, err := s.getLoanAccount(accountID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetPaymentsByAccountID(accountID, workspaceID)
+}
+
+// DeletePayment removes a loan payment and reverts its transactions
+// Only the latest payment can be removed, as every later payment stores the balance left after it.
+func (s *LoanService) DeletePayment(accountID, paymentID, workspaceID, userID string) error {
+	payment, err := s.repo.GetPaymentByID(paymentID, accountID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if payment == nil {
+		return fmt.Errorf("loan payment not found")
+	}
+
+	payments, err := s.repo.GetPaymentsByAccountID(accountID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if len(payments) > 0 && payments[len(payments)-1].ID != paymentID {
+		return fmt.Errorf("only the latest loan payment can be deleted; delete the later payments first")
+	}
+
+	if err := s.revertPaymentTransactions(workspaceID, userID, payment); err != nil {
+		// Keep track of the transactions that were reverted before the failure
+		if updateErr := s.repo.UpdatePaymentTransactions(payment); updateErr != nil {
+			fmt.Printf("Warning: failed to update loan payment %s: %v\n", payment.ID, updateErr)
+		}
+		return err
+	}
+
+	return s.repo.DeletePayment(paymentID, accountID, workspaceID)
+}
+
+// getLoanAccount retrieves an account and makes sure it is a loan with complete terms
+func (s *LoanService) getLoanAccount(accountID, workspaceID string) (*models.Account, error) {
+	account, err := s.accountRepo.GetByID(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found")
+	}
+	if account.Type != "loan" || account.LoanDirection == nil || account.Principal == nil ||
+		account.InterestRate == nil || account.TermPeriods == nil ||
+		account.PaymentFrequency == nil || account.LoanStartDate == nil {
+		return nil, fmt.Errorf("account is not a loan account")
+	}
+	return account, nil
+}
+
+// revertPaymentTransactions deletes the transactions created for a loan payment
+// Reverted transactions are cleared from the payment, so on failure it lists only those still in place.
+func (s *LoanService) revertPaymentTransactions(workspaceID, userID string, payment *models.LoanPayment) error {
+	if payment.PrincipalTransactionID != nil {
+		if err := s.transactionService.ReverseTransaction(*payment.PrincipalTransactionID, workspaceID, userID); err != nil {
+			return fmt.Errorf("failed to revert loan principal transaction %s: %v", *payment.PrincipalTransactionID, err)
+		}
+		payment.PrincipalTransactionID = nil
+	}
+	if payment.InterestTransactionID != nil {
+		if err := s.transactionService.ReverseTransaction(*payment.InterestTransactionID, workspaceID, userID); err != nil {
+			return fmt.Errorf("failed to revert loan interest transaction %s: %v", *payment.InterestTransactionID, err)
+		}
+		payment.InterestTransactionID = nil
+	}
+	return nil
+}
+
+// outstandingBalance returns the principal still owed on a loan account
+func outstandingBalance(account *models.Account) float64 {
+	if *account.LoanDirection == "borrowed" {
+		return math.Max(-account.Balance, 0)
+	}
+	return math.Max(account.Balance, 0)
+}
+
+// periodicRate converts an annual percentage rate to the rate per payment period
+func periodicRate(annualRate float64, frequency string) float64 {
+	periodsPerYear := map[string]float64{
+		"weekly":    52,
+		"biweekly":  26,
+		"monthly":   12,
+		"quarterly": 4,
+	}
+	perYear, ok := periodsPerYear[frequency]
+	if !ok {
+		perYear = 12
+	}
+	return annualRate / 100 / perYear
+}
+
+// addPeriods returns the date n payment periods after start
+func addPeriods(start time.Time, frequency string, n int) time.Time {
+	switch frequency {
+	case "weekly":
+		return start.AddDate(0, 0, 7*n)
+	case "biweekly":
+		return start.AddDate(0, 0, 14*n)
+	case "quarterly":
+		return start.AddDate(0, 3*n, 0)
+	default:
+		return start.AddDate(0, n, 0)
+	}
+}
+
+// buildAmortizationSchedule builds a fixed-payment amortization schedule and returns it with the periodic payment
+func buildAmortizationSchedule(principal, rate float64, periods int, frequency string, start time.Time) ([]models.AmortizationEntry, float64) {
+	payment := principal / float64(periods)
+	if rate > 0 {
+		payment = principal * rate / (1 - math.Pow(1+rate, -float64(periods)))
+	}
+	payment = utils.RoundToTwoDecimals(payment)
+
+	schedule := make([]models.AmortizationEntry, 0, periods)
+	balance := principal
+	for period := 1; period <= periods; period++ {
+		interest := utils.RoundToTwoDecimals(balance * rate)
+		principalPart := payment - interest
+
+		// The last payment absorbs rounding differences
+		if period == periods || principalPart > balance {
+			principalPart = balance
+		}
+		balance = utils.RoundToTwoDecimals(balance - principalPart)
+
+		schedule = append(schedule, models.AmortizationEntry{
+			Period:           period,
+			DueDate:          addPeriods(start, frequency, period),
+			Payment:          utils.RoundToTwoDecimals(principalPart + interest),
+			Principal:        utils.RoundToTwoDecimals(principalPart),
+			Interest:         interest,
+			RemainingBalance: balance,
+		})
+	}
+
+	return schedule, payment
+}
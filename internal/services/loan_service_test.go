@@ -0,0 +1,98 @@
+package services
+
+import (
+	"finance-hub-api/internal/config"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestDeletePaymentTransactionCannotBeRestored(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("reversed loan payment", func(mt *mtest.T) {
+		const (
+			workspaceID   = "workspace-1"
+			userID        = "user-1"
+			loanID        = "loan-account"
+			checkingID    = "checking-account"
+			paymentID     = "payment-1"
+			transactionID = "principal-transaction"
+		)
+
+		transactionRepo := repositories.NewTransactionRepository(mt.DB)
+		accountRepo := repositories.NewAccountRepository(mt.DB)
+		attachmentService := NewAttachmentService(repositories.NewAttachmentRepository(mt.DB), transactionRepo, utils.NewMemoryStorage(nil), &config.Config{})
+		auditService := NewAuditService(repositories.NewAuditRepository(mt.DB))
+		transactionService := NewTransactionService(transactionRepo, accountRepo, repositories.NewCategoryRepository(mt.DB), repositories.NewPayeeRepository(mt.DB), auditService, attachmentService)
+		loanService := NewLoanService(repositories.NewLoanRepository(mt.DB), accountRepo, transactionService)
+
+		now := time.Now()
+		payment := bson.D{
+			{Key: "_id", Value: paymentID},
+			{Key: "workspace_id", Value: workspaceID},
+			{Key: "user_id", Value: userID},
+			{Key: "account_id", Value: loanID},
+			{Key: "payment_account_id", Value: checkingID},
+			{Key: "amount", Value: 500.0},
+			{Key: "principal", Value: 500.0},
+			{Key: "principal_transaction_id", Value: transactionID},
+			{Key: "payment_date", Value: now},
+		}
+		transaction := bson.D{
+			{Key: "_id", Value: transactionID},
+			{Key: "workspace_id", Value: workspaceID},
+			{Key: "user_id", Value: userID},
+			{Key: "account_id", Value: checkingID},
+			{Key: "to_account_id", Value: loanID},
+			{Key: "type", Value: "transfer"},
+			{Key: "amount", Value: 500.0},
+			{Key: "transaction_date", Value: now},
+		}
+		written := bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}}
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.loan_payments", mtest.FirstBatch, payment),    // payment lookup
+			mtest.CreateCursorResponse(0, "db.loan_payments", mtest.FirstBatch, payment),    // later payments
+			mtest.CreateCursorResponse(0, "db.transactions", mtest.FirstBatch, transaction), // principal transaction lookup
+			mtest.CreateSuccessResponse(written...),                                         // checking balance
+			mtest.CreateSuccessResponse(written...),                                         // loan balance
+			mtest.CreateSuccessResponse(written...),                                         // transaction removal
+			mtest.CreateCursorResponse(0, "db.attachments", mtest.FirstBatch),               // attachments
+			mtest.CreateCursorResponse(0, "db.audit_log", mtest.FirstBatch),                 // latest audit version
+			mtest.CreateSuccessResponse(),                                                   // audit entry
+			mtest.CreateSuccessResponse(written...),                                         // payment removal
+			mtest.CreateCursorResponse(0, "db.transactions", mtest.FirstBatch),              // trash lookup on restore
+		)
+
+		if err := loanService.DeletePayment(loanID, paymentID, workspaceID, userID); err != nil {
+			mt.Fatalf("DeletePayment returned an error: %v", err)
+		}
+
+		// The principal transfer must be removed outright, not moved to the trash
+		var removed bool
+		for _, event := range mt.GetAllStartedEvents() {
+			collection, _ := event.Command.Lookup(event.CommandName).StringValueOK()
+			if collection != "transactions" {
+				continue
+			}
+			switch event.CommandName {
+			case "delete":
+				removed = true
+			case "update", "findAndModify":
+				mt.Fatalf("principal transaction was updated instead of removed: %s", event.Command)
+			}
+		}
+		if !removed {
+			mt.Fatal("principal transaction was not removed")
+		}
+
+		if _, err := transactionService.RestoreTransaction(transactionID, workspaceID, userID); err == nil {
+			mt.Fatal("reversed loan payment transaction was restored from the trash")
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+)
+
+// PayeeService handles business logic for payees
+type PayeeService struct {
+	repo            *repositories.PayeeRepository
+	transactionRepo *repositories.TransactionRepository
+	categoryRepo    *repositories.CategoryRepository
+	auditService    *AuditService
+}
+
+// NewPayeeService creates a new payee service
+func NewPayeeService(
+	repo *repositories.PayeeRepository,
+	transactionRepo *repositories.TransactionRepository,
+	categoryRepo *repositories.CategoryRepository,
+	auditService *AuditService,
+) *PayeeService {
+	return &PayeeService{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		auditService:    auditService,
+	}
+}
+
+// CreatePayee creates a new payee
+func (s *PayeeService) CreatePayee(workspaceID, userID string, req models.CreatePayeeRequest) (*models.Payee, error) {
+	if err := s.validateName(workspaceID, "", req.Name); err != nil {
+		return nil, err
+	}
+	if err := utils.ValidatePayeePatterns(req.Patterns); err != nil {
+		return nil, err
+	}
+	if err := s.validateDefaultCategory(workspaceID, req.DefaultCategoryID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(workspaceID, userID, req)
+}
+
+// GetPayee retrieves a payee by ID
+func (s *PayeeService) GetPayee(id, workspaceID string) (*models.Payee, error) {
+	payee, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if payee == nil {
+		return nil, fmt.Errorf("payee not found")
+	}
+	return payee, nil
+}
+
+// GetAllPayees retrieves all payees of a workspace
+func (s *PayeeService) GetAllPayees(workspaceID string) ([]models.Payee, error) {
+	return s.repo.GetAll(workspaceID)
+}
+
+// UpdatePayee updates a payee
+// Transactions already linked keep their link; new transactions are matched against the updated aliases and patterns.
+func (s *PayeeService) UpdatePayee(id, workspaceID string, req models.UpdatePayeeRequest) (*models.Payee, error) {
+	if _, err := s.GetPayee(id, workspaceID); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if err := s.validateName(workspaceID, id, *req.Name); err != nil {
+			return nil, err
+		}
+	}
+	if err := utils.ValidatePayeePatterns(req.Patterns); err != nil {
+		return nil, err
+	}
+	if req.DefaultCategoryID != nil && *req.DefaultCategoryID != "" {
+		if err := s.validateDefaultCategory(workspaceID, req.DefaultCategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	payee, err := s.repo.Update(context.Background(), id, workspaceID, req)
+	if err != nil {
+		return nil, err
+	}
+	if payee == nil {
+		return nil, fmt.Errorf("payee not found")
+	}
+
+	return payee, nil
+}
+
+// DeletePayee deletes a payee and unlinks its transactions, which keep their merchant text
+func (s *PayeeService) DeletePayee(id, workspaceID string) error {
+	if _, err := s.GetPayee(id, workspaceID); err != nil {
+		return err
+	}
+
+	if _, err := s.transactionRepo.UnlinkPayee(workspaceID, id); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(context.Background(), id, workspaceID)
+}
+
+// MergePayees folds other payees into a payee
+// Their names, aliases and patterns become aliases and patterns of the target, their transactions are
+// relinked to it and the merged payees are deleted, all in one database transaction.
+func (s *PayeeService) MergePayees(id, workspaceID, actorID string, req models.MergePayeesRequest) (*models.MergePayeesResult, error) {
+	target, err := s.GetPayee(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []*models.Payee
+	seen := map[string]bool{}
+	for _, sourceID := range req.SourceIDs {
+		if sourceID == id {
+			return nil, fmt.Errorf("cannot merge a payee into itself")
+		}
+		if seen[sourceID] {
+			continue
+		}
+		seen[sourceID] = true
+
+		source, err := s.repo.GetByID(sourceID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil {
+			return nil, fmt.Errorf("payee %s not found", sourceID)
+		}
+		sources = append(sources, source)
+	}
+
+	// Collect names and patterns, skipping ones the target already covers
+	knownNames := map[string]bool{target.NormalizedName: true}
+	aliases := []string{}
+	for _, alias := range target.Aliases {
+		knownNames[utils.NormalizeMerchantName(alias)] = true
+		aliases = append(aliases, alias)
+	}
+	knownPatterns := map[string]bool{}
+	patterns := []string{}
+	for _, pattern := range target.Patterns {
+		knownPatterns[pattern] = true
+		patterns = append(patterns, pattern)
+	}
+
+	update := models.UpdatePayeeRequest{}
+	for _, source := range sources {
+		for _, name := range append([]string{source.Name}, source.Aliases...) {
+			normalized := utils.NormalizeMerchantName(name)
+			if normalized == "" || knownNames[normalized] {
+				continue
+			}
+			knownNames[normalized] = true
+			aliases = append(aliases, name)
+		}
+		for _, pattern := range source.Patterns {
+			if !knownPatterns[pattern] {
+				knownPatterns[pattern] = true
+				patterns = append(patterns, pattern)
+			}
+		}
+		if target.DefaultCategoryID == nil && update.DefaultCategoryID == nil && source.DefaultCategoryID != nil {
+			update.DefaultCategoryID = source.DefaultCategoryID
+		}
+		if target.Logo == nil && update.Logo == nil && source.Logo != nil {
+			update.Logo = source.Logo
+		}
+	}
+	update.Aliases = aliases
+	update.Patterns = patterns
+
+	sourceIDs := make([]string, len(sources))
+	for i, source := range sources {
+		sourceIDs[i] = source.ID
+	}
+
+	var merged *models.Payee
+	var moved []models.Transaction
+	err = s.repo.RunInTransaction(func(ctx context.Context) error {
+		if merged, err = s.repo.Update(ctx, id, workspaceID, update); err != nil {
+			return err
+		}
+		if merged == nil {
+			return fmt.Errorf("payee not found")
+		}
+
+		if moved, err = s.transactionRepo.ReassignPayee(ctx, workspaceID, sourceIDs, id); err != nil {
+			return fmt.Errorf("failed to move transactions: %v", err)
+		}
+
+		for _, sourceID := range sourceIDs {
+			if err := s.repo.Delete(ctx, sourceID, workspaceID); err != nil {
+				return fmt.Errorf("failed to delete merged payee %s: %v", sourceID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range moved {
+		updated := moved[i]
+		updated.PayeeID = &id
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityTransaction,
+			EntityID:    moved[i].ID,
+			Action:      models.AuditActionUpdate,
+			ActorID:     actorID,
+		}, &moved[i], &updated)
+	}
+
+	return &models.MergePayeesResult{
+		Payee:               merged,
+		MergedCount:         len(sources),
+		TransactionsUpdated: int64(len(moved)),
+	}, nil
+}
+
+// validateName checks that a payee name is not empty once normalized and not taken by another payee
+func (s *PayeeService) validateName(workspaceID, id, name string) error {
+	normalized := utils.NormalizeMerchantName(name)
+	if normalized == "" {
+		return fmt.Errorf("payee name must contain letters or words, not only numbers or symbols")
+	}
+
+	existing, err := s.repo.GetByNormalizedName(workspaceID, normalized)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return fmt.Errorf("payee %s already exists", existing.Name)
+	}
+
+	return nil
+}
+
+// validateDefaultCategory checks that a default category exists in the workspace
+func (s *PayeeService) validateDefaultCategory(workspaceID string, categoryID *string) error {
+	if categoryID == nil || *categoryID == "" {
+		return nil
+	}
+
+	category, err := s.categoryRepo.GetByID(*categoryID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return fmt.Errorf("category not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"time"
+)
+
+// ReconciliationService handles business logic for reconciling accounts against bank statements
+type ReconciliationService struct {
+	repo            *repositories.ReconciliationRepository
+	transactionRepo *repositories.TransactionRepository
+	accountRepo     *repositories.AccountRepository
+	auditService    *AuditService
+}
+
+// NewReconciliationService creates a new reconciliation service
+func NewReconciliationService(
+	repo *repositories.ReconciliationRepository,
+	transactionRepo *repositories.TransactionRepository,
+	accountRepo *repositories.AccountRepository,
+	auditService *AuditService,
+) *ReconciliationService {
+	return &ReconciliationService{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+		auditService:    auditService,
+	}
+}
+
+// StartReconciliation starts reconciling an account against a statement
+func (s *ReconciliationService) StartReconciliation(accountID, workspaceID, userID string, req models.CreateReconciliationRequest) (*models.ReconciliationProgress, error) {
+	account, err := s.accountRepo.GetByID(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found")
+	}
+
+	inProgress, err := s.repo.GetInProgress(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if inProgress != nil {
+		return nil, fmt.Errorf("account already has a reconciliation in progress")
+	}
+
+	reconciliation, err := s.repo.Create(workspaceID, userID, accountID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildProgress(reconciliation, account.Balance)
+}
+
+// GetReconciliations retrieves the reconciliations of an account
+func (s *ReconciliationService) GetReconciliations(accountID, workspaceID string) ([]models.Reconciliation, error) {
+	account, err := s.accountRepo.GetByID(accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found")
+	}
+
+	return s.repo.GetByAccountID(accountID, workspaceID)
+}
+
+// GetReconciliation retrieves a reconciliation with its cleared balance, difference and open transactions
+func (s *ReconciliationService) GetReconciliation(id, accountID, workspaceID string) (*models.ReconciliationProgress, error) {
+	reconciliation, account, err := s.getReconciliation(id, accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildProgress(reconciliation, account.Balance)
+}
+
+// ClearTransactions marks transactions of the account as cleared, or back to pending, and returns the new difference
+func (s *ReconciliationService) ClearTransactions(id, accountID, workspaceID, actorID string, req models.ClearTransactionsRequest) (*models.ReconciliationProgress, error) {
+	reconciliation, account, err := s.getReconciliation(id, accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if reconciliation.Status != models.ReconciliationStatusInProgress {
+		return nil, fmt.Errorf("reconciliation is already completed")
+	}
+
+	status := models.TransactionStatusPending
+	if req.Cleared {
+		status = models.TransactionStatusCleared
+	}
+
+	// Validate every transaction before changing any of them
+	var transactions []*models.Transaction
+	for _, transactionID := range req.TransactionIDs {
+		transaction, err := s.transactionRepo.GetByID(transactionID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if transaction == nil {
+			return nil, fmt.Errorf("transaction %s not found", transactionID)
+		}
+		if transaction.AccountID != accountID && (transaction.ToAccountID == nil || *transaction.ToAccountID != accountID) {
+			return nil, fmt.Errorf("transaction %s does not belong to this account", transactionID)
+		}
+		if transaction.Status == models.TransactionStatusReconciled {
+			return nil, fmt.Errorf("transaction %s is already reconciled", transactionID)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if _, err := s.transactionRepo.SetStatus(workspaceID, req.TransactionIDs, status); err != nil {
+		return nil, err
+	}
+	s.recordStatusChanges(workspaceID, actorID, transactions, status)
+
+	return s.buildProgress(reconciliation, account.Balance)
+}
+
+// FinishReconciliation locks the cleared transactions up to the statement date as reconciled
+// It only succeeds once the cleared balance matches the statement balance
+func (s *ReconciliationService) FinishReconciliation(id, accountID, workspaceID, actorID string) (*models.Reconciliation, error) {
+	reconciliation, account, err := s.getReconciliation(id, accountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if reconciliation.Status != models.ReconciliationStatusInProgress {
+		return nil, fmt.Errorf("reconciliation is already completed")
+	}
+
+	progress, err := s.buildProgress(reconciliation, account.Balance)
+	if err != nil {
+		return nil, err
+	}
+	if progress.Difference != 0 {
+		return nil, fmt.Errorf("cleared balance differs from the statement balance by %.2f", progress.Difference)
+	}
+
+	var cleared []*models.Transaction
+	var clearedIDs []string
+	for i := range progress.Transactions {
+		if progress.Transactions[i].Status == models.TransactionStatusCleared {
+			cleared = append(cleared, &progress.Transactions[i])
+			clearedIDs = append(clearedIDs, progress.Transactions[i].ID)
+		}
+	}
+
+	if len(clearedIDs) > 0 {
+		if _, err := s.transactionRepo.SetStatus(workspaceID, clearedIDs, models.TransactionStatusReconciled); err != nil {
+			return nil, err
+		}
+		s.recordStatusChanges(workspaceID, actorID, cleared, models.TransactionStatusReconciled)
+	}
+
+	completed, err := s.repo.Complete(id, workspaceID, len(clearedIDs))
+	if err != nil {
+		return nil, err
+	}
+	if completed == nil {
+		return nil, fmt.Errorf("reconciliation is already completed")
+	}
+
+	return completed, nil
+}
+
+// CancelReconciliation discards an unfinished reconciliation
+// Transactions keep their cleared status so the work carries over to the next attempt
+func (s *ReconciliationService) CancelReconciliation(id, accountID, workspaceID string) error {
+	reconciliation, _, err := s.getReconciliation(id, accountID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if reconciliation.Status != models.ReconciliationStatusInProgress {
+		return fmt.Errorf("cannot cancel a completed reconciliation")
+	}
+
+	return s.repo.Delete(id, workspaceID)
+}
+
+// getReconciliation loads a reconciliation together with its account
+func (s *ReconciliationService) getReconciliation(id, accountID, workspaceID string) (*models.Reconciliation, *models.Account, error) {
+	account, err := s.accountRepo.GetByID(accountID, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if account == nil {
+		return nil, nil, fmt.Errorf("account not found")
+	}
+
+	reconciliation, err := s.repo.GetByID(id, accountID, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if reconciliation == nil {
+		return nil, nil, fmt.Errorf("reconciliation not found")
+	}
+
+	return reconciliation, account, nil
+}
+
+// buildProgress works out the cleared balance as of the statement date and lists the transactions left to tick off
+//
+// The cleared balance is the current balance without the transactions the statement cannot contain:
+// pending ones, and cleared ones dated after the statement date.
+func (s *ReconciliationService) buildProgress(reconciliation *models.Reconciliation, balance float64) (*models.ReconciliationProgress, error) {
+	progress := &models.ReconciliationProgress{
+		Reconciliation: *reconciliation,
+		Transactions:   []models.Transaction{},
+	}
+	if reconciliation.Status != models.ReconciliationStatusInProgress {
+		return progress, nil
+	}
+
+	cutoff := statementCutoff(reconciliation.StatementDate)
+
+	uncleared, err := s.transactionRepo.GetUnclearedEffect(reconciliation.WorkspaceID, reconciliation.AccountID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.transactionRepo.GetUnreconciled(reconciliation.WorkspaceID, reconciliation.AccountID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range transactions {
+		if transaction.Status == models.TransactionStatusCleared {
+			progress.ClearedCount++
+		} else {
+			progress.PendingCount++
+		}
+	}
+
+	progress.ClearedBalance = utils.RoundToTwoDecimals(balance - uncleared)
+	progress.Difference = utils.RoundToTwoDecimals(reconciliation.StatementBalance - progress.ClearedBalance)
+	progress.Transactions = transactions
+
+	return progress, nil
+}
+
+// recordStatusChanges adds an audit entry for each transaction whose status changed
+func (s *ReconciliationService) recordStatusChanges(workspaceID, actorID string, transactions []*models.Transaction, status string) {
+	for _, transaction := range transactions {
+		updated := *transaction
+		updated.Status = status
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityTransaction,
+			EntityID:    transaction.ID,
+			Action:      models.AuditActionUpdate,
+			ActorID:     actorID,
+		}, transaction, &updated)
+	}
+}
+
+// statementCutoff returns the start of the day after the statement date, so the whole statement day is included
+func statementCutoff(statementDate time.Time) time.Time {
+	year, month, day := statementDate.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, statementDate.Location()).AddDate(0, 0, 1)
+}
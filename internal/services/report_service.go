@@ -4,6 +4,7 @@ import (
 	"errors"
 	"finance-hub-api/internal/models"
 	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
 	"time"
 )
 
@@ -11,23 +12,29 @@ import (
 type ReportService struct {
 	transactionRepo *repositories.TransactionRepository
 	categoryRepo    *repositories.CategoryRepository
+	payeeRepo       *repositories.PayeeRepository
+	tagRepo         *repositories.TagRepository
 }
 
 // NewReportService creates a new report service
 func NewReportService(
 	transactionRepo *repositories.TransactionRepository,
 	categoryRepo *repositories.CategoryRepository,
+	payeeRepo *repositories.PayeeRepository,
+	tagRepo *repositories.TagRepository,
 ) *ReportService {
 	return &ReportService{
 		transactionRepo: transactionRepo,
 		categoryRepo:    categoryRepo,
+		payeeRepo:       payeeRepo,
+		tagRepo:         tagRepo,
 	}
 }
 
 // GetOverview generates overview report for a date range
-func (s *ReportService) GetOverview(userID string, startDate, endDate time.Time) (*models.OverviewReport, error) {
+func (s *ReportService) GetOverview(workspaceID string, startDate, endDate time.Time) (*models.OverviewReport, error) {
 	// Get transactions for the period (exclude transfers)
-	transactions, err := s.transactionRepo.GetByDateRange(userID, startDate, endDate)
+	transactions, err := s.transactionRepo.GetByDateRange(workspaceID, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -41,10 +48,10 @@ func (s *ReportService) GetOverview(userID string, startDate, endDate time.Time)
 		}
 		transactionCount++
 
-		if tx.Type == "income" {
+		if amount, ok := reportExpense(tx); ok {
+			totalExpense += amount
+		} else if tx.Type == "income" {
 			totalIncome += tx.Amount
-		} else if tx.Type == "expense" {
-			totalExpense += tx.Amount
 		}
 	}
 
@@ -64,7 +71,7 @@ func (s *ReportService) GetOverview(userID string, startDate, endDate time.Time)
 	// Calculate comparison with previous month
 	prevMonthStart := startDate.AddDate(0, -1, 0)
 	prevMonthEnd := endDate.AddDate(0, -1, 0)
-	comparison, _ := s.calculateComparison(userID, prevMonthStart, prevMonthEnd, totalIncome, totalExpense, netSaving)
+	comparison, _ := s.calculateComparison(workspaceID, prevMonthStart, prevMonthEnd, totalIncome, totalExpense, netSaving)
 
 	return &models.OverviewReport{
 		TotalIncome:         totalIncome,
@@ -79,11 +86,11 @@ func (s *ReportService) GetOverview(userID string, startDate, endDate time.Time)
 
 // calculateComparison calculates percentage change compared to previous period
 func (s *ReportService) calculateComparison(
-	userID string,
+	workspaceID string,
 	prevStart, prevEnd time.Time,
 	currentIncome, currentExpense, currentSaving float64,
 ) (models.ComparisonMetrics, error) {
-	prevTransactions, err := s.transactionRepo.GetByDateRange(userID, prevStart, prevEnd)
+	prevTransactions, err := s.transactionRepo.GetByDateRange(workspaceID, prevStart, prevEnd)
 	if err != nil {
 		return models.ComparisonMetrics{}, nil // Return zero on error
 	}
@@ -93,10 +100,10 @@ func (s *ReportService) calculateComparison(
 		if tx.Type == "transfer" {
 			continue
 		}
-		if tx.Type == "income" {
+		if amount, ok := reportExpense(tx); ok {
+			prevExpense += amount
+		} else if tx.Type == "income" {
 			prevIncome += tx.Amount
-		} else if tx.Type == "expense" {
-			prevExpense += tx.Amount
 		}
 	}
 	prevSaving := prevIncome - prevExpense
@@ -125,9 +132,9 @@ func calculatePercentageChange(oldValue, newValue float64) float64 {
 }
 
 // GetByCategory generates category breakdown report
-func (s *ReportService) GetByCategory(userID string, startDate, endDate time.Time) ([]models.CategoryReport, error) {
+func (s *ReportService) GetByCategory(workspaceID string, startDate, endDate time.Time) ([]models.CategoryReport, error) {
 	// Get expense transactions
-	transactions, err := s.transactionRepo.GetByDateRange(userID, startDate, endDate)
+	transactions, err := s.transactionRepo.GetByDateRange(workspaceID, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -137,11 +144,12 @@ func (s *ReportService) GetByCategory(userID string, startDate, endDate time.Tim
 	var totalExpense float64
 
 	for _, tx := range transactions {
-		if tx.Type != "expense" || tx.CategoryID == nil {
+		amount, ok := reportExpense(tx)
+		if !ok || tx.CategoryID == nil {
 			continue
 		}
 
-		totalExpense += tx.Amount
+		totalExpense += amount
 		categoryID := *tx.CategoryID
 
 		if _, exists := categoryMap[categoryID]; !exists {
@@ -154,13 +162,13 @@ func (s *ReportService) GetByCategory(userID string, startDate, endDate time.Tim
 			}
 		}
 
-		categoryMap[categoryID].Amount += tx.Amount
+		categoryMap[categoryID].Amount += amount
 		categoryMap[categoryID].TransactionCount++
 	}
 
 	// Get category names
 	for categoryID, report := range categoryMap {
-		category, err := s.categoryRepo.GetByID(categoryID, userID)
+		category, err := s.categoryRepo.GetByID(categoryID, workspaceID)
 		if err == nil && category != nil {
 			report.CategoryName = category.Name
 		}
@@ -192,35 +200,60 @@ func (s *ReportService) GetByCategory(userID string, startDate, endDate time.Tim
 }
 
 // GetByMerchant generates merchant breakdown report
-func (s *ReportService) GetByMerchant(userID string, startDate, endDate time.Time) ([]models.MerchantReport, error) {
+// Transactions linked to a payee are grouped under it; the rest are grouped by normalized merchant text.
+func (s *ReportService) GetByMerchant(workspaceID string, startDate, endDate time.Time) ([]models.MerchantReport, error) {
 	// Get expense transactions
-	transactions, err := s.transactionRepo.GetByDateRange(userID, startDate, endDate)
+	transactions, err := s.transactionRepo.GetByDateRange(workspaceID, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter expenses and group by merchant
+	payees, err := s.payeeRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	payeeMap := make(map[string]*models.Payee, len(payees))
+	for i := range payees {
+		payeeMap[payees[i].ID] = &payees[i]
+	}
+
+	// Filter expenses and group by payee or merchant
 	merchantMap := make(map[string]*models.MerchantReport)
 	var totalExpense float64
 
 	for _, tx := range transactions {
-		if tx.Type != "expense" || tx.Merchant == nil {
+		amount, ok := reportExpense(tx)
+		if !ok || (tx.Merchant == nil && tx.PayeeID == nil) {
 			continue
 		}
 
-		totalExpense += tx.Amount
-		merchant := *tx.Merchant
-
-		if _, exists := merchantMap[merchant]; !exists {
-			merchantMap[merchant] = &models.MerchantReport{
-				Merchant:         merchant,
-				Amount:           0,
-				TransactionCount: 0,
+		var key string
+		report := models.MerchantReport{}
+		if tx.PayeeID != nil && payeeMap[*tx.PayeeID] != nil {
+			payee := payeeMap[*tx.PayeeID]
+			key = "payee:" + payee.ID
+			report.Merchant = payee.Name
+			report.PayeeID = &payee.ID
+			report.Logo = payee.Logo
+		} else if tx.Merchant != nil {
+			normalized := utils.NormalizeMerchantName(*tx.Merchant)
+			if normalized == "" {
+				normalized = *tx.Merchant
 			}
+			key = "merchant:" + normalized
+			report.Merchant = *tx.Merchant
+		} else {
+			continue
+		}
+
+		totalExpense += amount
+
+		if _, exists := merchantMap[key]; !exists {
+			merchantMap[key] = &report
 		}
 
-		merchantMap[merchant].Amount += tx.Amount
-		merchantMap[merchant].TransactionCount++
+		merchantMap[key].Amount += amount
+		merchantMap[key].TransactionCount++
 	}
 
 	// Calculate percentages
@@ -244,8 +277,78 @@ func (s *ReportService) GetByMerchant(userID string, startDate, endDate time.Tim
 	return result, nil
 }
 
+// GetByTag generates tag breakdown report
+// A transaction counts toward each of its tags; untagged expenses are left out.
+func (s *ReportService) GetByTag(workspaceID string, startDate, endDate time.Time) ([]models.TagReport, error) {
+	// Get expense transactions
+	transactions, err := s.transactionRepo.GetByDateRange(workspaceID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	colors := make(map[string]*string, len(tags))
+	for _, tag := range tags {
+		colors[tag.Name] = tag.Color
+	}
+
+	// Filter tagged expenses and group by tag
+	tagMap := make(map[string]*models.TagReport)
+	var totalExpense float64
+
+	for _, tx := range transactions {
+		amount, ok := reportExpense(tx)
+		if !ok || len(tx.Tags) == 0 {
+			continue
+		}
+
+		totalExpense += amount
+
+		seen := make(map[string]bool, len(tx.Tags))
+		for _, tag := range tx.Tags {
+			if seen[tag] {
+				continue
+			}
+			seen[tag] = true
+
+			if _, exists := tagMap[tag]; !exists {
+				tagMap[tag] = &models.TagReport{
+					Tag:   tag,
+					Color: colors[tag],
+				}
+			}
+
+			tagMap[tag].Amount += amount
+			tagMap[tag].TransactionCount++
+		}
+	}
+
+	// Calculate percentages
+	result := make([]models.TagReport, 0, len(tagMap))
+	for _, report := range tagMap {
+		if totalExpense > 0 {
+			report.Percentage = (report.Amount / totalExpense) * 100
+		}
+		result = append(result, *report)
+	}
+
+	// Sort by amount descending
+	for i := 0; i < len(result); i++ {
+		for j := i + 1; j < len(result); j++ {
+			if result[j].Amount > result[i].Amount {
+				result[i], result[j] = result[j], result[i]
+			}
+		}
+	}
+
+	return result, nil
+}
+
 // GetWeeklySpending generates weekly spending report for a month
-func (s *ReportService) GetWeeklySpending(userID, month string, categoryID *string) ([]models.WeeklySpending, error) {
+func (s *ReportService) GetWeeklySpending(workspaceID, month string, categoryID *string) ([]models.WeeklySpending, error) {
 	// Parse month (format: YYYY-MM)
 	monthStart, monthEnd, err := parseMonthRangeForReport(month)
 	if err != nil {
@@ -253,7 +356,7 @@ func (s *ReportService) GetWeeklySpending(userID, month string, categoryID *stri
 	}
 
 	// Get transactions for the month
-	transactions, err := s.transactionRepo.GetByDateRange(userID, monthStart, monthEnd)
+	transactions, err := s.transactionRepo.GetByDateRange(workspaceID, monthStart, monthEnd)
 	if err != nil {
 		return nil, err
 	}
@@ -261,7 +364,7 @@ func (s *ReportService) GetWeeklySpending(userID, month string, categoryID *stri
 	// Filter expenses and by category if specified
 	var filteredTxs []models.Transaction
 	for _, tx := range transactions {
-		if tx.Type != "expense" {
+		if _, ok := reportExpense(tx); !ok {
 			continue
 		}
 		if categoryID != nil && (tx.CategoryID == nil || *tx.CategoryID != *categoryID) {
@@ -292,9 +395,11 @@ func (s *ReportService) GetWeeklySpending(userID, month string, categoryID *stri
 		var weekAmount float64
 		weekTxCount := 0
 		for _, tx := range filteredTxs {
-			if (tx.TransactionDate.After(effectiveStart) || tx.TransactionDate.Equal(effectiveStart)) &&
-				(tx.TransactionDate.Before(effectiveEnd) || tx.TransactionDate.Equal(effectiveEnd)) {
-				weekAmount += tx.Amount
+			date := reportDate(tx)
+			if (date.After(effectiveStart) || date.Equal(effectiveStart)) &&
+				(date.Before(effectiveEnd) || date.Equal(effectiveEnd)) {
+				amount, _ := reportExpense(tx)
+				weekAmount += amount
 				weekTxCount++
 			}
 		}
@@ -318,7 +423,7 @@ func (s *ReportService) GetWeeklySpending(userID, month string, categoryID *stri
 }
 
 // GetWeeklyCashflow generates weekly cashflow report for a month
-func (s *ReportService) GetWeeklyCashflow(userID, month string) ([]models.WeeklyCashflow, error) {
+func (s *ReportService) GetWeeklyCashflow(workspaceID, month string) ([]models.WeeklyCashflow, error) {
 	// Parse month
 	monthStart, monthEnd, err := parseMonthRangeForReport(month)
 	if err != nil {
@@ -326,7 +431,7 @@ func (s *ReportService) GetWeeklyCashflow(userID, month string) ([]models.Weekly
 	}
 
 	// Get transactions for the month
-	transactions, err := s.transactionRepo.GetByDateRange(userID, monthStart, monthEnd)
+	transactions, err := s.transactionRepo.GetByDateRange(workspaceID, monthStart, monthEnd)
 	if err != nil {
 		return nil, err
 	}
@@ -360,12 +465,13 @@ func (s *ReportService) GetWeeklyCashflow(userID, month string) ([]models.Weekly
 		// Get transactions for this week
 		var weekIncome, weekExpense float64
 		for _, tx := range filteredTxs {
-			if (tx.TransactionDate.After(effectiveStart) || tx.TransactionDate.Equal(effectiveStart)) &&
-				(tx.TransactionDate.Before(effectiveEnd) || tx.TransactionDate.Equal(effectiveEnd)) {
-				if tx.Type == "income" {
+			date := reportDate(tx)
+			if (date.After(effectiveStart) || date.Equal(effectiveStart)) &&
+				(date.Before(effectiveEnd) || date.Equal(effectiveEnd)) {
+				if amount, ok := reportExpense(tx); ok {
+					weekExpense += amount
+				} else if tx.Type == "income" {
 					weekIncome += tx.Amount
-				} else if tx.Type == "expense" {
-					weekExpense += tx.Amount
 				}
 			}
 		}
@@ -389,6 +495,26 @@ func (s *ReportService) GetWeeklyCashflow(userID, month string) ([]models.Weekly
 	return weeks, nil
 }
 
+// reportExpense returns what a transaction adds to spending
+// Expenses count in full and refunds count against the expense they return; other transactions are not spending.
+func reportExpense(tx models.Transaction) (float64, bool) {
+	if tx.Type == "expense" {
+		return tx.Amount, true
+	}
+	if tx.RefundOfID != nil {
+		return -tx.Amount, true
+	}
+	return 0, false
+}
+
+// reportDate returns the date a transaction is reported at: refunds are placed at their original expense
+func reportDate(tx models.Transaction) time.Time {
+	if tx.RefundOfDate != nil {
+		return *tx.RefundOfDate
+	}
+	return tx.TransactionDate
+}
+
 // getWeekStart returns the Monday of the week containing the given date
 func getWeekStart(date time.Time) time.Time {
 	weekday := int(date.Weekday())
@@ -0,0 +1,524 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// splitTolerance absorbs rounding when checking that split amounts or percentages add up
+const splitTolerance = 0.01
+
+// SharedExpenseService handles business logic for shared expenses and settling up
+type SharedExpenseService struct {
+	repo               *repositories.SharedExpenseRepository
+	contactRepo        *repositories.ContactRepository
+	transactionRepo    *repositories.TransactionRepository
+	transactionService *TransactionService
+}
+
+// NewSharedExpenseService creates a new shared expense service
+func NewSharedExpenseService(
+	repo *repositories.SharedExpenseRepository,
+	contactRepo *repositories.ContactRepository,
+	transactionRepo *repositories.TransactionRepository,
+	transactionService *TransactionService,
+) *SharedExpenseService {
+	return &SharedExpenseService{
+		repo:               repo,
+		contactRepo:        contactRepo,
+		transactionRepo:    transactionRepo,
+		transactionService: transactionService,
+	}
+}
+
+// CreateSharedExpense splits an expense between the user and contacts
+func (s *SharedExpenseService) CreateSharedExpense(workspaceID, userID string, req models.CreateSharedExpenseRequest) (*models.SharedExpense, error) {
+	expense := &models.SharedExpense{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		GroupID:     req.GroupID,
+		PaidBy:      req.PaidBy,
+		SplitType:   req.SplitType,
+		Description: req.Description,
+		ExpenseDate: time.Now(),
+	}
+	if expense.PaidBy == "" {
+		expense.PaidBy = models.SelfParticipantID
+	}
+	if req.ExpenseDate != nil {
+		expense.ExpenseDate = *req.ExpenseDate
+	}
+
+	if req.TransactionID != nil && *req.TransactionID != "" {
+		// Sharing one of the user's own expenses: the user is the payer
+		transaction, err := s.transactionRepo.GetByID(*req.TransactionID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if transaction == nil {
+			return nil, fmt.Errorf("transaction not found")
+		}
+		if transaction.Type != "expense" {
+			return nil, fmt.Errorf("only expense transactions can be shared")
+		}
+		if expense.PaidBy != models.SelfParticipantID {
+			return nil, fmt.Errorf("a shared transaction must be paid by yourself")
+		}
+
+		existing, err := s.repo.GetByTransactionID(transaction.ID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("transaction is already shared")
+		}
+
+		expense.TransactionID = &transaction.ID
+		expense.TotalAmount = transaction.Amount
+		if req.ExpenseDate == nil {
+			expense.ExpenseDate = transaction.TransactionDate
+		}
+		if expense.Description == nil {
+			if transaction.Description != nil {
+				expense.Description = transaction.Description
+			} else {
+				expense.Description = transaction.Merchant
+			}
+		}
+	} else {
+		if req.TotalAmount == nil || *req.TotalAmount <= 0 {
+			return nil, fmt.Errorf("total_amount is required when no transaction_id is given")
+		}
+		expense.TotalAmount = *req.TotalAmount
+	}
+
+	// Collect every contact involved so they can be validated in one query
+	contactIDs := make([]string, 0, len(req.Shares)+1)
+	seen := make(map[string]bool)
+	for _, share := range req.Shares {
+		if seen[share.ParticipantID] {
+			return nil, fmt.Errorf("duplicate participant %s", share.ParticipantID)
+		}
+		seen[share.ParticipantID] = true
+		if share.ParticipantID != models.SelfParticipantID {
+			contactIDs = append(contactIDs, share.ParticipantID)
+		}
+	}
+	if expense.PaidBy != models.SelfParticipantID && !seen[expense.PaidBy] {
+		contactIDs = append(contactIDs, expense.PaidBy)
+	}
+	if len(contactIDs) == 0 {
+		return nil, fmt.Errorf("a shared expense needs at least one contact")
+	}
+
+	count, err := s.contactRepo.CountByIDs(workspaceID, contactIDs)
+	if err != nil {
+		return nil, err
+	}
+	if count != len(contactIDs) {
+		return nil, fmt.Errorf("contact not found")
+	}
+
+	if req.GroupID != nil && *req.GroupID != "" {
+		group, err := s.contactRepo.GetGroupByID(*req.GroupID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if group == nil {
+			return nil, fmt.Errorf("group not found")
+		}
+		for _, id := range contactIDs {
+			if !utils.Contains(group.ContactIDs, id) {
+				return nil, fmt.Errorf("contact %s is not a member of the group", id)
+			}
+		}
+	} else {
+		expense.GroupID = nil
+	}
+
+	shares, err := splitShares(expense.TotalAmount, req.SplitType, req.Shares)
+	if err != nil {
+		return nil, err
+	}
+	expense.Shares = shares
+
+	if err := s.repo.Create(expense); err != nil {
+		return nil, err
+	}
+
+	return expense, nil
+}
+
+// GetSharedExpense retrieves a shared expense by ID
+func (s *SharedExpenseService) GetSharedExpense(id, workspaceID string) (*models.SharedExpense, error) {
+	expense, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if expense == nil {
+		return nil, fmt.Errorf("shared expense not found")
+	}
+	return expense, nil
+}
+
+// GetSharedExpenses retrieves shared expenses, optionally limited to a contact or a group
+func (s *SharedExpenseService) GetSharedExpenses(workspaceID, contactID, groupID string) ([]models.SharedExpense, error) {
+	return s.repo.GetAll(workspaceID, contactID, groupID)
+}
+
+// DeleteSharedExpense removes the split; the underlying transaction, if any, is kept
+func (s *SharedExpenseService) DeleteSharedExpense(id, workspaceID string) error {
+	existing, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("shared expense not found")
+	}
+
+	return s.repo.Delete(id, workspaceID)
+}
+
+// GetBalances returns the running balance with every contact
+func (s *SharedExpenseService) GetBalances(workspaceID string) ([]models.ContactBalance, error) {
+	contacts, err := s.contactRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.repo.GetAll(workspaceID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := s.repo.GetSettlements(workspaceID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]float64)
+	for _, expense := range expenses {
+		for _, contact := range contacts {
+			balances[contact.ID] += expenseDeltaForContact(expense, contact.ID)
+		}
+	}
+	for _, settlement := range settlements {
+		balances[settlement.ContactID] += settlementDelta(settlement)
+	}
+
+	result := make([]models.ContactBalance, 0, len(contacts))
+	for _, contact := range contacts {
+		result = append(result, models.ContactBalance{
+			ContactID:   contact.ID,
+			ContactName: contact.Name,
+			Balance:     utils.RoundToTwoDecimals(balances[contact.ID]),
+		})
+	}
+
+	return result, nil
+}
+
+// GetContactLedger returns the balance with a contact and the history that produced it
+func (s *SharedExpenseService) GetContactLedger(contactID, workspaceID string) (*models.ContactLedger, error) {
+	contact, err := s.contactRepo.GetByID(contactID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if contact == nil {
+		return nil, fmt.Errorf("contact not found")
+	}
+
+	expenses, err := s.repo.GetAll(workspaceID, contactID, "")
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := s.repo.GetSettlements(workspaceID, contactID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.ContactLedgerEntry, 0, len(expenses)+len(settlements))
+	for _, expense := range expenses {
+		delta := expenseDeltaForContact(expense, contactID)
+		if delta == 0 {
+			continue
+		}
+		entries = append(entries, models.ContactLedgerEntry{
+			Type:        "expense",
+			ReferenceID: expense.ID,
+			Description: expense.Description,
+			Date:        expense.ExpenseDate,
+			Amount:      delta,
+		})
+	}
+	for _, settlement := range settlements {
+		entries = append(entries, models.ContactLedgerEntry{
+			Type:        "settlement",
+			ReferenceID: settlement.ID,
+			Date:        settlement.SettledAt,
+			Amount:      settlementDelta(settlement),
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+
+	var running float64
+	for i := range entries {
+		running = utils.RoundToTwoDecimals(running + entries[i].Amount)
+		entries[i].RunningBalance = running
+	}
+
+	return &models.ContactLedger{
+		ContactBalance: models.ContactBalance{
+			ContactID:   contact.ID,
+			ContactName: contact.Name,
+			Balance:     running,
+		},
+		Entries: entries,
+	}, nil
+}
+
+// SettleUp records a repayment with a contact as a transaction on the chosen account
+func (s *SharedExpenseService) SettleUp(contactID, workspaceID, userID string, req models.SettleUpRequest) (*models.Settlement, error) {
+	ledger, err := s.GetContactLedger(contactID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := ledger.Balance
+	if math.Abs(balance) < splitTolerance {
+		return nil, fmt.Errorf("nothing to settle with %s", ledger.ContactName)
+	}
+
+	amount := math.Abs(balance)
+	if req.Amount != nil {
+		if *req.Amount > amount+splitTolerance {
+			return nil, fmt.Errorf("amount exceeds the outstanding balance of %.2f", amount)
+		}
+		amount = *req.Amount
+	}
+
+	settledAt := time.Now()
+	if req.SettledAt != nil {
+		settledAt = *req.SettledAt
+	}
+
+	// A positive balance means the contact owes the user, so the user receives money
+	direction := "received"
+	transactionType := "income"
+	description := fmt.Sprintf("Settle up with %s", ledger.ContactName)
+	if balance < 0 {
+		direction = "paid"
+		transactionType = "expense"
+	}
+
+	settlementID := uuid.New().String()
+	transaction, err := s.transactionService.CreateTransaction(workspaceID, userID, models.CreateTransactionRequest{
+		AccountID:       req.AccountID,
+		CategoryID:      &req.CategoryID,
+		Type:            transactionType,
+		Amount:          amount,
+		Description:     &description,
+		TransactionDate: settledAt,
+		Notes:           req.Notes,
+		LinkedTo:        &models.TransactionLink{Type: models.LinkTypeSettlement, ID: settlementID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := &models.Settlement{
+		ID:            settlementID,
+		WorkspaceID:   workspaceID,
+		UserID:        userID,
+		ContactID:     contactID,
+		GroupID:       req.GroupID,
+		Direction:     direction,
+		Amount:        amount,
+		AccountID:     req.AccountID,
+		TransactionID: &transaction.ID,
+		SettledAt:     settledAt,
+	}
+
+	if err := s.repo.CreateSettlement(settlement); err != nil {
+		_ = s.transactionService.ReverseTransaction(transaction.ID, workspaceID, userID)
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+// SimplifyGroupDebts computes the smallest set of payments that settles everyone in a group
+func (s *SharedExpenseService) SimplifyGroupDebts(groupID, workspaceID string) ([]models.SimplifiedDebt, error) {
+	group, err := s.contactRepo.GetGroupByID(groupID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("group not found")
+	}
+
+	expenses, err := s.repo.GetAll(workspaceID, "", groupID)
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := s.repo.GetSettlements(workspaceID, "", groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Net position per participant: positive is owed money, negative owes money
+	net := make(map[string]float64)
+	for _, expense := range expenses {
+		net[expense.PaidBy] += expense.TotalAmount
+		for _, share := range expense.Shares {
+			net[share.ParticipantID] -= share.Amount
+		}
+	}
+	for _, settlement := range settlements {
+		if settlement.Direction == "received" {
+			net[settlement.ContactID] += settlement.Amount
+			net[models.SelfParticipantID] -= settlement.Amount
+		} else {
+			net[models.SelfParticipantID] += settlement.Amount
+			net[settlement.ContactID] -= settlement.Amount
+		}
+	}
+
+	names := map[string]string{models.SelfParticipantID: "You"}
+	contacts, err := s.contactRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, contact := range contacts {
+		names[contact.ID] = contact.Name
+	}
+
+	type position struct {
+		id     string
+		amount float64
+	}
+	var creditors, debtors []position
+	for id, amount := range net {
+		amount = utils.RoundToTwoDecimals(amount)
+		if amount > splitTolerance {
+			creditors = append(creditors, position{id, amount})
+		} else if amount < -splitTolerance {
+			debtors = append(debtors, position{id, -amount})
+		}
+	}
+
+	// Greedily match the largest debtor with the largest creditor
+	sort.Slice(creditors, func(i, j int) bool { return creditors[i].amount > creditors[j].amount })
+	sort.Slice(debtors, func(i, j int) bool { return debtors[i].amount > debtors[j].amount })
+
+	result := []models.SimplifiedDebt{}
+	i, j := 0, 0
+	for i < len(debtors) && j < len(creditors) {
+		amount := math.Min(debtors[i].amount, creditors[j].amount)
+		result = append(result, models.SimplifiedDebt{
+			From:     debtors[i].id,
+			FromName: names[debtors[i].id],
+			To:       creditors[j].id,
+			ToName:   names[creditors[j].id],
+			Amount:   utils.RoundToTwoDecimals(amount),
+		})
+
+		debtors[i].amount -= amount
+		creditors[j].amount -= amount
+		if debtors[i].amount < splitTolerance {
+			i++
+		}
+		if creditors[j].amount < splitTolerance {
+			j++
+		}
+	}
+
+	return result, nil
+}
+
+// expenseDeltaForContact returns how much an expense changes what a contact owes the user
+func expenseDeltaForContact(expense models.SharedExpense, contactID string) float64 {
+	switch expense.PaidBy {
+	case models.SelfParticipantID:
+		return shareOf(expense, contactID)
+	case contactID:
+		return -shareOf(expense, models.SelfParticipantID)
+	}
+	return 0
+}
+
+// settlementDelta returns how much a settlement changes what a contact owes the user
+func settlementDelta(settlement models.Settlement) float64 {
+	if settlement.Direction == "received" {
+		return -settlement.Amount
+	}
+	return settlement.Amount
+}
+
+// shareOf returns a participant's share of an expense
+func shareOf(expense models.SharedExpense, participantID string) float64 {
+	for _, share := range expense.Shares {
+		if share.ParticipantID == participantID {
+			return share.Amount
+		}
+	}
+	return 0
+}
+
+// splitShares turns a split request into concrete share amounts that add up to the total
+func splitShares(total float64, splitType string, requested []models.ExpenseShareRequest) ([]models.ExpenseShare, error) {
+	shares := make([]models.ExpenseShare, len(requested))
+	var allocated float64
+
+	switch splitType {
+	case "equal":
+		each := math.Floor(total/float64(len(requested))*100) / 100
+		for i, share := range requested {
+			shares[i] = models.ExpenseShare{ParticipantID: share.ParticipantID, Amount: each}
+			allocated += each
+		}
+
+	case "percentage":
+		var totalPercentage float64
+		for i, share := range requested {
+			if share.Percentage == nil {
+				return nil, fmt.Errorf("percentage is required for every participant in a percentage split")
+			}
+			totalPercentage += *share.Percentage
+			amount := utils.RoundToTwoDecimals(total * *share.Percentage / 100)
+			shares[i] = models.ExpenseShare{ParticipantID: share.ParticipantID, Amount: amount, Percentage: share.Percentage}
+			allocated += amount
+		}
+		if math.Abs(totalPercentage-100) > splitTolerance {
+			return nil, fmt.Errorf("percentages must add up to 100")
+		}
+
+	case "exact":
+		for i, share := range requested {
+			if share.Amount == nil {
+				return nil, fmt.Errorf("amount is required for every participant in an exact split")
+			}
+			shares[i] = models.ExpenseShare{ParticipantID: share.ParticipantID, Amount: *share.Amount}
+			allocated += *share.Amount
+		}
+		if math.Abs(allocated-total) > splitTolerance {
+			return nil, fmt.Errorf("split amounts must add up to the total amount")
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid split type")
+	}
+
+	// Give any rounding remainder to the first participant
+	shares[0].Amount = utils.RoundToTwoDecimals(shares[0].Amount + total - allocated)
+
+	return shares, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"fmt"
+	"strings"
+)
+
+// TagService handles business logic for transaction tags
+type TagService struct {
+	repo            *repositories.TagRepository
+	transactionRepo *repositories.TransactionRepository
+	auditService    *AuditService
+}
+
+// NewTagService creates a new tag service
+func NewTagService(repo *repositories.TagRepository, transactionRepo *repositories.TransactionRepository, auditService *AuditService) *TagService {
+	return &TagService{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		auditService:    auditService,
+	}
+}
+
+// GetTags retrieves every tag of a workspace with its usage count and color, most used first
+// Tags that were given a color but are no longer on any transaction are listed with a count of 0.
+func (s *TagService) GetTags(workspaceID string) ([]models.TagUsage, error) {
+	usage, err := s.transactionRepo.GetTagUsage(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make(map[string]*string, len(settings))
+	for _, tag := range settings {
+		colors[tag.Name] = tag.Color
+	}
+
+	used := make(map[string]bool, len(usage))
+	for i := range usage {
+		usage[i].Color = colors[usage[i].Name]
+		used[usage[i].Name] = true
+	}
+	for _, tag := range settings {
+		if !used[tag.Name] {
+			usage = append(usage, models.TagUsage{Name: tag.Name, Color: tag.Color})
+		}
+	}
+
+	return usage, nil
+}
+
+// UpdateTag renames a tag on every transaction and changes its color
+func (s *TagService) UpdateTag(workspaceID, actorID, name string, req models.UpdateTagRequest) (*models.TagChangeResult, error) {
+	tags, err := s.GetTags(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	tag := findTag(tags, name)
+	if tag == nil {
+		return nil, fmt.Errorf("tag not found")
+	}
+
+	result := &models.TagChangeResult{Tag: *tag}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) != name {
+		newName, err := validateTagName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if findTag(tags, newName) != nil {
+			return nil, fmt.Errorf("tag %s already exists; merge the tags instead", newName)
+		}
+
+		if result.TransactionsUpdated, err = s.rewriteTags(workspaceID, actorID, []string{name}, newName); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Rename(workspaceID, name, newName); err != nil {
+			return nil, err
+		}
+		result.Tag.Name = newName
+	}
+
+	if req.Color != nil {
+		if err := s.repo.SetColor(workspaceID, result.Tag.Name, *req.Color); err != nil {
+			return nil, err
+		}
+		result.Tag.Color = nil
+		if *req.Color != "" {
+			result.Tag.Color = req.Color
+		}
+	}
+
+	return result, nil
+}
+
+// MergeTags replaces other tags with a tag on every transaction
+// The merged tags' settings are removed; the target keeps its color, or takes the first one a merged tag had.
+func (s *TagService) MergeTags(workspaceID, actorID, name string, req models.MergeTagsRequest) (*models.TagChangeResult, error) {
+	tags, err := s.GetTags(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	target := findTag(tags, name)
+	if target == nil {
+		return nil, fmt.Errorf("tag not found")
+	}
+
+	var sources []string
+	seen := map[string]bool{}
+	color := target.Color
+	for _, sourceName := range req.SourceTags {
+		if sourceName == name {
+			return nil, fmt.Errorf("cannot merge a tag into itself")
+		}
+		if seen[sourceName] {
+			continue
+		}
+		seen[sourceName] = true
+
+		source := findTag(tags, sourceName)
+		if source == nil {
+			return nil, fmt.Errorf("tag %s not found", sourceName)
+		}
+		if color == nil {
+			color = source.Color
+		}
+		sources = append(sources, sourceName)
+	}
+
+	count, err := s.rewriteTags(workspaceID, actorID, sources, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if color != nil && target.Color == nil {
+		if err := s.repo.SetColor(workspaceID, name, *color); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.DeleteByNames(workspaceID, sources); err != nil {
+		fmt.Printf("Warning: failed to delete settings of merged tags: %v\n", err)
+	}
+
+	updated, err := s.GetTags(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	merged := findTag(updated, name)
+	if merged == nil {
+		return nil, fmt.Errorf("tag not found")
+	}
+
+	return &models.TagChangeResult{Tag: *merged, TransactionsUpdated: count}, nil
+}
+
+// rewriteTags replaces tags on every transaction and records each changed transaction in the audit log
+func (s *TagService) rewriteTags(workspaceID, actorID string, fromTags []string, toTag string) (int64, error) {
+	before, after, err := s.transactionRepo.RewriteTags(workspaceID, fromTags, toTag)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range before {
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityTransaction,
+			EntityID:    before[i].ID,
+			Action:      models.AuditActionUpdate,
+			ActorID:     actorID,
+		}, &before[i], &after[i])
+	}
+
+	return int64(len(before)), nil
+}
+
+// findTag finds a tag by name
+func findTag(tags []models.TagUsage, name string) *models.TagUsage {
+	for i := range tags {
+		if tags[i].Name == name {
+			return &tags[i]
+		}
+	}
+	return nil
+}
+
+// validateTagName trims a tag name and checks it can be used in the comma-separated tags filter
+func validateTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("tag name cannot be empty")
+	}
+	if strings.Contains(name, ",") {
+		return "", fmt.Errorf("tag name cannot contain commas")
+	}
+	return name, nil
+}
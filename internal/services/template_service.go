@@ -0,0 +1,279 @@
+package services
+
+import (
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"time"
+)
+
+const (
+	// templateSuggestionWindow is how far back transactions are mined for suggested templates
+	templateSuggestionWindow = 90 * 24 * time.Hour
+	// templateSuggestionMinCount is how often a transaction must repeat to be suggested
+	templateSuggestionMinCount = 3
+	// templateSuggestionLimit caps the number of suggested templates
+	templateSuggestionLimit = 10
+)
+
+// TemplateService handles business logic for transaction templates
+type TemplateService struct {
+	repo               *repositories.TemplateRepository
+	accountRepo        *repositories.AccountRepository
+	categoryRepo       *repositories.CategoryRepository
+	transactionRepo    *repositories.TransactionRepository
+	transactionService *TransactionService
+}
+
+// NewTemplateService creates a new transaction template service
+func NewTemplateService(
+	repo *repositories.TemplateRepository,
+	accountRepo *repositories.AccountRepository,
+	categoryRepo *repositories.CategoryRepository,
+	transactionRepo *repositories.TransactionRepository,
+	transactionService *TransactionService,
+) *TemplateService {
+	return &TemplateService{
+		repo:               repo,
+		accountRepo:        accountRepo,
+		categoryRepo:       categoryRepo,
+		transactionRepo:    transactionRepo,
+		transactionService: transactionService,
+	}
+}
+
+// CreateTemplate creates a new transaction template
+func (s *TemplateService) CreateTemplate(workspaceID, userID string, req models.CreateTransactionTemplateRequest) (*models.TransactionTemplate, error) {
+	if err := s.validateTemplate(workspaceID, req.AccountID, req.CategoryID, req.Type); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(workspaceID, userID, req)
+}
+
+// GetTemplate retrieves a transaction template by ID
+func (s *TemplateService) GetTemplate(id, workspaceID string) (*models.TransactionTemplate, error) {
+	template, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, fmt.Errorf("template not found")
+	}
+	return template, nil
+}
+
+// GetAllTemplates retrieves the templates of a workspace ranked by favorite and usage
+func (s *TemplateService) GetAllTemplates(workspaceID string) ([]models.TransactionTemplate, error) {
+	return s.repo.GetAll(workspaceID)
+}
+
+// UpdateTemplate updates a transaction template
+func (s *TemplateService) UpdateTemplate(id, workspaceID string, req models.UpdateTransactionTemplateRequest) (*models.TransactionTemplate, error) {
+	existing, err := s.GetTemplate(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the template as it will be after the update
+	accountID := existing.AccountID
+	if req.AccountID != nil {
+		accountID = *req.AccountID
+	}
+	categoryID := existing.CategoryID
+	if req.CategoryID != nil {
+		categoryID = req.CategoryID
+		if *req.CategoryID == "" {
+			categoryID = nil
+		}
+	}
+	transactionType := existing.Type
+	if req.Type != nil {
+		transactionType = *req.Type
+	}
+	if err := s.validateTemplate(workspaceID, accountID, categoryID, transactionType); err != nil {
+		return nil, err
+	}
+
+	template, err := s.repo.Update(id, workspaceID, req)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, fmt.Errorf("template not found")
+	}
+
+	return template, nil
+}
+
+// DeleteTemplate deletes a transaction template
+func (s *TemplateService) DeleteTemplate(id, workspaceID string) error {
+	if _, err := s.GetTemplate(id, workspaceID); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(id, workspaceID)
+}
+
+// CreateFromTemplate creates a transaction from a template, with any fields of the request overriding the template
+func (s *TemplateService) CreateFromTemplate(id, workspaceID, userID string, req models.CreateFromTemplateRequest) (*models.Transaction, error) {
+	template, err := s.GetTemplate(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionReq := models.CreateTransactionRequest{
+		AccountID:       template.AccountID,
+		CategoryID:      template.CategoryID,
+		Type:            template.Type,
+		Merchant:        template.Merchant,
+		Description:     template.Description,
+		Tags:            template.Tags,
+		Notes:           template.Notes,
+		TransactionDate: time.Now(),
+		Status:          req.Status,
+	}
+
+	switch {
+	case req.Amount != nil:
+		transactionReq.Amount = *req.Amount
+	case template.Amount != nil:
+		transactionReq.Amount = *template.Amount
+	default:
+		return nil, fmt.Errorf("amount is required because the template has no amount")
+	}
+
+	if req.AccountID != nil {
+		transactionReq.AccountID = *req.AccountID
+	}
+	if req.CategoryID != nil {
+		transactionReq.CategoryID = req.CategoryID
+	}
+	if req.Merchant != nil {
+		transactionReq.Merchant = req.Merchant
+	}
+	if req.Description != nil {
+		transactionReq.Description = req.Description
+	}
+	if req.Tags != nil {
+		transactionReq.Tags = req.Tags
+	}
+	if req.Notes != nil {
+		transactionReq.Notes = req.Notes
+	}
+	if req.TransactionDate != nil {
+		transactionReq.TransactionDate = *req.TransactionDate
+	}
+
+	transaction, err := s.transactionService.CreateTransaction(workspaceID, userID, transactionReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RecordUsage(id, workspaceID); err != nil {
+		fmt.Printf("Warning: failed to record usage of template %s: %v\n", id, err)
+	}
+
+	return transaction, nil
+}
+
+// GetSuggestedTemplates mines the workspace's recent transactions for often repeated entries not yet saved as templates
+func (s *TemplateService) GetSuggestedTemplates(workspaceID string) ([]models.SuggestedTemplate, error) {
+	templates, err := s.repo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	covered := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		covered[templatePatternKey(template.AccountID, template.CategoryID, template.Type, template.Merchant)] = true
+	}
+
+	// Fetch extra patterns so there are enough left after dropping the covered ones
+	since := time.Now().Add(-templateSuggestionWindow)
+	patterns, err := s.transactionRepo.GetRepeatedPatterns(workspaceID, since, templateSuggestionMinCount, templateSuggestionLimit+len(templates))
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[string]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	suggestions := []models.SuggestedTemplate{}
+	for _, pattern := range patterns {
+		if covered[templatePatternKey(pattern.AccountID, pattern.CategoryID, pattern.Type, pattern.Merchant)] {
+			continue
+		}
+
+		name := pattern.Type
+		if pattern.Merchant != nil && *pattern.Merchant != "" {
+			name = *pattern.Merchant
+		} else if pattern.CategoryID != nil && categoryNames[*pattern.CategoryID] != "" {
+			name = categoryNames[*pattern.CategoryID]
+		}
+
+		suggestions = append(suggestions, models.SuggestedTemplate{
+			Name:        name,
+			AccountID:   pattern.AccountID,
+			CategoryID:  pattern.CategoryID,
+			Type:        pattern.Type,
+			Amount:      pattern.Amount,
+			Merchant:    pattern.Merchant,
+			Occurrences: pattern.Count,
+			LastUsedAt:  pattern.LastUsedAt,
+		})
+		if len(suggestions) == templateSuggestionLimit {
+			break
+		}
+	}
+
+	return suggestions, nil
+}
+
+// validateTemplate checks that a template's account exists and its category fits the transaction type
+func (s *TemplateService) validateTemplate(workspaceID, accountID string, categoryID *string, transactionType string) error {
+	account, err := s.accountRepo.GetByID(accountID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return fmt.Errorf("account not found")
+	}
+
+	if categoryID == nil || *categoryID == "" {
+		return nil
+	}
+
+	category, err := s.categoryRepo.GetByID(*categoryID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return fmt.Errorf("category not found")
+	}
+	if category.Type != transactionType && category.Type != "both" {
+		return fmt.Errorf("category type does not match transaction type")
+	}
+
+	return nil
+}
+
+// templatePatternKey identifies the kind of transaction a template or repeated pattern records
+func templatePatternKey(accountID string, categoryID *string, transactionType string, merchant *string) string {
+	category := ""
+	if categoryID != nil {
+		category = *categoryID
+	}
+	merchantName := ""
+	if merchant != nil {
+		merchantName = utils.NormalizeMerchantName(*merchant)
+	}
+	return accountID + "|" + category + "|" + transactionType + "|" + merchantName
+}
@@ -1,16 +1,28 @@
 package services
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"finance-hub-api/internal/models"
 	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 )
 
+// bulkEditLimit caps the number of transactions a single bulk edit can change
+const bulkEditLimit = 1000
+
 // TransactionService handles business logic for transactions
 type TransactionService struct {
 	repo        *repositories.TransactionRepository
 	accountRepo *repositories.AccountRepository
 	categoryRepo *repositories.CategoryRepository
+	payeeRepo    *repositories.PayeeRepository
+	auditService *AuditService
+	attachmentService *AttachmentService
 }
 
 // NewTransactionService creates a new transaction service
@@ -18,16 +30,22 @@ func NewTransactionService(
 	repo *repositories.TransactionRepository, 
 	accountRepo *repositories.AccountRepository,
 	categoryRepo *repositories.CategoryRepository,
+	payeeRepo *repositories.PayeeRepository,
+	auditService *AuditService,
+	attachmentService *AttachmentService,
 ) *TransactionService {
 	return &TransactionService{
 		repo:         repo,
 		accountRepo:  accountRepo,
 		categoryRepo: categoryRepo,
+		payeeRepo:    payeeRepo,
+		auditService: auditService,
+		attachmentService: attachmentService,
 	}
 }
 
 // CreateTransaction creates a new transaction and updates account balance(s)
-func (s *TransactionService) CreateTransaction(userID string, req models.CreateTransactionRequest) (*models.Transaction, error) {
+func (s *TransactionService) CreateTransaction(workspaceID, userID string, req models.CreateTransactionRequest) (*models.Transaction, error) {
 	// Validate transaction type
 	validTypes := map[string]bool{
 		"income":   true,
@@ -38,8 +56,8 @@ func (s *TransactionService) CreateTransaction(userID string, req models.CreateT
 		return nil, fmt.Errorf("invalid transaction type")
 	}
 
-	// Verify source account belongs to user
-	account, err := s.accountRepo.GetByID(req.AccountID, userID)
+	// Verify source account belongs to the workspace
+	account, err := s.accountRepo.GetByID(req.AccountID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +65,48 @@ func (s *TransactionService) CreateTransaction(userID string, req models.CreateT
 		return nil, fmt.Errorf("account not found")
 	}
 
+	// Link the merchant to a payee, whose default category fills in a missing category
+	payee, err := s.resolvePayee(workspaceID, req.PayeeID, req.Merchant)
+	if err != nil {
+		return nil, err
+	}
+	if payee != nil {
+		req.PayeeID = &payee.ID
+		if req.Merchant == nil || *req.Merchant == "" {
+			req.Merchant = &payee.Name
+		}
+		if req.Type != "transfer" && (req.CategoryID == nil || *req.CategoryID == "") && payee.DefaultCategoryID != nil {
+			category, err := s.categoryRepo.GetByID(*payee.DefaultCategoryID, workspaceID)
+			if err != nil {
+				return nil, err
+			}
+			if category != nil && (category.Type == req.Type || category.Type == "both") {
+				req.CategoryID = &category.ID
+			}
+		}
+	}
+
+	// A refund or reimbursement is income booked against the category of its original expense
+	if req.RefundOfID != nil && *req.RefundOfID == "" {
+		req.RefundOfID = nil
+	}
+	if req.RefundOfID != nil {
+		if req.Type != "income" {
+			return nil, fmt.Errorf("refunds and reimbursements must be income transactions")
+		}
+		original, err := s.checkRefund(workspaceID, "", *req.RefundOfID, req.Amount)
+		if err != nil {
+			return nil, err
+		}
+		req.CategoryID = original.CategoryID
+		req.RefundOfDate = original.TransactionDate
+		if req.RefundKind == "" {
+			req.RefundKind = models.RefundKindRefund
+		}
+	} else {
+		req.RefundKind = ""
+	}
+
 	// Validate transfer transaction
 	if req.Type == "transfer" {
 		if req.ToAccountID == nil || *req.ToAccountID == "" {
@@ -57,7 +117,7 @@ func (s *TransactionService) CreateTransaction(userID string, req models.CreateT
 		}
 
 		// Verify destination account
-		toAccount, err := s.accountRepo.GetByID(*req.ToAccountID, userID)
+		toAccount, err := s.accountRepo.GetByID(*req.ToAccountID, workspaceID)
 		if err != nil {
 			return nil, err
 		}
@@ -73,8 +133,8 @@ func (s *TransactionService) CreateTransaction(userID string, req models.CreateT
 			return nil, fmt.Errorf("category_id is required for income and expense transactions")
 		}
 
-		// Verify category exists and belongs to user
-		category, err := s.categoryRepo.GetByID(*req.CategoryID, userID)
+		// Verify category exists and belongs to the workspace
+		category, err := s.categoryRepo.GetByID(*req.CategoryID, workspaceID)
 		if err != nil {
 			return nil, err
 		}
@@ -83,52 +143,131 @@ func (s *TransactionService) CreateTransaction(userID string, req models.CreateT
 		}
 
 		// Validate category type matches transaction type
-		if category.Type != req.Type && category.Type != "both" {
+		if category.Type != req.Type && category.Type != "both" && req.RefundOfID == nil {
 			return nil, fmt.Errorf("category type does not match transaction type")
 		}
 	}
 
 	// Check if account has sufficient balance for expenses and transfers
-	if (req.Type == "expense" || req.Type == "transfer") && account.Balance < req.Amount {
+	// Imported and scheduled entries record money the bank has already moved, so they are not held back by the tracked balance,
+	// and credit accounts spend against their limit rather than a balance.
+	if (req.Type == "expense" || req.Type == "transfer") && account.Type != "credit" && req.Source != models.AuditSourceImport && req.Source != models.AuditSourceRecurring && account.Balance < req.Amount {
 		return nil, fmt.Errorf("insufficient balance in account %s", account.Name)
 	}
 
+	if err := s.attachmentService.ValidateUploads(workspaceID, req.AttachmentIDs); err != nil {
+		return nil, err
+	}
+
 	// Create transaction
-	transaction, err := s.repo.Create(userID, req)
+	transaction, err := s.repo.Create(workspaceID, userID, req)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(req.AttachmentIDs) > 0 {
+		if err := s.attachmentService.LinkUploads(workspaceID, transaction.ID, req.AttachmentIDs); err != nil {
+			return nil, fmt.Errorf("failed to link attachments: %v", err)
+		}
+	}
+
 	// Update account balance(s)
-	if err := s.updateAccountBalances(userID, transaction, nil); err != nil {
+	if err := s.updateAccountBalances(workspaceID, transaction, nil); err != nil {
 		// If balance update fails, we should ideally rollback the transaction
 		// For now, we'll return the error
 		return nil, fmt.Errorf("failed to update account balance: %v", err)
 	}
 
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityTransaction,
+		EntityID:    transaction.ID,
+		Action:      models.AuditActionCreate,
+		ActorID:     userID,
+		Source:      req.Source,
+	}, nil, transaction)
+
 	return transaction, nil
 }
 
+// ParseTransaction reads a free-text line into a draft transaction without saving it
+// Accounts and categories are matched against the workspace's own data
+func (s *TransactionService) ParseTransaction(workspaceID string, req models.ParseTransactionRequest) (*models.ParsedTransaction, error) {
+	location := utils.VietnamLocation()
+	if req.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", req.Timezone)
+		}
+	}
+
+	accounts, err := s.accountRepo.GetAllInWorkspace(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	activeAccounts := make([]models.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if account.IsActive {
+			activeAccounts = append(activeAccounts, account)
+		}
+	}
+
+	categories, err := s.categoryRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseQuickAdd(req.Text, time.Now().In(location), activeAccounts, categories), nil
+}
+
 // GetTransaction retrieves a transaction by ID
-func (s *TransactionService) GetTransaction(id, userID string) (*models.Transaction, error) {
-	transaction, err := s.repo.GetByID(id, userID)
+func (s *TransactionService) GetTransaction(id, workspaceID string) (*models.Transaction, error) {
+	transaction, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 	if transaction == nil {
 		return nil, fmt.Errorf("transaction not found")
 	}
-	return transaction, nil
+
+	transactions := []models.Transaction{*transaction}
+	if err := s.attachRefunds(workspaceID, transactions); err != nil {
+		return nil, err
+	}
+	if err := s.attachmentService.AttachFiles(workspaceID, transactions); err != nil {
+		return nil, err
+	}
+	if transactions[0].Attachments, err = s.attachmentService.GetAttachments(workspaceID, id); err != nil {
+		return nil, err
+	}
+	return &transactions[0], nil
 }
 
-// GetAllTransactions retrieves all transactions for a user with filters
-func (s *TransactionService) GetAllTransactions(userID string, filters models.TransactionFilterQuery) (*models.PaginatedResponse, error) {
+// GetAllTransactions retrieves all transactions in a workspace with filters
+func (s *TransactionService) GetAllTransactions(workspaceID string, filters models.TransactionFilterQuery) (*models.PaginatedResponse, error) {
 	filters.SetDefaults()
-	
-	transactions, totalCount, err := s.repo.GetAll(userID, filters)
+
+	if filters.Search != "" && filters.SearchTerms == nil {
+		terms, err := utils.ParseSearchQuery(filters.Search)
+		if err != nil {
+			return nil, err
+		}
+		filters.SearchTerms = terms
+	}
+	if err := s.resolveSearchTerms(workspaceID, filters.SearchTerms); err != nil {
+		return nil, err
+	}
+
+	transactions, totalCount, err := s.repo.GetAll(workspaceID, filters)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.attachRefunds(workspaceID, transactions); err != nil {
+		return nil, err
+	}
+	if err := s.attachmentService.AttachFiles(workspaceID, transactions); err != nil {
+		return nil, err
+	}
 
 	totalPages := (totalCount + filters.Limit - 1) / filters.Limit
 
@@ -141,16 +280,133 @@ func (s *TransactionService) GetAllTransactions(userID string, filters models.Tr
 	}, nil
 }
 
+// GetTransactionsByCursor retrieves a page of transactions after an opaque cursor
+// Unlike page/limit it never skips rows, so deep pages stay fast and inserts cannot cause duplicates or gaps
+func (s *TransactionService) GetTransactionsByCursor(workspaceID string, filters models.TransactionFilterQuery) (*models.CursorPaginatedResponse, error) {
+	filters.SetDefaults()
+
+	if filters.Search != "" && filters.SearchTerms == nil {
+		terms, err := utils.ParseSearchQuery(filters.Search)
+		if err != nil {
+			return nil, err
+		}
+		filters.SearchTerms = terms
+	}
+	if err := s.resolveSearchTerms(workspaceID, filters.SearchTerms); err != nil {
+		return nil, err
+	}
+
+	sortBy, sortOrder := cursorSortKey(filters)
+
+	var after *models.TransactionCursor
+	if filters.Cursor != "" {
+		decoded, err := decodeTransactionCursor(filters.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if decoded.SortBy != sortBy || decoded.SortOrder != sortOrder {
+			return nil, fmt.Errorf("cursor does not match the requested sort order")
+		}
+		after = decoded
+	}
+
+	// Fetch one extra row to learn whether another page exists
+	transactions, err := s.repo.GetPage(workspaceID, filters, after, filters.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CursorPaginatedResponse{Limit: filters.Limit}
+	if len(transactions) > filters.Limit {
+		transactions = transactions[:filters.Limit]
+		result.HasMore = true
+
+		last := transactions[len(transactions)-1]
+		next := models.TransactionCursor{
+			SortBy:    sortBy,
+			SortOrder: sortOrder,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		}
+		if sortBy == "amount" {
+			next.Amount = &last.Amount
+		} else {
+			next.Date = &last.TransactionDate
+		}
+		encoded, err := encodeTransactionCursor(next)
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = &encoded
+	}
+	if err := s.attachRefunds(workspaceID, transactions); err != nil {
+		return nil, err
+	}
+	if err := s.attachmentService.AttachFiles(workspaceID, transactions); err != nil {
+		return nil, err
+	}
+	result.Data = transactions
+
+	if filters.IncludeTotal {
+		total, err := s.repo.Count(workspaceID, filters)
+		if err != nil {
+			return nil, err
+		}
+		result.TotalItems = &total
+	}
+
+	return result, nil
+}
+
 // UpdateTransaction updates a transaction and adjusts account balance(s)
-func (s *TransactionService) UpdateTransaction(id, userID string, req models.UpdateTransactionRequest) (*models.Transaction, error) {
+func (s *TransactionService) UpdateTransaction(id, workspaceID, actorID string, req models.UpdateTransactionRequest) (*models.Transaction, error) {
 	// Get existing transaction
-	existing, err := s.repo.GetByID(id, userID)
+	existing, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 	if existing == nil {
 		return nil, fmt.Errorf("transaction not found")
 	}
+	if err := checkUnlocked(existing); err != nil {
+		return nil, err
+	}
+	if err := checkNotLinked(existing); err != nil {
+		return nil, err
+	}
+
+	// Keep refunds and the expenses they point at consistent
+	var refunds []models.Transaction
+	if existing.RefundOfID != nil {
+		if req.Type != nil && *req.Type != "income" {
+			return nil, fmt.Errorf("refunds and reimbursements must stay income transactions")
+		}
+		if req.CategoryID != nil && (existing.CategoryID == nil || *req.CategoryID != *existing.CategoryID) {
+			if req.RevertedTo == nil {
+				return nil, fmt.Errorf("a refund keeps the category of its original expense")
+			}
+			// The original expense may have been recategorized since that version
+			req.CategoryID = nil
+		}
+		if req.Amount != nil {
+			if _, err := s.checkRefund(workspaceID, id, *existing.RefundOfID, *req.Amount); err != nil {
+				return nil, err
+			}
+		}
+	} else if existing.Type == "expense" {
+		if refunds, err = s.repo.GetRefunds(workspaceID, []string{id}); err != nil {
+			return nil, err
+		}
+		if len(refunds) > 0 {
+			if req.Type != nil && *req.Type != "expense" {
+				return nil, fmt.Errorf("transaction has refunds and must stay an expense")
+			}
+			refunded := refundedAmount(refunds)
+			if req.Amount != nil && utils.RoundToTwoDecimals(*req.Amount) < refunded {
+				return nil, fmt.Errorf("amount cannot be less than the %.2f already refunded", refunded)
+			}
+		}
+	}
 
 	// If type is being changed, validate it
 	if req.Type != nil {
@@ -176,7 +432,7 @@ func (s *TransactionService) UpdateTransaction(id, userID string, req models.Upd
 
 	// Validate account if being changed
 	if req.AccountID != nil {
-		account, err := s.accountRepo.GetByID(*req.AccountID, userID)
+		account, err := s.accountRepo.GetByID(*req.AccountID, workspaceID)
 		if err != nil {
 			return nil, err
 		}
@@ -187,7 +443,7 @@ func (s *TransactionService) UpdateTransaction(id, userID string, req models.Upd
 
 	// Validate destination account if being changed
 	if req.ToAccountID != nil && *req.ToAccountID != "" {
-		toAccount, err := s.accountRepo.GetByID(*req.ToAccountID, userID)
+		toAccount, err := s.accountRepo.GetByID(*req.ToAccountID, workspaceID)
 		if err != nil {
 			return nil, err
 		}
@@ -198,7 +454,7 @@ func (s *TransactionService) UpdateTransaction(id, userID string, req models.Upd
 
 	// Validate category if being changed
 	if req.CategoryID != nil && *req.CategoryID != "" {
-		category, err := s.categoryRepo.GetByID(*req.CategoryID, userID)
+		category, err := s.categoryRepo.GetByID(*req.CategoryID, workspaceID)
 		if err != nil {
 			return nil, err
 		}
@@ -207,59 +463,239 @@ func (s *TransactionService) UpdateTransaction(id, userID string, req models.Upd
 		}
 	}
 
+	// Validate payee if being changed, or relink it when only the merchant changes
+	if req.PayeeID != nil && *req.PayeeID != "" {
+		payee, err := s.payeeRepo.GetByID(*req.PayeeID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if payee == nil {
+			if req.RevertedTo == nil {
+				return nil, fmt.Errorf("payee not found")
+			}
+			// The payee was deleted since that version; restore the rest without the link
+			unlinked := ""
+			req.PayeeID = &unlinked
+		}
+	} else if req.PayeeID == nil && req.Merchant != nil {
+		payee, err := s.matchPayee(workspaceID, *req.Merchant)
+		if err != nil {
+			return nil, err
+		}
+		unlinked := ""
+		req.PayeeID = &unlinked
+		if payee != nil {
+			req.PayeeID = &payee.ID
+		}
+	}
+
 	// Revert old balance changes
-	if err := s.revertAccountBalances(userID, existing); err != nil {
+	if err := s.revertAccountBalances(workspaceID, existing); err != nil {
 		return nil, fmt.Errorf("failed to revert account balance: %v", err)
 	}
 
 	// Update transaction
-	updated, err := s.repo.Update(id, userID, req)
+	updated, err := s.repo.Update(id, workspaceID, req)
 	if err != nil {
 		// If update fails, try to restore the old balance
-		_ = s.updateAccountBalances(userID, existing, nil)
+		_ = s.updateAccountBalances(workspaceID, existing, nil)
 		return nil, err
 	}
 
 	// Apply new balance changes
-	if err := s.updateAccountBalances(userID, updated, nil); err != nil {
+	if err := s.updateAccountBalances(workspaceID, updated, nil); err != nil {
 		// This is a critical error - transaction is updated but balance is not
 		return nil, fmt.Errorf("transaction updated but failed to update account balance: %v", err)
 	}
 
+	action := models.AuditActionUpdate
+	if req.RevertedTo != nil {
+		action = models.AuditActionRevert
+	}
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityTransaction,
+		EntityID:    id,
+		Action:      action,
+		ActorID:     actorID,
+		Source:      req.Source,
+		RevertedTo:  req.RevertedTo,
+	}, existing, updated)
+
+	// Refunds follow their expense's category and date
+	if len(refunds) > 0 {
+		if _, err := s.repo.SyncRefunds(workspaceID, id, updated.CategoryID, updated.TransactionDate); err != nil {
+			fmt.Printf("Warning: failed to update refunds of transaction %s: %v\n", id, err)
+		}
+	}
+
 	return updated, nil
 }
 
-// DeleteTransaction deletes a transaction and reverts account balance changes
-func (s *TransactionService) DeleteTransaction(id, userID string) error {
+// UnlockTransaction returns a reconciled transaction to cleared so it can be edited again
+func (s *TransactionService) UnlockTransaction(id, workspaceID, actorID string) (*models.Transaction, error) {
+	existing, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+	if existing.Status != models.TransactionStatusReconciled {
+		return nil, fmt.Errorf("transaction is not reconciled")
+	}
+
+	if _, err := s.repo.SetStatus(workspaceID, []string{id}, models.TransactionStatusCleared); err != nil {
+		return nil, err
+	}
+
+	unlocked, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityTransaction,
+		EntityID:    id,
+		Action:      models.AuditActionUpdate,
+		ActorID:     actorID,
+	}, existing, unlocked)
+
+	return unlocked, nil
+}
+
+// GetTransactionHistory retrieves the recorded versions of a transaction, newest first
+// The history outlives the transaction, so it is still available after a delete
+func (s *TransactionService) GetTransactionHistory(id, workspaceID string) ([]models.AuditEntry, error) {
+	entries, err := s.auditService.GetHistory(workspaceID, models.AuditEntityTransaction, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		// Transactions created before auditing have no history yet
+		existing, err := s.repo.GetByID(id, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("transaction not found")
+		}
+	}
+	return entries, nil
+}
+
+// RevertTransaction restores the fields of a transaction to a recorded version through UpdateTransaction,
+// so balances are adjusted and the revert itself is recorded as a new version
+func (s *TransactionService) RevertTransaction(id, workspaceID, actorID string, version int) (*models.Transaction, error) {
+	entry, err := s.auditService.GetVersion(workspaceID, models.AuditEntityTransaction, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Action == models.AuditActionDelete || entry.Action == models.AuditActionPurge {
+		return nil, fmt.Errorf("cannot revert to version %d because it records a delete", version)
+	}
+
+	var target models.Transaction
+	if err := s.auditService.DecodeSnapshot(entry, &target); err != nil {
+		return nil, fmt.Errorf("failed to read version %d: %v", version, err)
+	}
+
+	return s.UpdateTransaction(id, workspaceID, actorID, revertTransactionRequest(&target, version))
+}
+
+// DeleteTransaction moves a transaction to the trash and reverts account balance changes
+func (s *TransactionService) DeleteTransaction(id, workspaceID, actorID string) error {
 	// Check if transaction exists
-	existing, err := s.repo.GetByID(id, userID)
+	existing, err := s.repo.GetByID(id, workspaceID)
 	if err != nil {
 		return err
 	}
 	if existing == nil {
 		return fmt.Errorf("transaction not found")
 	}
+	if err := checkUnlocked(existing); err != nil {
+		return err
+	}
+	if err := checkNotLinked(existing); err != nil {
+		return err
+	}
+	if err := s.checkNoOpenRefunds(workspaceID, existing, nil); err != nil {
+		return err
+	}
 
 	// Revert account balance changes
-	if err := s.revertAccountBalances(userID, existing); err != nil {
+	if err := s.revertAccountBalances(workspaceID, existing); err != nil {
 		return fmt.Errorf("failed to revert account balance: %v", err)
 	}
 
 	// Delete transaction
-	_, err = s.repo.Delete(id, userID)
+	_, err = s.repo.Delete(id, workspaceID)
 	if err != nil {
 		// If delete fails, try to restore the balance
-		_ = s.updateAccountBalances(userID, existing, nil)
+		_ = s.updateAccountBalances(workspaceID, existing, nil)
 		return err
 	}
 
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityTransaction,
+		EntityID:    id,
+		Action:      models.AuditActionDelete,
+		ActorID:     actorID,
+	}, existing, nil)
+
+	return nil
+}
+
+// ReverseTransaction removes a transaction that was created for another record, such as a goal
+// contribution, loan payment, investment trade or settlement, and reverts its balance changes.
+// It skips the trash: restoring the transaction on its own would move the balance again while the
+// record it belonged to is gone.
+func (s *TransactionService) ReverseTransaction(id, workspaceID, actorID string) error {
+	existing, err := s.repo.GetByID(id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("transaction not found")
+	}
+	if err := checkUnlocked(existing); err != nil {
+		return err
+	}
+	if err := s.checkNoOpenRefunds(workspaceID, existing, nil); err != nil {
+		return err
+	}
+
+	if err := s.revertAccountBalances(workspaceID, existing); err != nil {
+		return fmt.Errorf("failed to revert account balance: %v", err)
+	}
+
+	if err := s.repo.DeleteImmediately(id, workspaceID); err != nil {
+		// If delete fails, try to restore the balance
+		_ = s.updateAccountBalances(workspaceID, existing, nil)
+		return err
+	}
+
+	if err := s.attachmentService.DeleteForTransaction(workspaceID, id); err != nil {
+		fmt.Printf("Warning: failed to delete attachments of transaction %s: %v\n", id, err)
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityTransaction,
+		EntityID:    id,
+		Action:      models.AuditActionDelete,
+		ActorID:     actorID,
+	}, existing, nil)
+
 	return nil
 }
 
 // BulkUpdateCategory updates category for multiple transactions
-func (s *TransactionService) BulkUpdateCategory(userID string, req models.BulkUpdateCategoryRequest) (int64, error) {
+func (s *TransactionService) BulkUpdateCategory(workspaceID, actorID string, req models.BulkUpdateCategoryRequest) (int64, error) {
 	// Verify category exists
-	category, err := s.categoryRepo.GetByID(req.CategoryID, userID)
+	category, err := s.categoryRepo.GetByID(req.CategoryID, workspaceID)
 	if err != nil {
 		return 0, err
 	}
@@ -267,52 +703,512 @@ func (s *TransactionService) BulkUpdateCategory(userID string, req models.BulkUp
 		return 0, fmt.Errorf("category not found")
 	}
 
+	// Keep the current state of each transaction for the audit log
+	// Transfers have no category and refunds keep the one of their original expense, so both are left alone.
+	var transactionsToUpdate []*models.Transaction
+	var transactionIDs []string
+	for _, id := range req.TransactionIDs {
+		transaction, err := s.repo.GetByID(id, workspaceID)
+		if err != nil {
+			continue // Skip errors, continue with others
+		}
+		if transaction == nil || transaction.Type == "transfer" || transaction.RefundOfID != nil {
+			continue
+		}
+		if err := checkUnlocked(transaction); err != nil {
+			return 0, err
+		}
+		if err := checkNotLinked(transaction); err != nil {
+			return 0, err
+		}
+		if category.Type != transaction.Type && category.Type != "both" {
+			return 0, fmt.Errorf("category type does not match the type of transaction %s", transaction.ID)
+		}
+		transactionsToUpdate = append(transactionsToUpdate, transaction)
+		transactionIDs = append(transactionIDs, transaction.ID)
+	}
+	if len(transactionIDs) == 0 {
+		return 0, nil
+	}
+
 	// Perform bulk update
-	count, err := s.repo.BulkUpdateCategory(userID, req.TransactionIDs, req.CategoryID)
+	count, err := s.repo.BulkUpdateCategory(workspaceID, transactionIDs, req.CategoryID)
 	if err != nil {
 		return 0, err
 	}
 
+	for _, transaction := range transactionsToUpdate {
+		updated := *transaction
+		updated.CategoryID = &req.CategoryID
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityTransaction,
+			EntityID:    transaction.ID,
+			Action:      models.AuditActionUpdate,
+			ActorID:     actorID,
+		}, transaction, &updated)
+
+		if transaction.Type == "expense" {
+			if _, err := s.repo.SyncRefunds(workspaceID, transaction.ID, &req.CategoryID, transaction.TransactionDate); err != nil {
+				fmt.Printf("Warning: failed to update refunds of transaction %s: %v\n", transaction.ID, err)
+			}
+		}
+	}
+
 	return count, nil
 }
 
-// BulkDelete deletes multiple transactions and reverts their balance changes
-func (s *TransactionService) BulkDelete(userID string, req models.BulkDeleteRequest) (int64, error) {
+// BulkDelete moves multiple transactions to the trash and reverts their balance changes
+func (s *TransactionService) BulkDelete(workspaceID, actorID string, req models.BulkDeleteRequest) (int64, error) {
 	// Get all transactions to be deleted
 	var transactionsToDelete []*models.Transaction
+	deleting := make(map[string]bool, len(req.TransactionIDs))
+	for _, id := range req.TransactionIDs {
+		deleting[id] = true
+	}
 	for _, id := range req.TransactionIDs {
-		transaction, err := s.repo.GetByID(id, userID)
+		transaction, err := s.repo.GetByID(id, workspaceID)
 		if err != nil {
 			continue // Skip errors, continue with others
 		}
 		if transaction != nil {
+			if err := checkUnlocked(transaction); err != nil {
+				return 0, err
+			}
+			if err := checkNotLinked(transaction); err != nil {
+				return 0, err
+			}
+			if err := s.checkNoOpenRefunds(workspaceID, transaction, deleting); err != nil {
+				return 0, err
+			}
 			transactionsToDelete = append(transactionsToDelete, transaction)
 		}
 	}
 
 	// Revert balance changes for all transactions
 	for _, transaction := range transactionsToDelete {
-		if err := s.revertAccountBalances(userID, transaction); err != nil {
+		if err := s.revertAccountBalances(workspaceID, transaction); err != nil {
 			// Log error but continue
 			fmt.Printf("Warning: failed to revert balance for transaction %s: %v\n", transaction.ID, err)
 		}
 	}
 
 	// Perform bulk delete
-	count, err := s.repo.BulkDelete(userID, req.TransactionIDs)
+	count, err := s.repo.BulkDelete(workspaceID, req.TransactionIDs)
 	if err != nil {
 		// If delete fails, try to restore all balances
 		for _, transaction := range transactionsToDelete {
-			_ = s.updateAccountBalances(userID, transaction, nil)
+			_ = s.updateAccountBalances(workspaceID, transaction, nil)
 		}
 		return 0, err
 	}
 
+	for _, transaction := range transactionsToDelete {
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: workspaceID,
+			EntityType:  models.AuditEntityTransaction,
+			EntityID:    transaction.ID,
+			Action:      models.AuditActionDelete,
+			ActorID:     actorID,
+		}, transaction, nil)
+	}
+
 	return count, nil
 }
 
+// BulkEdit applies the same changes to the transactions picked by IDs or by list filters
+// Every transaction is validated on its own, so one that cannot take the changes is reported and skipped
+// instead of failing the whole request. A dry run only reports what would happen.
+func (s *TransactionService) BulkEdit(workspaceID, actorID string, filters models.TransactionFilterQuery, req models.BulkEditRequest) (*models.BulkEditResult, error) {
+	if req.CategoryID == nil && req.AccountID == nil && len(req.AddTags) == 0 && len(req.RemoveTags) == 0 &&
+		req.ShiftDays == 0 && req.Merchant == nil {
+		return nil, fmt.Errorf("no changes requested")
+	}
+
+	var category *models.Category
+	if req.CategoryID != nil {
+		var err error
+		if category, err = s.categoryRepo.GetByID(*req.CategoryID, workspaceID); err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, fmt.Errorf("category not found")
+		}
+	}
+
+	// Balances the accounts would have after the rows validated so far are moved
+	balances := map[string]float64{}
+	if req.AccountID != nil {
+		account, err := s.accountRepo.GetByID(*req.AccountID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if account == nil {
+			return nil, fmt.Errorf("account not found")
+		}
+		balances[account.ID] = account.Balance
+	}
+
+	result := &models.BulkEditResult{DryRun: req.DryRun, Failures: []models.BulkEditFailure{}}
+	fail := func(id string, err error) {
+		result.Failed++
+		result.Failures = append(result.Failures, models.BulkEditFailure{TransactionID: id, Error: err.Error()})
+	}
+
+	transactions, err := s.bulkEditTargets(workspaceID, filters, req.TransactionIDs, fail)
+	if err != nil {
+		return nil, err
+	}
+	result.Matched = len(transactions) + result.Failed
+
+	for i := range transactions {
+		transaction := &transactions[i]
+
+		update, err := s.bulkEditUpdate(transaction, category, req, balances)
+		if err != nil {
+			fail(transaction.ID, err)
+			continue
+		}
+		if update.CategoryID == nil && update.AccountID == nil && update.Tags == nil && update.TransactionDate == nil && update.Merchant == nil {
+			result.Unchanged++
+			continue
+		}
+
+		if !req.DryRun {
+			if _, err := s.UpdateTransaction(transaction.ID, workspaceID, actorID, update); err != nil {
+				fail(transaction.ID, err)
+				continue
+			}
+		}
+		result.Affected++
+	}
+
+	return result, nil
+}
+
+// bulkEditTargets loads the transactions a bulk edit applies to, reporting IDs that do not exist
+func (s *TransactionService) bulkEditTargets(workspaceID string, filters models.TransactionFilterQuery, ids []string, fail func(string, error)) ([]models.Transaction, error) {
+	if len(ids) > 0 {
+		if len(ids) > bulkEditLimit {
+			return nil, fmt.Errorf("at most %d transactions can be edited at once", bulkEditLimit)
+		}
+
+		transactions := []models.Transaction{}
+		seen := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			transaction, err := s.repo.GetByID(id, workspaceID)
+			if err != nil {
+				return nil, err
+			}
+			if transaction == nil {
+				fail(id, fmt.Errorf("transaction not found"))
+				continue
+			}
+			transactions = append(transactions, *transaction)
+		}
+		return transactions, nil
+	}
+
+	// Editing a whole workspace by accident is too easy with an empty filter
+	if !filters.HasConditions() {
+		return nil, fmt.Errorf("transaction_ids or at least one filter is required")
+	}
+
+	if filters.Search != "" && filters.SearchTerms == nil {
+		terms, err := utils.ParseSearchQuery(filters.Search)
+		if err != nil {
+			return nil, err
+		}
+		filters.SearchTerms = terms
+	}
+	if err := s.resolveSearchTerms(workspaceID, filters.SearchTerms); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.Count(workspaceID, filters)
+	if err != nil {
+		return nil, err
+	}
+	if count > bulkEditLimit {
+		return nil, fmt.Errorf("filter matches %d transactions; narrow it down to at most %d", count, bulkEditLimit)
+	}
+
+	return s.repo.GetMatching(workspaceID, filters, bulkEditLimit)
+}
+
+// bulkEditUpdate validates a bulk edit against one transaction and builds the update to apply to it
+// A transaction moved to another account is counted against that account's projected balance.
+func (s *TransactionService) bulkEditUpdate(transaction *models.Transaction, category *models.Category, req models.BulkEditRequest, balances map[string]float64) (models.UpdateTransactionRequest, error) {
+	update := models.UpdateTransactionRequest{}
+
+	if err := checkUnlocked(transaction); err != nil {
+		return update, err
+	}
+	if err := checkNotLinked(transaction); err != nil {
+		return update, err
+	}
+
+	if category != nil && (transaction.CategoryID == nil || *transaction.CategoryID != category.ID) {
+		if transaction.Type == "transfer" {
+			return update, fmt.Errorf("transfers have no category")
+		}
+		if transaction.RefundOfID != nil {
+			return update, fmt.Errorf("a refund keeps the category of its original expense")
+		}
+		if category.Type != transaction.Type && category.Type != "both" {
+			return update, fmt.Errorf("category type does not match transaction type")
+		}
+		update.CategoryID = &category.ID
+	}
+
+	if req.AccountID != nil && *req.AccountID != transaction.AccountID {
+		if transaction.ToAccountID != nil && *transaction.ToAccountID == *req.AccountID {
+			return update, fmt.Errorf("source and destination accounts cannot be the same")
+		}
+		if transaction.Type == "expense" || transaction.Type == "transfer" {
+			if balances[*req.AccountID] < transaction.Amount {
+				return update, fmt.Errorf("insufficient balance in the new account")
+			}
+			balances[*req.AccountID] -= transaction.Amount
+		} else {
+			balances[*req.AccountID] += transaction.Amount
+		}
+		update.AccountID = req.AccountID
+	}
+
+	if len(req.AddTags) > 0 || len(req.RemoveTags) > 0 {
+		tags := editTags(transaction.Tags, req.AddTags, req.RemoveTags)
+		if !slices.Equal(tags, transaction.Tags) {
+			update.Tags = tags
+		}
+	}
+
+	if req.ShiftDays != 0 {
+		date := transaction.TransactionDate.AddDate(0, 0, req.ShiftDays)
+		update.TransactionDate = &date
+	}
+
+	if req.Merchant != nil {
+		current := ""
+		if transaction.Merchant != nil {
+			current = *transaction.Merchant
+		}
+		if current != *req.Merchant {
+			update.Merchant = req.Merchant
+		}
+	}
+
+	return update, nil
+}
+
+// editTags removes and then adds tags, keeping the existing order and skipping duplicates
+func editTags(tags, add, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+
+	result := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		if !removed[tag] && !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	for _, tag := range add {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// GetTrash retrieves the transactions in the trash
+func (s *TransactionService) GetTrash(workspaceID string, pagination models.PaginationQuery) (*models.PaginatedResponse, error) {
+	pagination.SetDefaults()
+
+	transactions, totalCount, err := s.repo.GetTrash(workspaceID, pagination)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachmentService.AttachFiles(workspaceID, transactions); err != nil {
+		return nil, err
+	}
+
+	totalPages := (totalCount + pagination.Limit - 1) / pagination.Limit
+
+	return &models.PaginatedResponse{
+		Data:       transactions,
+		Page:       pagination.Page,
+		Limit:      pagination.Limit,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// RestoreTransaction takes a transaction out of the trash and re-applies its balance changes
+func (s *TransactionService) RestoreTransaction(id, workspaceID, actorID string) (*models.Transaction, error) {
+	existing, err := s.repo.GetDeletedByID(id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("transaction not found in trash")
+	}
+
+	// A refund can only come back while its expense is there to take it
+	if existing.RefundOfID != nil {
+		if _, err := s.checkRefund(workspaceID, id, *existing.RefundOfID, existing.Amount); err != nil {
+			return nil, fmt.Errorf("cannot restore refund: %v", err)
+		}
+	}
+
+	// The accounts may have been removed while the transaction was in the trash
+	account, err := s.accountRepo.GetByID(existing.AccountID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("cannot restore transaction: account no longer exists")
+	}
+	if existing.Type == "transfer" && existing.ToAccountID != nil {
+		toAccount, err := s.accountRepo.GetByID(*existing.ToAccountID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if toAccount == nil {
+			return nil, fmt.Errorf("cannot restore transaction: destination account no longer exists")
+		}
+	}
+	if existing.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(*existing.CategoryID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, fmt.Errorf("cannot restore transaction: category no longer exists")
+		}
+	}
+
+	if err := s.updateAccountBalances(workspaceID, existing, nil); err != nil {
+		return nil, fmt.Errorf("failed to update account balance: %v", err)
+	}
+
+	restored, err := s.repo.Restore(id, workspaceID)
+	if err != nil || restored == nil {
+		// Undo the balance change so the trash and balances stay consistent
+		_ = s.revertAccountBalances(workspaceID, existing)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("transaction not found in trash")
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityTransaction,
+		EntityID:    id,
+		Action:      models.AuditActionRestore,
+		ActorID:     actorID,
+	}, nil, restored)
+
+	return restored, nil
+}
+
+// DeleteTransactionPermanently removes a transaction from the trash for good
+// Its balance changes were already reverted when it was moved to the trash
+func (s *TransactionService) DeleteTransactionPermanently(id, workspaceID, actorID string) error {
+	existing, err := s.repo.GetDeletedByID(id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("transaction not found in trash")
+	}
+
+	if err := s.repo.DeletePermanently(id, workspaceID); err != nil {
+		return err
+	}
+
+	// Anything left behind is picked up by the attachment garbage collector
+	if err := s.attachmentService.DeleteForTransaction(workspaceID, id); err != nil {
+		fmt.Printf("Warning: failed to delete attachments of transaction %s: %v\n", id, err)
+	}
+
+	s.auditService.Record(models.AuditEntry{
+		WorkspaceID: workspaceID,
+		EntityType:  models.AuditEntityTransaction,
+		EntityID:    id,
+		Action:      models.AuditActionPurge,
+		ActorID:     actorID,
+	}, existing, nil)
+
+	return nil
+}
+
+// PurgeTrash permanently removes transactions that have been in the trash longer than the retention period
+// Attachments of the purged transactions are deleted along with them, and every purge is recorded in the
+// transaction's history like one made from the trash.
+func (s *TransactionService) PurgeTrash(retention time.Duration) (int64, error) {
+	transactions, err := s.repo.GetDeletedBefore(time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for i := range transactions {
+		transaction := &transactions[i]
+		if err := s.repo.DeletePermanently(transaction.ID, transaction.WorkspaceID); err != nil {
+			fmt.Printf("Warning: failed to purge transaction %s: %v\n", transaction.ID, err)
+			continue
+		}
+		count++
+
+		s.auditService.Record(models.AuditEntry{
+			WorkspaceID: transaction.WorkspaceID,
+			EntityType:  models.AuditEntityTransaction,
+			EntityID:    transaction.ID,
+			Action:      models.AuditActionPurge,
+			Source:      models.AuditSourceRetention,
+		}, transaction, nil)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+
+	if _, err := s.attachmentService.DeleteOrphaned(); err != nil {
+		fmt.Printf("Warning: failed to delete attachments of purged transactions: %v\n", err)
+	}
+
+	return count, nil
+}
+
+// RunTrashPurge purges expired transactions from the trash now and then on every interval
+// It blocks, so callers run it in its own goroutine
+func (s *TransactionService) RunTrashPurge(retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.PurgeTrash(retention); err != nil {
+			fmt.Printf("Warning: failed to purge transaction trash: %v\n", err)
+		} else if count > 0 {
+			fmt.Printf("Purged %d transactions from the trash\n", count)
+		}
+		<-ticker.C
+	}
+}
+
 // GetRecentTransactions retrieves recent transactions
-func (s *TransactionService) GetRecentTransactions(userID string, limit int) ([]models.Transaction, error) {
+func (s *TransactionService) GetRecentTransactions(workspaceID string, limit int) ([]models.Transaction, error) {
 	if limit <= 0 {
 		limit = 5
 	}
@@ -320,37 +1216,44 @@ func (s *TransactionService) GetRecentTransactions(userID string, limit int) ([]
 		limit = 50
 	}
 	
-	return s.repo.GetRecentTransactions(userID, limit)
+	transactions, err := s.repo.GetRecentTransactions(workspaceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachmentService.AttachFiles(workspaceID, transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
 }
 
 // GetTransactionSummary retrieves transaction summary statistics
-func (s *TransactionService) GetTransactionSummary(userID string, filters models.TransactionFilterQuery) (*models.TransactionSummary, error) {
-	return s.repo.GetSummary(userID, filters)
+func (s *TransactionService) GetTransactionSummary(workspaceID string, filters models.TransactionFilterQuery) (*models.TransactionSummary, error) {
+	return s.repo.GetSummary(workspaceID, filters)
 }
 
 // Helper function to update account balances based on transaction
-func (s *TransactionService) updateAccountBalances(userID string, transaction *models.Transaction, previousTransaction *models.Transaction) error {
+func (s *TransactionService) updateAccountBalances(workspaceID string, transaction *models.Transaction, previousTransaction *models.Transaction) error {
 	switch transaction.Type {
 	case "income":
 		// Add to account balance
-		return s.accountRepo.UpdateBalance(transaction.AccountID, userID, transaction.Amount)
+		return s.accountRepo.UpdateBalance(transaction.AccountID, workspaceID, transaction.Amount)
 	
 	case "expense":
 		// Subtract from account balance
-		return s.accountRepo.UpdateBalance(transaction.AccountID, userID, -transaction.Amount)
+		return s.accountRepo.UpdateBalance(transaction.AccountID, workspaceID, -transaction.Amount)
 	
 	case "transfer":
 		if transaction.ToAccountID == nil {
 			return fmt.Errorf("to_account_id is required for transfer")
 		}
 		// Subtract from source account
-		if err := s.accountRepo.UpdateBalance(transaction.AccountID, userID, -transaction.Amount); err != nil {
+		if err := s.accountRepo.UpdateBalance(transaction.AccountID, workspaceID, -transaction.Amount); err != nil {
 			return err
 		}
 		// Add to destination account
-		if err := s.accountRepo.UpdateBalance(*transaction.ToAccountID, userID, transaction.Amount); err != nil {
+		if err := s.accountRepo.UpdateBalance(*transaction.ToAccountID, workspaceID, transaction.Amount); err != nil {
 			// Rollback source account change
-			_ = s.accountRepo.UpdateBalance(transaction.AccountID, userID, transaction.Amount)
+			_ = s.accountRepo.UpdateBalance(transaction.AccountID, workspaceID, transaction.Amount)
 			return err
 		}
 	}
@@ -359,31 +1262,294 @@ func (s *TransactionService) updateAccountBalances(userID string, transaction *m
 }
 
 // Helper function to revert account balance changes
-func (s *TransactionService) revertAccountBalances(userID string, transaction *models.Transaction) error {
+func (s *TransactionService) revertAccountBalances(workspaceID string, transaction *models.Transaction) error {
 	switch transaction.Type {
 	case "income":
 		// Subtract what was added
-		return s.accountRepo.UpdateBalance(transaction.AccountID, userID, -transaction.Amount)
+		return s.accountRepo.UpdateBalance(transaction.AccountID, workspaceID, -transaction.Amount)
 	
 	case "expense":
 		// Add back what was subtracted
-		return s.accountRepo.UpdateBalance(transaction.AccountID, userID, transaction.Amount)
+		return s.accountRepo.UpdateBalance(transaction.AccountID, workspaceID, transaction.Amount)
 	
 	case "transfer":
 		if transaction.ToAccountID == nil {
 			return fmt.Errorf("to_account_id is required for transfer")
 		}
 		// Add back to source account
-		if err := s.accountRepo.UpdateBalance(transaction.AccountID, userID, transaction.Amount); err != nil {
+		if err := s.accountRepo.UpdateBalance(transaction.AccountID, workspaceID, transaction.Amount); err != nil {
 			return err
 		}
 		// Subtract from destination account
-		if err := s.accountRepo.UpdateBalance(*transaction.ToAccountID, userID, -transaction.Amount); err != nil {
+		if err := s.accountRepo.UpdateBalance(*transaction.ToAccountID, workspaceID, -transaction.Amount); err != nil {
 			// Rollback source account change
-			_ = s.accountRepo.UpdateBalance(transaction.AccountID, userID, -transaction.Amount)
+			_ = s.accountRepo.UpdateBalance(transaction.AccountID, workspaceID, -transaction.Amount)
 			return err
 		}
 	}
 	
 	return nil
 }
+
+// checkUnlocked rejects changes to transactions locked by a finished reconciliation
+func checkUnlocked(transaction *models.Transaction) error {
+	if transaction.Status == models.TransactionStatusReconciled {
+		return fmt.Errorf("transaction %s is reconciled; unlock it before making changes", transaction.ID)
+	}
+	return nil
+}
+
+// checkNotLinked rejects changes to a transaction that a goal contribution, loan payment, settlement or
+// investment transaction created, as that record would no longer match it
+func checkNotLinked(transaction *models.Transaction) error {
+	if transaction.LinkedTo != nil {
+		record := strings.ReplaceAll(transaction.LinkedTo.Type, "_", " ")
+		return fmt.Errorf("transaction %s belongs to %s %s; change or delete it through that %s", transaction.ID, record, transaction.LinkedTo.ID, record)
+	}
+	return nil
+}
+
+// checkRefund validates a refund amount against its original expense, ignoring the refund being edited
+func (s *TransactionService) checkRefund(workspaceID, refundID, originalID string, amount float64) (*models.Transaction, error) {
+	original, err := s.repo.GetByID(originalID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, fmt.Errorf("original transaction not found")
+	}
+	if original.Type != "expense" {
+		return nil, fmt.Errorf("only expenses can be refunded or reimbursed")
+	}
+
+	refunds, err := s.repo.GetRefunds(workspaceID, []string{originalID})
+	if err != nil {
+		return nil, err
+	}
+	var others []models.Transaction
+	for _, refund := range refunds {
+		if refund.ID != refundID {
+			others = append(others, refund)
+		}
+	}
+
+	remaining := utils.RoundToTwoDecimals(original.Amount - refundedAmount(others))
+	if utils.RoundToTwoDecimals(amount) > remaining {
+		return nil, fmt.Errorf("amount exceeds the %.2f left to refund on the original expense", remaining)
+	}
+
+	return original, nil
+}
+
+// checkNoOpenRefunds rejects removing an expense while refunds other than the ones being removed with it point at it
+func (s *TransactionService) checkNoOpenRefunds(workspaceID string, transaction *models.Transaction, removing map[string]bool) error {
+	if transaction.Type != "expense" {
+		return nil
+	}
+
+	refunds, err := s.repo.GetRefunds(workspaceID, []string{transaction.ID})
+	if err != nil {
+		return err
+	}
+	for _, refund := range refunds {
+		if !removing[refund.ID] {
+			return fmt.Errorf("transaction %s has refunds; delete them first", transaction.ID)
+		}
+	}
+
+	return nil
+}
+
+// attachRefunds lists on each expense the refunds and reimbursements that point at it
+func (s *TransactionService) attachRefunds(workspaceID string, transactions []models.Transaction) error {
+	var expenseIDs []string
+	for _, transaction := range transactions {
+		if transaction.Type == "expense" {
+			expenseIDs = append(expenseIDs, transaction.ID)
+		}
+	}
+	if len(expenseIDs) == 0 {
+		return nil
+	}
+
+	refunds, err := s.repo.GetRefunds(workspaceID, expenseIDs)
+	if err != nil {
+		return err
+	}
+
+	byOriginal := make(map[string][]models.Transaction)
+	for _, refund := range refunds {
+		byOriginal[*refund.RefundOfID] = append(byOriginal[*refund.RefundOfID], refund)
+	}
+
+	for i := range transactions {
+		for _, refund := range byOriginal[transactions[i].ID] {
+			transactions[i].Refunds = append(transactions[i].Refunds, models.RefundLink{
+				TransactionID:   refund.ID,
+				Kind:            refund.RefundKind,
+				Amount:          refund.Amount,
+				TransactionDate: refund.TransactionDate,
+			})
+		}
+		transactions[i].RefundedAmount = refundedAmount(byOriginal[transactions[i].ID])
+	}
+
+	return nil
+}
+
+// refundedAmount totals a list of refunds
+func refundedAmount(refunds []models.Transaction) float64 {
+	var total float64
+	for _, refund := range refunds {
+		total += refund.Amount
+	}
+	return utils.RoundToTwoDecimals(total)
+}
+
+// resolvePayee returns the payee a new transaction names, or the one its merchant text matches
+func (s *TransactionService) resolvePayee(workspaceID string, payeeID, merchant *string) (*models.Payee, error) {
+	if payeeID != nil && *payeeID != "" {
+		payee, err := s.payeeRepo.GetByID(*payeeID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if payee == nil {
+			return nil, fmt.Errorf("payee not found")
+		}
+		return payee, nil
+	}
+
+	if merchant == nil {
+		return nil, nil
+	}
+	return s.matchPayee(workspaceID, *merchant)
+}
+
+// matchPayee finds the workspace payee a merchant text belongs to
+func (s *TransactionService) matchPayee(workspaceID, merchant string) (*models.Payee, error) {
+	if strings.TrimSpace(merchant) == "" {
+		return nil, nil
+	}
+
+	payees, err := s.payeeRepo.GetAll(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.MatchPayee(merchant, payees), nil
+}
+
+// revertTransactionRequest builds an update that sets every field of a transaction to its value in target
+// Fields that were empty in target are sent as empty values so the update clears them
+func revertTransactionRequest(target *models.Transaction, version int) models.UpdateTransactionRequest {
+	optional := func(value *string) *string {
+		if value == nil {
+			empty := ""
+			return &empty
+		}
+		return value
+	}
+
+	tags := target.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return models.UpdateTransactionRequest{
+		AccountID:       &target.AccountID,
+		ToAccountID:     optional(target.ToAccountID),
+		CategoryID:      optional(target.CategoryID),
+		Type:            &target.Type,
+		Amount:          &target.Amount,
+		Merchant:        optional(target.Merchant),
+		PayeeID:         optional(target.PayeeID),
+		Description:     optional(target.Description),
+		TransactionDate: &target.TransactionDate,
+		Notes:           optional(target.Notes),
+		Tags:            tags,
+		AttachmentURL:   optional(target.AttachmentURL),
+		RevertedTo:      &version,
+	}
+}
+
+// cursorSortKey returns the normalized sort options a cursor is bound to
+func cursorSortKey(filters models.TransactionFilterQuery) (string, string) {
+	sortBy := "date"
+	if filters.SortBy == "amount" {
+		sortBy = "amount"
+	}
+	sortOrder := "desc"
+	if filters.SortOrder == "asc" {
+		sortOrder = "asc"
+	}
+	return sortBy, sortOrder
+}
+
+// encodeTransactionCursor encodes a cursor as an opaque URL-safe token
+func encodeTransactionCursor(cursor models.TransactionCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeTransactionCursor decodes and validates a cursor token
+func decodeTransactionCursor(token string) (*models.TransactionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor models.TransactionCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if (cursor.SortBy == "amount" && cursor.Amount == nil) || (cursor.SortBy != "amount" && cursor.Date == nil) {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// resolveSearchTerms fills in the IDs of the categories and accounts whose names match category: and account: terms
+// Names match when they contain the term, ignoring case and Vietnamese diacritics
+func (s *TransactionService) resolveSearchTerms(workspaceID string, terms []models.SearchTerm) error {
+	var categories []models.Category
+	var accounts []models.Account
+
+	for i := range terms {
+		term := &terms[i]
+		term.Values = []string{}
+
+		switch term.Field {
+		case models.SearchFieldCategory:
+			if categories == nil {
+				var err error
+				if categories, err = s.categoryRepo.GetAll(workspaceID); err != nil {
+					return err
+				}
+			}
+			for _, category := range categories {
+				if strings.Contains(utils.NormalizeSearchText(category.Name), term.Value) {
+					term.Values = append(term.Values, category.ID)
+				}
+			}
+
+		case models.SearchFieldAccount:
+			if accounts == nil {
+				var err error
+				if accounts, err = s.accountRepo.GetAllInWorkspace(workspaceID); err != nil {
+					return err
+				}
+			}
+			for _, account := range accounts {
+				if strings.Contains(utils.NormalizeSearchText(account.Name), term.Value) {
+					term.Values = append(term.Values, account.ID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"finance-hub-api/internal/config"
+	"finance-hub-api/internal/models"
+	"finance-hub-api/internal/repositories"
+	"finance-hub-api/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkspaceService handles business logic for workspaces and their members
+type WorkspaceService struct {
+	repo            *repositories.WorkspaceRepository
+	userRepo        *repositories.UserRepository
+	accountRepo     *repositories.AccountRepository
+	categoryRepo    *repositories.CategoryRepository
+	budgetRepo      *repositories.BudgetRepository
+	transactionRepo *repositories.TransactionRepository
+	emailService    *utils.EmailService
+}
+
+// NewWorkspaceService creates a new workspace service
+func NewWorkspaceService(
+	repo *repositories.WorkspaceRepository,
+	userRepo *repositories.UserRepository,
+	accountRepo *repositories.AccountRepository,
+	categoryRepo *repositories.CategoryRepository,
+	budgetRepo *repositories.BudgetRepository,
+	transactionRepo *repositories.TransactionRepository,
+	cfg *config.Config,
+) *WorkspaceService {
+	return &WorkspaceService{
+		repo:            repo,
+		userRepo:        userRepo,
+		accountRepo:     accountRepo,
+		categoryRepo:    categoryRepo,
+		budgetRepo:      budgetRepo,
+		transactionRepo: transactionRepo,
+		emailService:    utils.NewEmailService(cfg),
+	}
+}
+
+// ResolveRole returns the user's role in a workspace
+// The personal workspace is created on first use so existing users need no migration step
+func (s *WorkspaceService) ResolveRole(workspaceID, userID string) (string, error) {
+	if workspaceID == userID {
+		if _, err := s.repo.EnsurePersonal(userID); err != nil {
+			return "", err
+		}
+		return models.WorkspaceRoleOwner, nil
+	}
+
+	workspace, err := s.repo.GetByID(workspaceID)
+	if err != nil {
+		return "", err
+	}
+	if workspace == nil {
+		return "", fmt.Errorf("workspace not found")
+	}
+	if workspace.OwnerID == userID {
+		return models.WorkspaceRoleOwner, nil
+	}
+
+	member, err := s.repo.GetActiveMember(workspaceID, userID)
+	if err != nil {
+		return "", err
+	}
+	if member == nil {
+		// Do not reveal that the workspace exists to non-members
+		return "", fmt.Errorf("workspace not found")
+	}
+
+	return member.Role, nil
+}
+
+// CreateWorkspace creates a new shared workspace owned by the user
+func (s *WorkspaceService) CreateWorkspace(userID string, req models.CreateWorkspaceRequest) (*models.Workspace, error) {
+	return s.repo.Create(userID, req)
+}
+
+// GetWorkspaces retrieves every workspace the user owns or has joined
+func (s *WorkspaceService) GetWorkspaces(userID string) ([]models.WorkspaceWithRole, error) {
+	if _, err := s.repo.EnsurePersonal(userID); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.repo.GetActiveMemberships(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[string]string, len(memberships))
+	ids := make([]string, 0, len(memberships))
+	for _, membership := range memberships {
+		roles[membership.WorkspaceID] = membership.Role
+		ids = append(ids, membership.WorkspaceID)
+	}
+
+	workspaces, err := s.repo.GetAccessible(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.WorkspaceWithRole, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		role := roles[workspace.ID]
+		if workspace.OwnerID == userID {
+			role = models.WorkspaceRoleOwner
+		}
+		result = append(result, models.WorkspaceWithRole{Workspace: workspace, Role: role})
+	}
+
+	return result, nil
+}
+
+// GetWorkspace retrieves a workspace the user has access to
+func (s *WorkspaceService) GetWorkspace(id, userID string) (*models.WorkspaceWithRole, error) {
+	role, err := s.ResolveRole(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if workspace == nil {
+		return nil, fmt.Errorf("workspace not found")
+	}
+
+	return &models.WorkspaceWithRole{Workspace: *workspace, Role: role}, nil
+}
+
+// UpdateWorkspace renames a workspace (owner only)
+func (s *WorkspaceService) UpdateWorkspace(id, userID string, req models.UpdateWorkspaceRequest) (*models.Workspace, error) {
+	if err := s.requireOwner(id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(id, req)
+}
+
+// DeleteWorkspace deletes an empty shared workspace (owner only)
+func (s *WorkspaceService) DeleteWorkspace(id, userID string) error {
+	if err := s.requireOwner(id, userID); err != nil {
+		return err
+	}
+	if id == userID {
+		return fmt.Errorf("cannot delete your personal workspace")
+	}
+
+	_, accountCount, err := s.accountRepo.GetAll(id, models.PaginationQuery{Page: 1, Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to check workspace usage: %v", err)
+	}
+	categories, err := s.categoryRepo.GetAll(id)
+	if err != nil {
+		return fmt.Errorf("failed to check workspace usage: %v", err)
+	}
+	if accountCount > 0 || len(categories) > 0 {
+		return fmt.Errorf("cannot delete workspace that still has accounts or categories")
+	}
+
+	// Trashed transactions count too, they can still be restored into the workspace
+	budgetCount, err := s.budgetRepo.CountByWorkspace(id)
+	if err != nil {
+		return fmt.Errorf("failed to check workspace usage: %v", err)
+	}
+	transactionCount, err := s.transactionRepo.CountByWorkspace(id)
+	if err != nil {
+		return fmt.Errorf("failed to check workspace usage: %v", err)
+	}
+	if budgetCount > 0 || transactionCount > 0 {
+		return fmt.Errorf("cannot delete workspace that still has budgets or transactions")
+	}
+
+	return s.repo.Delete(id)
+}
+
+// GetMembers retrieves the members and pending invitations of a workspace
+func (s *WorkspaceService) GetMembers(id, userID string) ([]models.WorkspaceMember, error) {
+	if _, err := s.ResolveRole(id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMembers(id)
+}
+
+// InviteMember invites a user to a workspace by email (owner only)
+func (s *WorkspaceService) InviteMember(id, userID string, req models.InviteWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
+	if err := s.requireOwner(id, userID); err != nil {
+		return nil, err
+	}
+	if id == userID {
+		return nil, fmt.Errorf("cannot share your personal workspace")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	inviter, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if strings.EqualFold(inviter.Email, email) {
+		return nil, fmt.Errorf("you are already the owner of this workspace")
+	}
+
+	existing, err := s.repo.GetMemberByEmail(id, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%s has already been invited to this workspace", email)
+	}
+
+	workspace, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if workspace == nil {
+		return nil, fmt.Errorf("workspace not found")
+	}
+
+	member := &models.WorkspaceMember{
+		WorkspaceID: id,
+		Email:       email,
+		Role:        req.Role,
+		Status:      "pending",
+		InvitedBy:   userID,
+	}
+	if err := s.repo.CreateMember(member); err != nil {
+		return nil, err
+	}
+
+	if err := s.emailService.SendWorkspaceInvitationEmail(email, inviter.FullName, workspace.Name); err != nil {
+		fmt.Printf("Warning: failed to send workspace invitation to %s: %v\n", email, err)
+	}
+
+	return member, nil
+}
+
+// UpdateMemberRole changes a member's role (owner only)
+func (s *WorkspaceService) UpdateMemberRole(id, memberID, userID string, req models.UpdateWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
+	if err := s.requireOwner(id, userID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.UpdateMemberRole(memberID, id, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, fmt.Errorf("member not found")
+	}
+
+	return member, nil
+}
+
+// RemoveMember removes a member or revokes an invitation
+// Owners can remove anyone; other members can only remove themselves to leave the workspace
+func (s *WorkspaceService) RemoveMember(id, memberID, userID string) error {
+	role, err := s.ResolveRole(id, userID)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.repo.GetMemberByID(memberID, id)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return fmt.Errorf("member not found")
+	}
+
+	if role != models.WorkspaceRoleOwner && member.UserID != userID {
+		return fmt.Errorf("only the workspace owner can remove other members")
+	}
+
+	return s.repo.DeleteMember(memberID, id)
+}
+
+// GetInvitations retrieves the pending invitations addressed to the user's email
+func (s *WorkspaceService) GetInvitations(userID string) ([]models.WorkspaceInvitation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.GetPendingInvitations(strings.ToLower(user.Email))
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]models.WorkspaceInvitation, 0, len(pending))
+	for _, member := range pending {
+		invitation := models.WorkspaceInvitation{WorkspaceMember: member}
+		if workspace, err := s.repo.GetByID(member.WorkspaceID); err == nil && workspace != nil {
+			invitation.WorkspaceName = workspace.Name
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitation joins the workspace of an invitation addressed to the user
+func (s *WorkspaceService) AcceptInvitation(invitationID, userID string) (*models.WorkspaceMember, error) {
+	if _, err := s.getOwnInvitation(invitationID, userID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.ActivateMember(invitationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, fmt.Errorf("invitation not found")
+	}
+
+	return member, nil
+}
+
+// DeclineInvitation discards an invitation addressed to the user
+func (s *WorkspaceService) DeclineInvitation(invitationID, userID string) error {
+	invitation, err := s.getOwnInvitation(invitationID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteMember(invitationID, invitation.WorkspaceID)
+}
+
+// getOwnInvitation retrieves a pending invitation and makes sure it was sent to the user's email
+func (s *WorkspaceService) getOwnInvitation(invitationID, userID string) (*models.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.repo.GetPendingInvitation(invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || !strings.EqualFold(invitation.Email, user.Email) {
+		return nil, fmt.Errorf("invitation not found")
+	}
+
+	return invitation, nil
+}
+
+// requireOwner makes sure the user owns the workspace
+func (s *WorkspaceService) requireOwner(id, userID string) error {
+	role, err := s.ResolveRole(id, userID)
+	if err != nil {
+		return err
+	}
+	if role != models.WorkspaceRoleOwner {
+		return fmt.Errorf("only the workspace owner can perform this action")
+	}
+	return nil
+}
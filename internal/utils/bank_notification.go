@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"finance-hub-api/internal/models"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bankNotificationTemplate describes where one bank puts each field in its balance-change SMS or push notification
+// Every pattern captures the field in its first group; amount captures the sign first and the number second.
+type bankNotificationTemplate struct {
+	amount      *regexp.Regexp
+	balance     *regexp.Regexp
+	occurredAt  *regexp.Regexp
+	timeLayouts []string
+	reference   *regexp.Regexp
+	account     *regexp.Regexp
+}
+
+// bankNotificationTemplates are keyed by the VietQR bank code stored in Account.BankCode
+//
+// Sample messages the patterns are written against:
+//
+//	VCB  SD TK 0011001234567 +5,000,000VND luc 15-03-2025 10:20:30. SD 12,345,678VND. Ref MBVCB.123456.chuyen tien
+//	TCB  TK 1903xxxx890 So tien GD:-150,000 So du:2,350,000 15/03/25 10:20 ND: thanh toan hoa don
+//	MB   TK 0123xxx789|GD: +2,000,000VND 15/03/25 10:20|SD: 10,500,000VND|ND: NGUYEN VAN A chuyen tien
+//	ACB  ACB: TK 123456789(VND) + 500,000 luc 10:20 15/03/2025. So du 3,200,000. GD: thanh toan
+//	ICB  VietinBank:15/03/2025 10:20|TK:1234567890|GD:-200,000VND|SDC:1,800,000VND|ND:tien dien
+//	BIDV BIDV: 10:20 15/03/2025 Tai khoan thanh toan: 1234567890. So tien GD: -300,000VND. So du cuoi: 4,700,000VND. ND: an trua
+//	VPB  VPBank: TK 12345678 tai VPB +1,000,000VND luc 15/03/2025 10:20. SD: 2,000,000VND. ND: luong
+//	TPB  (TPBank): 15/03/25;10:20 TK: xxxx1234 PS:-100,000VND SD: 900,000VND ND: cafe
+var bankNotificationTemplates = map[string]bankNotificationTemplate{
+	"VCB": {
+		amount:      regexp.MustCompile(`(?i)TK\s*[\dx*]+\s*([+-])\s*([\d.,]+)\s*VND`),
+		balance:     regexp.MustCompile(`(?i)\.\s*SD\s+([\d.,]+)\s*VND`),
+		occurredAt:  regexp.MustCompile(`(?i)luc\s+(\d{2}-\d{2}-\d{4}\s+\d{2}:\d{2}(?::\d{2})?)`),
+		timeLayouts: []string{"02-01-2006 15:04:05", "02-01-2006 15:04"},
+		reference:   regexp.MustCompile(`(?i)Ref\s+(.+)$`),
+		account:     regexp.MustCompile(`(?i)TK\s*([\dx*]+)`),
+	},
+	"TCB": {
+		amount:      regexp.MustCompile(`(?i)So tien GD:\s*([+-])\s*([\d.,]+)`),
+		balance:     regexp.MustCompile(`(?i)So du:\s*([\d.,]+)`),
+		occurredAt:  regexp.MustCompile(`(\d{2}/\d{2}/\d{2,4}\s+\d{2}:\d{2})`),
+		timeLayouts: []string{"02/01/06 15:04", "02/01/2006 15:04"},
+		reference:   regexp.MustCompile(`(?i)ND:\s*(.+)$`),
+		account:     regexp.MustCompile(`(?i)TK\s*([\dx*]+)`),
+	},
+	"MB": {
+		amount:      regexp.MustCompile(`(?i)GD:\s*([+-])\s*([\d.,]+)`),
+		balance:     regexp.MustCompile(`(?i)SD:\s*([\d.,]+)`),
+		occurredAt:  regexp.MustCompile(`(\d{2}/\d{2}/\d{2,4}\s+\d{2}:\d{2})`),
+		timeLayouts: []string{"02/01/06 15:04", "02/01/2006 15:04"},
+		reference:   regexp.MustCompile(`(?i)ND:\s*(.+)$`),
+		account:     regexp.MustCompile(`(?i)TK\s*([\dx*]+)`),
+	},
+	"ACB": {
+		amount:      regexp.MustCompile(`(?i)\(VND\)\s*([+-])\s*([\d.,]+)`),
+		balance:     regexp.MustCompile(`(?i)So du\s*:?\s*([\d.,]+)`),
+		occurredAt:  regexp.MustCompile(`(?i)luc\s+(\d{2}:\d{2}\s+\d{2}/\d{2}/\d{4})`),
+		timeLayouts: []string{"15:04 02/01/2006"},
+		reference:   regexp.MustCompile(`(?i)GD:\s*(.+)$`),
+		account:     regexp.MustCompile(`(?i)TK\s*([\dx*]+)`),
+	},
+	"ICB": {
+		amount:      regexp.MustCompile(`(?i)GD:\s*([+-])\s*([\d.,]+)`),
+		balance:     regexp.MustCompile(`(?i)SDC:\s*([\d.,]+)`),
+		occurredAt:  regexp.MustCompile(`(\d{2}/\d{2}/\d{4}\s+\d{2}:\d{2})`),
+		timeLayouts: []string{"02/01/2006 15:04"},
+		reference:   regexp.MustCompile(`(?i)ND:\s*(.+)$`),
+		account:     regexp.MustCompile(`(?i)TK:\s*([\dx*]+)`),
+	},
+	"BIDV": {
+		amount:      regexp.MustCompile(`(?i)So tien GD:\s*([+-])\s*([\d.,]+)`),
+		balance:     regexp.MustCompile(`(?i)So du cuoi:\s*([\d.,]+)`),
+		occurredAt:  regexp.MustCompile(`(\d{2}:\d{2}\s+\d{2}/\d{2}/\d{4})`),
+		timeLayouts: []string{"15:04 02/01/2006"},
+		reference:   regexp.MustCompile(`(?i)ND:\s*(.+)$`),
+		account:     regexp.MustCompile(`(?i)Tai khoan[^:]*:\s*([\dx*]+)`),
+	},
+	"VPB": {
+		amount:      regexp.MustCompile(`(?i)tai VPB\s*([+-])\s*([\d.,]+)`),
+		balance:     regexp.MustCompile(`(?i)SD:\s*([\d.,]+)`),
+		occurredAt:  regexp.MustCompile(`(?i)luc\s+(\d{2}/\d{2}/\d{4}\s+\d{2}:\d{2})`),
+		timeLayouts: []string{"02/01/2006 15:04"},
+		reference:   regexp.MustCompile(`(?i)ND:\s*(.+)$`),
+		account:     regexp.MustCompile(`(?i)TK\s*([\dx*]+)`),
+	},
+	"TPB": {
+		amount:      regexp.MustCompile(`(?i)PS:\s*([+-])\s*([\d.,]+)`),
+		balance:     regexp.MustCompile(`(?i)SD:\s*([\d.,]+)`),
+		occurredAt:  regexp.MustCompile(`(\d{2}/\d{2}/\d{2};\d{2}:\d{2})`),
+		timeLayouts: []string{"02/01/06;15:04"},
+		reference:   regexp.MustCompile(`(?i)ND:\s*(.+)$`),
+		account:     regexp.MustCompile(`(?i)TK:\s*([\dx*]+)`),
+	},
+}
+
+// SupportedNotificationBanks returns the bank codes that have a notification template
+func SupportedNotificationBanks() []string {
+	codes := make([]string, 0, len(bankNotificationTemplates))
+	for code := range bankNotificationTemplates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// HasNotificationTemplate reports whether notifications from a bank can be parsed
+func HasNotificationTemplate(bankCode string) bool {
+	_, ok := bankNotificationTemplates[strings.ToUpper(bankCode)]
+	return ok
+}
+
+// ParseBankNotification reads a balance-change SMS or push notification with the template of the given bank
+// Times without a zone are read in location. Amount and direction are required; the other fields are left
+// empty when the message does not carry them.
+func ParseBankNotification(bankCode, text string, location *time.Location) (*models.BankNotification, error) {
+	code := strings.ToUpper(bankCode)
+	template, ok := bankNotificationTemplates[code]
+	if !ok {
+		return nil, fmt.Errorf("no notification template for bank %s", bankCode)
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	notification := &models.BankNotification{BankCode: code}
+
+	match := template.amount.FindStringSubmatch(text)
+	if match == nil {
+		return nil, fmt.Errorf("notification does not match the %s template", code)
+	}
+	amount, ok := parseQuickNumber(match[2])
+	if !ok || amount <= 0 {
+		return nil, fmt.Errorf("invalid amount %q", match[2])
+	}
+	notification.Amount = amount
+	notification.Direction = models.BankNotificationDirectionOut
+	if match[1] == "+" {
+		notification.Direction = models.BankNotificationDirectionIn
+	}
+
+	if match := template.balance.FindStringSubmatch(text); match != nil {
+		if balance, ok := parseQuickNumber(match[1]); ok {
+			notification.Balance = &balance
+		}
+	}
+
+	if match := template.occurredAt.FindStringSubmatch(text); match != nil {
+		for _, layout := range template.timeLayouts {
+			if occurredAt, err := time.ParseInLocation(layout, match[1], location); err == nil {
+				notification.OccurredAt = &occurredAt
+				break
+			}
+		}
+	}
+
+	if match := template.reference.FindStringSubmatch(text); match != nil {
+		reference := strings.Trim(strings.TrimSpace(match[1]), ".|")
+		if reference != "" {
+			notification.Reference = &reference
+		}
+	}
+
+	if match := template.account.FindStringSubmatch(text); match != nil {
+		notification.AccountHint = match[1]
+	}
+
+	return notification, nil
+}
+
+// MatchAccountNumber reports whether a possibly masked account number from a notification (1903xxxx890, xxxx1234)
+// fits a full account number. An unmasked hint may also be just the last digits of the number.
+func MatchAccountNumber(hint, number string) bool {
+	hint = strings.ToLower(strings.TrimSpace(hint))
+	number = strings.TrimSpace(number)
+	if hint == "" || number == "" {
+		return false
+	}
+
+	first := strings.IndexAny(hint, "x*")
+	if first == -1 {
+		return strings.HasSuffix(number, hint) || strings.HasSuffix(hint, number)
+	}
+	last := strings.LastIndexAny(hint, "x*")
+
+	return strings.HasPrefix(number, hint[:first]) && strings.HasSuffix(number, hint[last+1:])
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"finance-hub-api/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBankNotification(t *testing.T) {
+	at := func(seconds int) time.Time {
+		return time.Date(2025, 3, 15, 10, 20, seconds, 0, time.UTC)
+	}
+
+	tests := []struct {
+		bankCode    string
+		text        string
+		amount      float64
+		direction   string
+		balance     float64
+		occurredAt  time.Time
+		reference   string
+		accountHint string
+	}{
+		{
+			bankCode: "VCB",
+			text:     "SD TK 0011001234567 +5,000,000VND luc 15-03-2025 10:20:30. SD 12,345,678VND. Ref MBVCB.123456.chuyen tien",
+			amount:   5000000, direction: models.BankNotificationDirectionIn, balance: 12345678, occurredAt: at(30),
+			reference: "MBVCB.123456.chuyen tien", accountHint: "0011001234567",
+		},
+		{
+			bankCode: "TCB",
+			text:     "TK 1903xxxx890 So tien GD:-150,000 So du:2,350,000 15/03/25 10:20 ND: thanh toan hoa don",
+			amount:   150000, direction: models.BankNotificationDirectionOut, balance: 2350000, occurredAt: at(0),
+			reference: "thanh toan hoa don", accountHint: "1903xxxx890",
+		},
+		{
+			bankCode: "MB",
+			text:     "TK 0123xxx789|GD: +2,000,000VND 15/03/25 10:20|SD: 10,500,000VND|ND: NGUYEN VAN A chuyen tien",
+			amount:   2000000, direction: models.BankNotificationDirectionIn, balance: 10500000, occurredAt: at(0),
+			reference: "NGUYEN VAN A chuyen tien", accountHint: "0123xxx789",
+		},
+		{
+			bankCode: "ACB",
+			text:     "ACB: TK 123456789(VND) + 500,000 luc 10:20 15/03/2025. So du 3,200,000. GD: thanh toan",
+			amount:   500000, direction: models.BankNotificationDirectionIn, balance: 3200000, occurredAt: at(0),
+			reference: "thanh toan", accountHint: "123456789",
+		},
+		{
+			bankCode: "ICB",
+			text:     "VietinBank:15/03/2025 10:20|TK:1234567890|GD:-200,000VND|SDC:1,800,000VND|ND:tien dien",
+			amount:   200000, direction: models.BankNotificationDirectionOut, balance: 1800000, occurredAt: at(0),
+			reference: "tien dien", accountHint: "1234567890",
+		},
+		{
+			bankCode: "BIDV",
+			text:     "BIDV: 10:20 15/03/2025 Tai khoan thanh toan: 1234567890. So tien GD: -300,000VND. So du cuoi: 4,700,000VND. ND: an trua",
+			amount:   300000, direction: models.BankNotificationDirectionOut, balance: 4700000, occurredAt: at(0),
+			reference: "an trua", accountHint: "1234567890",
+		},
+		{
+			bankCode: "VPB",
+			text:     "VPBank: TK 12345678 tai VPB +1,000,000VND luc 15/03/2025 10:20. SD: 2,000,000VND. ND: luong",
+			amount:   1000000, direction: models.BankNotificationDirectionIn, balance: 2000000, occurredAt: at(0),
+			reference: "luong", accountHint: "12345678",
+		},
+		{
+			bankCode: "TPB",
+			text:     "(TPBank): 15/03/25;10:20 TK: xxxx1234 PS:-100,000VND SD: 900,000VND ND: cafe",
+			amount:   100000, direction: models.BankNotificationDirectionOut, balance: 900000, occurredAt: at(0),
+			reference: "cafe", accountHint: "xxxx1234",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bankCode, func(t *testing.T) {
+			notification, err := ParseBankNotification(strings.ToLower(tt.bankCode), tt.text, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseBankNotification returned error: %v", err)
+			}
+
+			if notification.BankCode != tt.bankCode {
+				t.Errorf("bank code = %s, want %s", notification.BankCode, tt.bankCode)
+			}
+			if notification.Amount != tt.amount {
+				t.Errorf("amount = %v, want %v", notification.Amount, tt.amount)
+			}
+			if notification.Direction != tt.direction {
+				t.Errorf("direction = %s, want %s", notification.Direction, tt.direction)
+			}
+			if notification.Balance == nil || *notification.Balance != tt.balance {
+				t.Errorf("balance = %v, want %v", notification.Balance, tt.balance)
+			}
+			if notification.OccurredAt == nil || !notification.OccurredAt.Equal(tt.occurredAt) {
+				t.Errorf("occurred at = %v, want %v", notification.OccurredAt, tt.occurredAt)
+			}
+			if notification.Reference == nil || *notification.Reference != tt.reference {
+				t.Errorf("reference = %v, want %q", notification.Reference, tt.reference)
+			}
+			if notification.AccountHint != tt.accountHint {
+				t.Errorf("account hint = %s, want %s", notification.AccountHint, tt.accountHint)
+			}
+		})
+	}
+}
+
+func TestParseBankNotificationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		bankCode string
+		text     string
+		wantErr  string
+	}{
+		{name: "unknown bank", bankCode: "XYZ", text: "TK 123 +100,000VND", wantErr: "no notification template"},
+		{name: "message of another bank", bankCode: "VCB", text: "TK 1903xxxx890 So tien GD:-150,000 So du:2,350,000", wantErr: "does not match"},
+		{name: "zero amount", bankCode: "TPB", text: "TK: xxxx1234 PS:-0VND SD: 900,000VND", wantErr: "invalid amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBankNotification(tt.bankCode, tt.text, time.UTC)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchAccountNumber(t *testing.T) {
+	tests := []struct {
+		hint   string
+		number string
+		want   bool
+	}{
+		{hint: "1903xxxx890", number: "19031234890", want: true},
+		{hint: "1903xxxx890", number: "19041234890", want: false},
+		{hint: "xxxx1234", number: "9876541234", want: true},
+		{hint: "xxxx1234", number: "9876541235", want: false},
+		{hint: "1234", number: "9876541234", want: true},
+		{hint: "0011001234567", number: "0011001234567", want: true},
+		{hint: "", number: "0011001234567", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchAccountNumber(tt.hint, tt.number); got != tt.want {
+			t.Errorf("MatchAccountNumber(%q, %q) = %v, want %v", tt.hint, tt.number, got, tt.want)
+		}
+	}
+}
@@ -40,6 +40,8 @@ type EmailData struct {
 	Body          string
 	Link          string
 	Token         string
+	InviterName   string
+	WorkspaceName string
 }
 
 // SendVerificationEmail sends email verification email
@@ -78,6 +80,23 @@ func (s *EmailService) SendPasswordResetEmail(toEmail, toName, token string) err
 	return s.sendEmail(toEmail, emailData.Subject, htmlBody)
 }
 
+// SendWorkspaceInvitationEmail sends an invitation to join a workspace
+func (s *EmailService) SendWorkspaceInvitationEmail(toEmail, inviterName, workspaceName string) error {
+	invitationsURL := fmt.Sprintf("%s/workspaces/invitations", s.frontendURL)
+
+	emailData := EmailData{
+		RecipientEmail: toEmail,
+		Subject:        "Lời mời tham gia không gian chung - Finance Hub",
+		Link:           invitationsURL,
+		InviterName:    inviterName,
+		WorkspaceName:  workspaceName,
+	}
+
+	htmlBody := s.getWorkspaceInvitationEmailTemplate(emailData)
+
+	return s.sendEmail(toEmail, emailData.Subject, htmlBody)
+}
+
 // sendEmail sends an email using SMTP
 func (s *EmailService) sendEmail(to, subject, htmlBody string) error {
 	// If SMTP not configured, just log and return (for development)
@@ -243,3 +262,62 @@ func (s *EmailService) getPasswordResetEmailTemplate(data EmailData) string {
 	t.Execute(&buf, data)
 	return buf.String()
 }
+
+// getWorkspaceInvitationEmailTemplate returns HTML template for workspace invitations
+func (s *EmailService) getWorkspaceInvitationEmailTemplate(data EmailData) string {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Subject}}</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: Arial, sans-serif; background-color: #f4f4f7;">
+    <table width="100%" cellpadding="0" cellspacing="0" border="0">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table width="600" cellpadding="0" cellspacing="0" border="0" style="background-color: #ffffff; border-radius: 8px; box-shadow: 0 2px 8px rgba(0,0,0,0.1);">
+                    <!-- Header -->
+                    <tr>
+                        <td style="padding: 40px 40px 30px; text-align: center; background: linear-gradient(135deg, #43cea2 0%, #185a9d 100%); border-radius: 8px 8px 0 0;">
+                            <h1 style="margin: 0; color: #ffffff; font-size: 28px; font-weight: bold;">Finance Hub</h1>
+                        </td>
+                    </tr>
+                    <!-- Content -->
+                    <tr>
+                        <td style="padding: 40px;">
+                            <h2 style="margin: 0 0 20px; color: #333333; font-size: 24px;">Bạn được mời quản lý tài chính chung</h2>
+                            <p style="margin: 0 0 20px; color: #666666; font-size: 16px; line-height: 1.5;">
+                                {{.InviterName}} đã mời bạn tham gia không gian <strong>{{.WorkspaceName}}</strong> trên Finance Hub để cùng theo dõi tài khoản, ngân sách và giao dịch.
+                            </p>
+                            <div style="text-align: center; margin: 30px 0;">
+                                <a href="{{.Link}}" style="display: inline-block; padding: 14px 40px; background: linear-gradient(135deg, #43cea2 0%, #185a9d 100%); color: #ffffff; text-decoration: none; border-radius: 6px; font-size: 16px; font-weight: bold;">
+                                    Xem lời mời
+                                </a>
+                            </div>
+                            <p style="margin: 30px 0 0; color: #999999; font-size: 14px; line-height: 1.5;">
+                                Hãy đăng nhập hoặc đăng ký bằng địa chỉ email này để chấp nhận lời mời. Nếu bạn không quen người gửi, vui lòng bỏ qua email này.
+                            </p>
+                        </td>
+                    </tr>
+                    <!-- Footer -->
+                    <tr>
+                        <td style="padding: 30px; background-color: #f8f9fa; border-radius: 0 0 8px 8px; text-align: center;">
+                            <p style="margin: 0; color: #999999; font-size: 12px;">
+                                © 2026 Finance Hub. All rights reserved.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`
+	t := template.Must(template.New("email").Parse(tmpl))
+	var buf bytes.Buffer
+	t.Execute(&buf, data)
+	return buf.String()
+}
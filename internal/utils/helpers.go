@@ -77,3 +77,26 @@ func Contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+// VietnamLocation returns the Asia/Ho_Chi_Minh zone, or a fixed UTC+7 zone when the system has no zone database
+func VietnamLocation() *time.Location {
+	location, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		return time.FixedZone("ICT", 7*60*60)
+	}
+	return location
+}
+
+// Initials returns the uppercase first letters of the first and last words of a name, such as "NA" for "Nguyễn Văn A"
+func Initials(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+
+	initials := []rune(words[0])[:1]
+	if len(words) > 1 {
+		initials = append(initials, []rune(words[len(words)-1])[0])
+	}
+	return strings.ToUpper(string(initials))
+}
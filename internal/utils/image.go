@@ -0,0 +1,454 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ErrUnsupportedImage is returned when an image format cannot be decoded, such as WebP or PDF
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// ErrImageTooLarge is returned when an image declares more pixels than the pipeline is allowed to decode
+var ErrImageTooLarge = errors.New("image is too large")
+
+const (
+	// jpegQuality is used whenever an image is re-encoded as JPEG
+	jpegQuality = 85
+	// ThumbnailSize bounds the thumbnails shown in transaction lists
+	ThumbnailSize = 320
+	// AvatarSize and AvatarThumbnailSize are the sides of the square avatar variants
+	AvatarSize          = 512
+	AvatarThumbnailSize = 128
+	// avatarFolder holds square-cropped avatars, each with a small variant next to it
+	avatarFolder = "avatars/square"
+)
+
+// ProcessedImage is the output of the image pipeline
+type ProcessedImage struct {
+	Content     []byte
+	ContentType string
+	Width       int // 0 when the format cannot be decoded
+	Height      int
+}
+
+// ReadUpload reads an uploaded file and checks its real type
+// At most maxSize bytes are read whatever the client claims, and the type sniffed from the content must be
+// allowed and agree with the declared Content-Type. It returns the content and the sniffed type.
+func ReadUpload(file multipart.File, header *multipart.FileHeader, maxSize int64, allowedTypes []string) ([]byte, string, error) {
+	content, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, "", fmt.Errorf("file size exceeds limit of %d bytes", maxSize)
+	}
+
+	contentType, err := CheckContentType(content, header.Header.Get("Content-Type"), allowedTypes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return content, contentType, nil
+}
+
+// CheckContentType sniffs the type of content and checks it is allowed and matches the declared type
+func CheckContentType(content []byte, declaredType string, allowedTypes []string) (string, error) {
+	contentType := SniffContentType(content)
+	if !typeAllowed(contentType, allowedTypes) {
+		return "", fmt.Errorf("file type %s not allowed", contentType)
+	}
+
+	declared := normalizeContentType(declaredType)
+	if declared != "" && declared != "application/octet-stream" && declared != contentType {
+		return "", fmt.Errorf("file content is %s but was declared as %s", contentType, declared)
+	}
+
+	return contentType, nil
+}
+
+// SniffContentType detects the type of content from its bytes
+func SniffContentType(content []byte) string {
+	return normalizeContentType(http.DetectContentType(content))
+}
+
+// ProcessImage strips metadata such as EXIF and GPS from an image and downscales it to fit maxDimension
+// JPEG and PNG are re-encoded, JPEG after applying its EXIF orientation; WebP has its metadata chunks
+// removed but keeps its size. Other types, such as PDF, are returned unchanged. Images declaring more
+// than maxPixels pixels are rejected with ErrImageTooLarge before they are decoded.
+func ProcessImage(content []byte, contentType string, maxDimension, maxPixels int) (*ProcessedImage, error) {
+	switch contentType {
+	case "image/jpeg", "image/png":
+		img, err := decodeImage(content, contentType, maxPixels)
+		if err != nil {
+			return nil, err
+		}
+		return encodeImage(fitWithin(img, maxDimension), contentType)
+	case "image/webp":
+		stripped, err := stripWebPMetadata(content)
+		if err != nil {
+			return nil, err
+		}
+		return &ProcessedImage{Content: stripped, ContentType: contentType}, nil
+	default:
+		return &ProcessedImage{Content: content, ContentType: contentType}, nil
+	}
+}
+
+// MakeThumbnail renders a JPEG that fits within size, for lists of transactions
+// Transparent areas are filled with white. Formats that cannot be decoded return ErrUnsupportedImage.
+func MakeThumbnail(content []byte, contentType string, size, maxPixels int) (*ProcessedImage, error) {
+	img, err := decodeImage(content, contentType, maxPixels)
+	if err != nil {
+		return nil, err
+	}
+	return encodeImage(fitWithin(img, size), "image/jpeg")
+}
+
+// SquareCrop crops the centre square of an image and scales it down to size, for avatars
+// PNG keeps its format so transparency survives; everything else becomes JPEG.
+func SquareCrop(content []byte, contentType string, size, maxPixels int) (*ProcessedImage, error) {
+	img, err := decodeImage(content, contentType, maxPixels)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	square := img.SubImage(image.Rect(x, y, x+side, y+side)).(*image.RGBA)
+
+	outputType := "image/jpeg"
+	if contentType == "image/png" {
+		outputType = "image/png"
+	}
+	return encodeImage(fitWithin(square, size), outputType)
+}
+
+// VariantKey returns the key of a generated variant of a stored file, such as "receipts/a_thumb.jpg"
+func VariantKey(key, variant, contentType string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "_" + variant + imageExt(contentType)
+}
+
+// NewAvatarKey returns the key for a new square avatar of the given type
+func NewAvatarKey(contentType string) string {
+	return ObjectKey(avatarFolder, contentType)
+}
+
+// AvatarThumbnailKey returns the key of the small variant of an avatar, or false when it has none
+// Only avatars cropped by the pipeline have one; older uploads and external pictures do not.
+func AvatarThumbnailKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, avatarFolder+"/") {
+		return "", false
+	}
+	contentType := "image/jpeg"
+	if path.Ext(key) == ".png" {
+		contentType = "image/png"
+	}
+	return VariantKey(key, "small", contentType), true
+}
+
+// imageExt returns the extension of images the pipeline writes, which are PNG or JPEG
+func imageExt(contentType string) string {
+	if contentType == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// normalizeContentType lowercases a content type and drops parameters and legacy aliases
+func normalizeContentType(contentType string) string {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	switch contentType {
+	case "image/jpg", "image/pjpeg":
+		return "image/jpeg"
+	case "application/x-pdf":
+		return "application/pdf"
+	}
+	return contentType
+}
+
+// typeAllowed reports whether a content type is in the allowed list
+func typeAllowed(contentType string, allowedTypes []string) bool {
+	for _, allowed := range allowedTypes {
+		if normalizeContentType(allowed) == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeImage decodes an image into RGBA, applying the EXIF orientation of JPEGs
+// The header is read first, so a small file declaring huge dimensions is rejected before anything is allocated.
+func decodeImage(content []byte, contentType string, maxPixels int) (*image.RGBA, error) {
+	var (
+		decode       func(io.Reader) (image.Image, error)
+		decodeConfig func(io.Reader) (image.Config, error)
+	)
+	switch contentType {
+	case "image/jpeg":
+		decode, decodeConfig = jpeg.Decode, jpeg.DecodeConfig
+	case "image/png":
+		decode, decodeConfig = png.Decode, png.DecodeConfig
+	case "image/gif":
+		decode, decodeConfig = gif.Decode, gif.DecodeConfig
+	default:
+		return nil, ErrUnsupportedImage
+	}
+
+	config, err := decodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if maxPixels > 0 && int64(config.Width)*int64(config.Height) > int64(maxPixels) {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrImageTooLarge, config.Width, config.Height, maxPixels)
+	}
+
+	img, err := decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	if contentType == "image/jpeg" {
+		rgba = applyOrientation(rgba, jpegOrientation(content))
+	}
+	return rgba, nil
+}
+
+// encodeImage encodes an image as JPEG or PNG; metadata of the original is not carried over
+func encodeImage(img *image.RGBA, contentType string) (*ProcessedImage, error) {
+	var buf bytes.Buffer
+	switch contentType {
+	case "image/png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+	default:
+		// JPEG has no alpha channel, so flatten onto white
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		contentType = "image/jpeg"
+	}
+
+	return &ProcessedImage{
+		Content:     buf.Bytes(),
+		ContentType: contentType,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
+
+// fitWithin scales an image down, keeping its aspect ratio, so neither side exceeds maxDimension
+// Smaller images are returned as they are; images are never enlarged.
+func fitWithin(img *image.RGBA, maxDimension int) *image.RGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if maxDimension <= 0 || (width <= maxDimension && height <= maxDimension) {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxDimension/width)
+		width = maxDimension
+	} else {
+		width = max(1, width*maxDimension/height)
+		height = maxDimension
+	}
+	return downscale(img, width, height)
+}
+
+// downscale resizes an image to a smaller size by averaging the source pixels each target pixel covers
+func downscale(src *image.RGBA, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(bounds.Min.X+x0, bounds.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(src.Pix[offset])
+					g += uint32(src.Pix[offset+1])
+					b += uint32(src.Pix[offset+2])
+					a += uint32(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+// applyOrientation turns an image the way its EXIF orientation (1-8) says it should be displayed
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // Mirrored
+				sx, sy = width-1-x, y
+			case 3: // Upside down
+				sx, sy = width-1-x, height-1-y
+			case 4: // Mirrored upside down
+				sx, sy = x, height-1-y
+			case 5: // Mirrored and turned left
+				sx, sy = y, x
+			case 6: // Turned left, so rotate clockwise
+				sx, sy = y, height-1-x
+			case 7: // Mirrored and turned right
+				sx, sy = width-1-y, height-1-x
+			case 8: // Turned right, so rotate counter-clockwise
+				sx, sy = width-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG, or returns 1 when it has none
+func jpegOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return 1
+	}
+
+	for offset := 2; offset+4 <= len(content); {
+		if content[offset] != 0xFF {
+			return 1
+		}
+		marker := content[offset+1]
+		if marker == 0xDA || marker == 0xD9 { // Image data starts; metadata comes before it
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(content[offset+2:]))
+		end := offset + 2 + length
+		if length < 2 || end > len(content) {
+			return 1
+		}
+
+		segment := content[offset+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		offset = end
+	}
+
+	return 1
+}
+
+// exifOrientation finds the orientation tag in the first IFD of TIFF-encoded EXIF data
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 { // Orientation, a SHORT stored in the value field
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+
+	return 1
+}
+
+// stripWebPMetadata removes the EXIF and XMP chunks of a WebP file and clears their flags
+func stripWebPMetadata(content []byte) ([]byte, error) {
+	if len(content) < 12 || string(content[:4]) != "RIFF" || string(content[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("invalid WebP file")
+	}
+
+	out := make([]byte, 12, len(content))
+	copy(out, content[:12])
+
+	for offset := 12; offset+8 <= len(content); {
+		fourCC := string(content[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(content[offset+4:]))
+		end := offset + 8 + size + size%2 // Chunks are padded to an even size
+		if end > len(content) {
+			end = len(content)
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), content[offset:end]...)
+			if len(chunk) > 8 {
+				chunk[8] &^= 0x08 | 0x04 // EXIF and XMP present flags
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, content[offset:end]...)
+		}
+		offset = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// contentTypeSuffix names the file next to each stored file that records its content type
+const contentTypeSuffix = ".content-type"
+
+// LocalStorage keeps files in a directory on disk, for development without R2
+// Files are served by the API through URLs signed by its URLSigner.
+type LocalStorage struct {
+	*URLSigner
+	dir string
+}
+
+// NewLocalStorage creates a storage backend writing under dir
+func NewLocalStorage(dir string, signer *URLSigner) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &LocalStorage{URLSigner: signer, dir: dir}, nil
+}
+
+// PutObject writes content under a key and returns its URL
+func (s *LocalStorage) PutObject(ctx context.Context, key string, content []byte, contentType string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to store file: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", fmt.Errorf("failed to store file: %w", err)
+	}
+	if err := os.WriteFile(path+contentTypeSuffix, []byte(contentType), 0o644); err != nil {
+		return "", fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return s.URLForKey(key), nil
+}
+
+// GetObject reads the content stored under a key
+// The content type is the one recorded when the file was stored; files stored before it was recorded
+// have it sniffed from their content.
+func (s *LocalStorage) GetObject(ctx context.Context, key string) ([]byte, string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	contentType := http.DetectContentType(content)
+	if recorded, err := os.ReadFile(path + contentTypeSuffix); err == nil && len(recorded) > 0 {
+		contentType = string(recorded)
+	}
+
+	return content, contentType, nil
+}
+
+// DeleteObject deletes the file stored under a key
+func (s *LocalStorage) DeleteObject(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	if err := os.Remove(path + contentTypeSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return nil
+}
+
+// ListObjects lists every file whose key starts with the prefix
+func (s *LocalStorage) ListObjects(ctx context.Context, prefix string) ([]StoredObject, error) {
+	var objects []StoredObject
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || strings.HasSuffix(path, contentTypeSuffix) {
+			return err
+		}
+
+		relative, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relative)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, StoredObject{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return objects, nil
+}
+
+// path maps a key to its file, refusing keys that would leave the storage directory
+func (s *LocalStorage) path(key string) (string, error) {
+	if !validObjectKey(key) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
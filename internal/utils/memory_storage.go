@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStorage keeps files in memory, for tests and throwaway environments
+// Files are served by the API through URLs signed by its URLSigner and are lost on restart.
+type MemoryStorage struct {
+	*URLSigner
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+// memoryObject is a file held by MemoryStorage
+type memoryObject struct {
+	content      []byte
+	contentType  string
+	lastModified time.Time
+}
+
+// NewMemoryStorage creates an empty in-memory storage backend
+func NewMemoryStorage(signer *URLSigner) *MemoryStorage {
+	return &MemoryStorage{
+		URLSigner: signer,
+		objects:   make(map[string]memoryObject),
+	}
+}
+
+// PutObject stores a copy of content under a key and returns its URL
+func (s *MemoryStorage) PutObject(ctx context.Context, key string, content []byte, contentType string) (string, error) {
+	if !validObjectKey(key) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = memoryObject{
+		content:      append([]byte(nil), content...),
+		contentType:  contentType,
+		lastModified: time.Now(),
+	}
+
+	return s.URLForKey(key), nil
+}
+
+// GetObject returns a copy of the content stored under a key
+func (s *MemoryStorage) GetObject(ctx context.Context, key string) ([]byte, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, "", ErrObjectNotFound
+	}
+
+	return append([]byte(nil), object.content...), object.contentType, nil
+}
+
+// DeleteObject deletes the object stored under a key
+func (s *MemoryStorage) DeleteObject(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, key)
+	return nil
+}
+
+// ListObjects lists every object whose key starts with the prefix, sorted by key
+func (s *MemoryStorage) ListObjects(ctx context.Context, prefix string) ([]StoredObject, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objects := []StoredObject{}
+	for key, object := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, StoredObject{
+				Key:          key,
+				Size:         int64(len(object.content)),
+				LastModified: object.lastModified,
+			})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	return objects, nil
+}
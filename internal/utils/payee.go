@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"finance-hub-api/internal/models"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	merchantSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+	merchantNumber    = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// NormalizeMerchantName reduces merchant text to the words that identify the merchant
+// Accents, case, punctuation and trailing store or terminal numbers are dropped, so "GRAB*FOOD 1234" becomes "grab food".
+func NormalizeMerchantName(merchant string) string {
+	var words []string
+	for _, word := range merchantSeparator.Split(NormalizeSearchText(merchant), -1) {
+		// Numbers after the first word are store or terminal numbers; a leading one is part of the name (7-Eleven)
+		if word == "" || (len(words) > 0 && merchantNumber.MatchString(word)) {
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// ValidatePayeePatterns checks that every payee pattern is a valid regular expression
+func ValidatePayeePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// MatchPayee finds the payee a merchant text belongs to
+// A payee whose name or alias normalizes to the same text wins over one that only matches by pattern.
+func MatchPayee(merchant string, payees []models.Payee) *models.Payee {
+	name := NormalizeMerchantName(merchant)
+	if name == "" {
+		return nil
+	}
+
+	for i := range payees {
+		payee := &payees[i]
+		if payee.NormalizedName == name {
+			return payee
+		}
+		for _, alias := range payee.Aliases {
+			if NormalizeMerchantName(alias) == name {
+				return payee
+			}
+		}
+	}
+
+	for i := range payees {
+		payee := &payees[i]
+		for _, pattern := range payee.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				continue
+			}
+			if re.MatchString(name) {
+				return payee
+			}
+		}
+	}
+
+	return nil
+}
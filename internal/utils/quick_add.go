@@ -0,0 +1,602 @@
+package utils
+
+import (
+	"finance-hub-api/internal/models"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// 45k, 1.5tr, 1tr5, 2trieu, 100.000, +500k, 20000d
+	quickAmountPattern = regexp.MustCompile(`^([+-]?)(\d+(?:[.,]\d+)*)(k|nghin|ngan|tr|trieu|m|ty|b|d|vnd)?(\d*)$`)
+	// 15/3, 15/03/2025, 15-3-25
+	quickDayMonthPattern = regexp.MustCompile(`^(\d{1,2})[/-](\d{1,2})(?:[/-](\d{2}|\d{4}))?$`)
+	// 2025-03-15
+	quickISODatePattern = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	// Thousand separators or decimal point
+	quickNumberSeparator = regexp.MustCompile(`[.,]`)
+)
+
+// quickAmountUnits maps amount suffixes (without diacritics) to their multiplier
+var quickAmountUnits = map[string]float64{
+	"k": 1e3, "nghin": 1e3, "ngan": 1e3,
+	"tr": 1e6, "trieu": 1e6, "m": 1e6,
+	"ty": 1e9, "b": 1e9,
+	"d": 1, "vnd": 1,
+}
+
+// quickWeekdays maps Vietnamese and English weekday names to their weekday
+var quickWeekdays = map[string]time.Weekday{
+	"thu 2": time.Monday, "thu hai": time.Monday, "t2": time.Monday, "monday": time.Monday,
+	"thu 3": time.Tuesday, "thu ba": time.Tuesday, "t3": time.Tuesday, "tuesday": time.Tuesday,
+	"thu 4": time.Wednesday, "thu tu": time.Wednesday, "t4": time.Wednesday, "wednesday": time.Wednesday,
+	"thu 5": time.Thursday, "thu nam": time.Thursday, "t5": time.Thursday, "thursday": time.Thursday,
+	"thu 6": time.Friday, "thu sau": time.Friday, "t6": time.Friday, "friday": time.Friday,
+	"thu 7": time.Saturday, "thu bay": time.Saturday, "t7": time.Saturday, "saturday": time.Saturday,
+	"chu nhat": time.Sunday, "cn": time.Sunday, "sunday": time.Sunday,
+}
+
+// quickRelativeDays maps relative day phrases to a number of days from today
+var quickRelativeDays = map[string]int{
+	"hom nay": 0, "today": 0,
+	"hom qua": -1, "hqua": -1, "yesterday": -1,
+	"hom kia":  -2,
+	"ngay mai": 1, "tomorrow": 1,
+}
+
+// quickIncomeKeywords mark a line as income rather than expense
+var quickIncomeKeywords = map[string]bool{
+	"luong": true, "salary": true, "thuong": true, "bonus": true, "income": true,
+	"thu nhap": true, "nhan tien": true, "received": true, "hoan tien": true, "refund": true,
+}
+
+// quickDateConnectors and quickAccountConnectors are dropped from the description together with what they introduce
+var quickDateConnectors = map[string]bool{"ngay": true, "on": true, "vao": true}
+var quickAccountConnectors = map[string]bool{
+	"bang": true, "qua": true, "by": true, "with": true, "via": true, "tu": true, "from": true,
+	"sang": true, "to": true, "vao": true, "into": true,
+}
+
+// quickAccountAliases maps common short names to the name used in accounts and bank codes
+var quickAccountAliases = map[string]string{
+	"vcb": "vietcombank", "tcb": "techcombank", "mb": "mbbank", "mbb": "mbbank",
+	"vpb": "vpbank", "tpb": "tpbank", "vtb": "vietinbank", "ctg": "vietinbank", "agr": "agribank",
+	"zalo": "zalopay", "zlp": "zalopay", "shopee": "shopeepay",
+}
+
+// quickCashAliases match any account of type cash
+var quickCashAliases = map[string]bool{"tienmat": true, "cash": true, "tm": true}
+
+// quickGenericAccountWords are too common in account names to identify an account on their own
+var quickGenericAccountWords = map[string]bool{
+	"vi": true, "the": true, "tai khoan": true, "ngan hang": true, "bank": true, "card": true, "account": true, "wallet": true,
+}
+
+// quickCategoryKeywords maps words found in a line to category names they usually belong to
+var quickCategoryKeywords = map[string][]string{
+	"cafe": {"an uong", "cafe", "ca phe", "food"}, "ca phe": {"an uong", "ca phe", "cafe", "food"},
+	"coffee": {"an uong", "cafe", "coffee", "food"}, "tra sua": {"an uong", "food"},
+	"pho": {"an uong", "food"}, "bun": {"an uong", "food"}, "com": {"an uong", "food"},
+	"an sang": {"an uong", "food"}, "an trua": {"an uong", "food"}, "an toi": {"an uong", "food"},
+	"an vat": {"an uong", "food"}, "nha hang": {"an uong", "food"}, "breakfast": {"an uong", "food"},
+	"lunch": {"an uong", "food"}, "dinner": {"an uong", "food"}, "restaurant": {"an uong", "food"},
+	"grab": {"di chuyen", "transport"}, "taxi": {"di chuyen", "transport"}, "xang": {"di chuyen", "xang xe", "transport"},
+	"gui xe": {"di chuyen", "transport"}, "parking": {"di chuyen", "transport"}, "gas": {"di chuyen", "transport"},
+	"dien": {"hoa don", "bills", "utilities"}, "nuoc": {"hoa don", "bills", "utilities"},
+	"internet": {"hoa don", "bills", "utilities"}, "wifi": {"hoa don", "bills", "utilities"},
+	"shopee": {"mua sam", "shopping"}, "lazada": {"mua sam", "shopping"}, "tiki": {"mua sam", "shopping"},
+	"quan ao": {"mua sam", "shopping"}, "shopping": {"mua sam", "shopping"},
+	"phim": {"giai tri", "entertainment"}, "movie": {"giai tri", "entertainment"}, "netflix": {"giai tri", "entertainment"},
+	"spotify": {"giai tri", "entertainment"}, "game": {"giai tri", "entertainment"},
+	"thuoc": {"suc khoe", "y te", "health"}, "benh vien": {"suc khoe", "y te", "health"}, "medicine": {"suc khoe", "health"},
+	"hoc phi": {"giao duc", "hoc tap", "education"}, "sach": {"giao duc", "hoc tap", "education"},
+	"tuition": {"giao duc", "education"}, "book": {"giao duc", "education"},
+	"tien nha": {"nha o", "tien nha", "housing", "rent"}, "thue nha": {"nha o", "tien nha", "housing", "rent"},
+	"rent":     {"nha o", "housing", "rent"},
+	"sieu thi": {"di cho", "thuc pham", "groceries"}, "di cho": {"di cho", "thuc pham", "groceries"},
+	"groceries": {"di cho", "thuc pham", "groceries"},
+	"luong":     {"luong", "salary", "thu nhap", "income"}, "salary": {"luong", "salary", "income"},
+	"thuong": {"thuong", "bonus", "thu nhap", "income"}, "bonus": {"thuong", "bonus", "income"},
+}
+
+// quickToken is one word of a quick-add line
+type quickToken struct {
+	text string // As typed, for the description
+	norm string // Lower-case without diacritics, for matching
+	used bool   // Consumed as amount, date, tag or account
+}
+
+// quickAccountMatch is an account found in a quick-add line
+type quickAccountMatch struct {
+	account   *models.Account
+	connector string // Word before the account name, such as "tu" or "sang"
+}
+
+// ParseQuickAdd turns a free-text line such as "cafe 45k hôm qua bằng momo #work" into a draft transaction
+//
+// Amounts, dates and tags are read by rules; accounts and categories are matched by name and alias against the
+// given workspace data. Nothing is saved, and fields that cannot be worked out are listed in Missing.
+func ParseQuickAdd(text string, now time.Time, accounts []models.Account, categories []models.Category) *models.ParsedTransaction {
+	tokens := tokenizeQuickAdd(text)
+
+	draft := models.CreateTransactionRequest{
+		Type:            "expense",
+		TransactionDate: now,
+		Tags:            []string{},
+	}
+
+	// Hashtags
+	for i := range tokens {
+		if strings.HasPrefix(tokens[i].text, "#") && len(tokens[i].text) > 1 {
+			draft.Tags = append(draft.Tags, strings.ToLower(strings.TrimPrefix(tokens[i].text, "#")))
+			tokens[i].used = true
+		}
+	}
+
+	if date, ok := parseQuickDate(tokens, now); ok {
+		draft.TransactionDate = date
+	}
+
+	amount, sign, hasAmount := parseQuickAmount(tokens)
+	draft.Amount = amount
+	if sign == "+" || hasQuickKeyword(tokens, quickIncomeKeywords) {
+		draft.Type = "income"
+	}
+
+	matches := matchQuickAccounts(tokens, accounts)
+	switch {
+	case len(matches) >= 2:
+		// Two accounts make a transfer, written "from A to B" or "B from A"
+		from, to := matches[0], matches[1]
+		if quickIsToConnector(from.connector) || quickIsFromConnector(to.connector) {
+			from, to = to, from
+		}
+		draft.Type = "transfer"
+		draft.AccountID = from.account.ID
+		draft.ToAccountID = &to.account.ID
+	case len(matches) == 1:
+		draft.AccountID = matches[0].account.ID
+	case len(accounts) == 1:
+		draft.AccountID = accounts[0].ID
+	}
+
+	if draft.Type != "transfer" {
+		if category := matchQuickCategory(tokens, draft.Tags, draft.Type, categories); category != nil {
+			draft.CategoryID = &category.ID
+		}
+	}
+
+	var description []string
+	for _, token := range tokens {
+		if !token.used {
+			description = append(description, token.text)
+		}
+	}
+	if len(description) > 0 {
+		joined := strings.Join(description, " ")
+		draft.Description = &joined
+	}
+
+	missing := []string{}
+	if !hasAmount {
+		missing = append(missing, "amount")
+	}
+	if draft.AccountID == "" {
+		missing = append(missing, "account")
+	}
+	if draft.Type != "transfer" && draft.CategoryID == nil {
+		missing = append(missing, "category")
+	}
+
+	return &models.ParsedTransaction{
+		Draft:   draft,
+		Missing: missing,
+	}
+}
+
+// tokenizeQuickAdd splits a line into words, keeping both the typed and the normalized form
+func tokenizeQuickAdd(text string) []quickToken {
+	var tokens []quickToken
+	for _, field := range strings.Fields(text) {
+		field = strings.Trim(field, ",;!?")
+		if field == "" {
+			continue
+		}
+		tokens = append(tokens, quickToken{text: field, norm: NormalizeSearchText(field)})
+	}
+	return tokens
+}
+
+// quickPhraseAt reports whether the unused tokens starting at i spell phrase (normalized, space separated)
+func quickPhraseAt(tokens []quickToken, i int, phrase string) bool {
+	words := strings.Fields(phrase)
+	if i+len(words) > len(tokens) {
+		return false
+	}
+	for j, word := range words {
+		if tokens[i+j].used || tokens[i+j].norm != word {
+			return false
+		}
+	}
+	return true
+}
+
+// useQuickTokens marks count tokens from i as consumed, along with a connector word right before them
+func useQuickTokens(tokens []quickToken, i, count int, connectors map[string]bool) string {
+	for j := i; j < i+count; j++ {
+		tokens[j].used = true
+	}
+	if i > 0 && !tokens[i-1].used && connectors[tokens[i-1].norm] {
+		tokens[i-1].used = true
+		return tokens[i-1].norm
+	}
+	return ""
+}
+
+// parseQuickDate finds the first date in the line: an explicit date, a relative day, "N ngày trước" or a weekday
+// The time of day is kept from now
+func parseQuickDate(tokens []quickToken, now time.Time) (time.Time, bool) {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, now.Hour(), now.Minute(), now.Second(), 0, now.Location())
+	}
+
+	for i := range tokens {
+		if tokens[i].used {
+			continue
+		}
+		norm := tokens[i].norm
+
+		// Explicit dates
+		if m := quickISODatePattern.FindStringSubmatch(norm); m != nil {
+			year, _ := strconv.Atoi(m[1])
+			month, _ := strconv.Atoi(m[2])
+			day, _ := strconv.Atoi(m[3])
+			if date := at(year, time.Month(month), day); date.Day() == day && date.Month() == time.Month(month) {
+				useQuickTokens(tokens, i, 1, quickDateConnectors)
+				return date, true
+			}
+		}
+		if m := quickDayMonthPattern.FindStringSubmatch(norm); m != nil {
+			day, _ := strconv.Atoi(m[1])
+			month, _ := strconv.Atoi(m[2])
+			year := now.Year()
+			if m[3] != "" {
+				year, _ = strconv.Atoi(m[3])
+				if year < 100 {
+					year += 2000
+				}
+			}
+			date := at(year, time.Month(month), day)
+			if date.Day() == day && date.Month() == time.Month(month) {
+				// Without a year, a date later than today means last year
+				if m[3] == "" && date.After(now) {
+					date = date.AddDate(-1, 0, 0)
+				}
+				useQuickTokens(tokens, i, 1, quickDateConnectors)
+				return date, true
+			}
+		}
+
+		// Relative days
+		for phrase, offset := range quickRelativeDays {
+			if quickPhraseAt(tokens, i, phrase) {
+				useQuickTokens(tokens, i, len(strings.Fields(phrase)), quickDateConnectors)
+				return now.AddDate(0, 0, offset), true
+			}
+		}
+
+		// "3 ngày trước", "3 days ago"
+		if days, err := strconv.Atoi(norm); err == nil && days > 0 && days < 1000 {
+			if quickPhraseAt(tokens, i+1, "ngay truoc") || quickPhraseAt(tokens, i+1, "days ago") {
+				useQuickTokens(tokens, i, 3, quickDateConnectors)
+				return now.AddDate(0, 0, -days), true
+			}
+		}
+
+		// Weekdays, optionally with "last" before or "tuần trước"/"tuần này"/"last week"/"this week" after
+		for phrase, weekday := range quickWeekdays {
+			if !quickPhraseAt(tokens, i, phrase) {
+				continue
+			}
+			length := len(strings.Fields(phrase))
+			start := i
+			weekOffset, strictlyBefore, inWeek := 0, false, false
+
+			switch {
+			case quickPhraseAt(tokens, i+length, "tuan truoc") || quickPhraseAt(tokens, i+length, "last week"):
+				weekOffset, inWeek = -7, true
+				length += 2
+			case quickPhraseAt(tokens, i+length, "tuan nay") || quickPhraseAt(tokens, i+length, "this week"):
+				inWeek = true
+				length += 2
+			case i > 0 && !tokens[i-1].used && tokens[i-1].norm == "last":
+				strictlyBefore = true
+				start, length = i-1, length+1
+			}
+
+			useQuickTokens(tokens, start, length, quickDateConnectors)
+
+			if inWeek {
+				// Weeks start on Monday
+				sinceMonday := (int(now.Weekday()) + 6) % 7
+				dayOfWeek := (int(weekday) + 6) % 7
+				return now.AddDate(0, 0, weekOffset-sinceMonday+dayOfWeek), true
+			}
+
+			daysBack := (int(now.Weekday()) - int(weekday) + 7) % 7
+			if daysBack == 0 && strictlyBefore {
+				daysBack = 7
+			}
+			return now.AddDate(0, 0, -daysBack), true
+		}
+	}
+
+	return now, false
+}
+
+// parseQuickAmount finds the amount in the line
+// An amount with a unit (45k, 2 triệu) wins over a bare number; among bare numbers the largest is taken.
+func parseQuickAmount(tokens []quickToken) (float64, string, bool) {
+	bestIndex, bestLength := -1, 0
+	var bestAmount float64
+	var bestSign string
+	bestHasUnit := false
+
+	for i := range tokens {
+		if tokens[i].used {
+			continue
+		}
+		m := quickAmountPattern.FindStringSubmatch(tokens[i].norm)
+		if m == nil {
+			continue
+		}
+
+		sign, number, unit, fraction := m[1], m[2], m[3], m[4]
+		if unit == "" && fraction != "" {
+			continue
+		}
+
+		length := 1
+		if unit == "" && i+1 < len(tokens) && !tokens[i+1].used {
+			if _, ok := quickAmountUnits[tokens[i+1].norm]; ok {
+				unit, length = tokens[i+1].norm, 2
+			}
+		}
+
+		value, ok := parseQuickNumber(number)
+		if !ok {
+			continue
+		}
+		if fraction != "" {
+			fractionValue, _ := strconv.ParseFloat("0."+fraction, 64)
+			value += fractionValue
+		}
+		if multiplier, ok := quickAmountUnits[unit]; ok {
+			value *= multiplier
+		}
+		if value <= 0 {
+			continue
+		}
+
+		hasUnit := unit != ""
+		better := bestIndex < 0 ||
+			(hasUnit && !bestHasUnit) ||
+			(hasUnit == bestHasUnit && !hasUnit && value > bestAmount)
+		if better {
+			bestIndex, bestLength, bestAmount, bestSign, bestHasUnit = i, length, value, sign, hasUnit
+		}
+		if hasUnit {
+			// The first amount with a unit is the one meant
+			break
+		}
+	}
+
+	if bestIndex < 0 {
+		return 0, "", false
+	}
+
+	useQuickTokens(tokens, bestIndex, bestLength, nil)
+	return math.Round(bestAmount*100) / 100, bestSign, true
+}
+
+// parseQuickNumber reads a number written with "." or "," as thousand separators or as the decimal point
+// Groups of exactly three digits after every separator are thousands (100.000, 1,234,567); otherwise the last
+// separator is the decimal point (1,5 or 1.234,5).
+func parseQuickNumber(number string) (float64, bool) {
+	groups := quickNumberSeparator.Split(number, -1)
+	if len(groups) == 1 {
+		value, err := strconv.ParseFloat(number, 64)
+		return value, err == nil
+	}
+
+	thousands := true
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			thousands = false
+			break
+		}
+	}
+
+	var cleaned string
+	if thousands {
+		cleaned = strings.Join(groups, "")
+	} else {
+		cleaned = strings.Join(groups[:len(groups)-1], "") + "." + groups[len(groups)-1]
+	}
+
+	value, err := strconv.ParseFloat(cleaned, 64)
+	return value, err == nil
+}
+
+// hasQuickKeyword reports whether any one or two word phrase of the line is in keywords
+func hasQuickKeyword(tokens []quickToken, keywords map[string]bool) bool {
+	for i := range tokens {
+		if tokens[i].used {
+			continue
+		}
+		if keywords[tokens[i].norm] {
+			return true
+		}
+		if i+1 < len(tokens) && !tokens[i+1].used && keywords[tokens[i].norm+" "+tokens[i+1].norm] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchQuickAccounts finds accounts named in the line, longest names first, and returns them in line order
+func matchQuickAccounts(tokens []quickToken, accounts []models.Account) []quickAccountMatch {
+	type positioned struct {
+		index int
+		match quickAccountMatch
+	}
+	var found []positioned
+	seen := make(map[string]bool)
+
+	for length := 3; length >= 1; length-- {
+		for i := 0; i+length <= len(tokens); i++ {
+			words := make([]string, 0, length)
+			for j := i; j < i+length; j++ {
+				if tokens[j].used {
+					words = nil
+					break
+				}
+				words = append(words, tokens[j].norm)
+			}
+			if words == nil {
+				continue
+			}
+
+			account := matchQuickAccount(strings.Join(words, " "), accounts)
+			if account == nil || seen[account.ID] {
+				continue
+			}
+			seen[account.ID] = true
+			connector := useQuickTokens(tokens, i, length, quickAccountConnectors)
+			found = append(found, positioned{index: i, match: quickAccountMatch{account: account, connector: connector}})
+		}
+	}
+
+	// Back into line order
+	for i := 1; i < len(found); i++ {
+		for j := i; j > 0 && found[j].index < found[j-1].index; j-- {
+			found[j], found[j-1] = found[j-1], found[j]
+		}
+	}
+
+	matches := make([]quickAccountMatch, len(found))
+	for i, f := range found {
+		matches[i] = f.match
+	}
+	return matches
+}
+
+// matchQuickAccount finds the account a phrase refers to by name, bank name, bank code or alias
+func matchQuickAccount(phrase string, accounts []models.Account) *models.Account {
+	if quickGenericAccountWords[phrase] {
+		return nil
+	}
+
+	compact := strings.ReplaceAll(phrase, " ", "")
+	if alias, ok := quickAccountAliases[compact]; ok {
+		compact = alias
+	}
+
+	for i := range accounts {
+		account := &accounts[i]
+		name := NormalizeSearchText(account.Name)
+
+		if compact == strings.ReplaceAll(name, " ", "") || containsQuickWords(name, phrase) {
+			return account
+		}
+		if account.BankName != nil && compact == strings.ReplaceAll(NormalizeSearchText(*account.BankName), " ", "") {
+			return account
+		}
+		if account.BankCode != nil && compact == strings.ToLower(*account.BankCode) {
+			return account
+		}
+		if account.Type == "cash" && quickCashAliases[compact] {
+			return account
+		}
+	}
+
+	return nil
+}
+
+// matchQuickCategory picks a category of the right type named in the line or its tags, or suggested by a keyword
+func matchQuickCategory(tokens []quickToken, tags []string, transactionType string, categories []models.Category) *models.Category {
+	var words []string
+	for _, token := range tokens {
+		if !token.used {
+			words = append(words, token.norm)
+		}
+	}
+	for _, tag := range tags {
+		words = append(words, NormalizeSearchText(strings.ReplaceAll(tag, "_", " ")))
+	}
+	line := strings.Join(words, " ")
+
+	var eligible []*models.Category
+	for i := range categories {
+		if categories[i].Type == transactionType || categories[i].Type == "both" {
+			eligible = append(eligible, &categories[i])
+		}
+	}
+
+	// A category named in the line, preferring the longest name
+	var best *models.Category
+	bestLength := 0
+	for _, category := range eligible {
+		name := NormalizeSearchText(category.Name)
+		if containsQuickWords(line, name) && len(name) > bestLength {
+			best, bestLength = category, len(name)
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	// A category suggested by a keyword
+	for i := range words {
+		candidates := []string{words[i]}
+		if i+1 < len(words) {
+			candidates = append([]string{words[i] + " " + words[i+1]}, candidates...)
+		}
+		for _, candidate := range candidates {
+			for _, hint := range quickCategoryKeywords[candidate] {
+				for _, category := range eligible {
+					if containsQuickWords(NormalizeSearchText(category.Name), hint) {
+						return category
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// containsQuickWords reports whether phrase appears in text as whole words
+func containsQuickWords(text, phrase string) bool {
+	if phrase == "" {
+		return false
+	}
+	return strings.Contains(" "+text+" ", " "+phrase+" ")
+}
+
+// quickIsFromConnector and quickIsToConnector tell the direction a connector word gives a transfer
+func quickIsFromConnector(connector string) bool {
+	return connector == "tu" || connector == "from"
+}
+
+func quickIsToConnector(connector string) bool {
+	return connector == "sang" || connector == "to" || connector == "vao" || connector == "into"
+}
+
+// GuessCategory picks a category of the given type named in text or suggested by one of its keywords
+func GuessCategory(text, transactionType string, categories []models.Category) *models.Category {
+	return matchQuickCategory(tokenizeQuickAdd(text), nil, transactionType, categories)
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"finance-hub-api/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	bankCode := "VCB"
+	accounts := []models.Account{
+		{ID: "momo", Name: "Ví MoMo", Type: "ewallet"},
+		{ID: "vcb", Name: "Vietcombank", Type: "bank", BankCode: &bankCode},
+		{ID: "cash", Name: "Tiền mặt", Type: "cash"},
+	}
+	categories := []models.Category{
+		{ID: "food", Name: "Ăn uống", Type: "expense"},
+		{ID: "transport", Name: "Di chuyển", Type: "expense"},
+		{ID: "salary", Name: "Lương", Type: "income"},
+	}
+	// A Wednesday
+	now := time.Date(2025, 3, 12, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		text        string
+		txType      string
+		amount      float64
+		date        string
+		accountID   string
+		toAccountID string
+		categoryID  string
+		tags        []string
+		description string
+		missing     []string
+	}{
+		{
+			text: "cafe 45k hôm qua bằng momo", txType: "expense", amount: 45000, date: "2025-03-11",
+			accountID: "momo", categoryID: "food", tags: []string{}, description: "cafe", missing: []string{},
+		},
+		{
+			text: "cafe 45k hôm qua bằng momo #Work", txType: "expense", amount: 45000, date: "2025-03-11",
+			accountID: "momo", categoryID: "food", tags: []string{"work"}, description: "cafe", missing: []string{},
+		},
+		{
+			text: "grab 1.5tr hôm nay", txType: "expense", amount: 1500000, date: "2025-03-12",
+			categoryID: "transport", tags: []string{}, description: "grab", missing: []string{"account"},
+		},
+		{
+			text: "taxi 1tr5 yesterday", txType: "expense", amount: 1500000, date: "2025-03-11",
+			categoryID: "transport", tags: []string{}, description: "taxi", missing: []string{"account"},
+		},
+		{
+			text: "lương 20 triệu vcb", txType: "income", amount: 20000000, date: "2025-03-12",
+			accountID: "vcb", categoryID: "salary", tags: []string{}, description: "lương", missing: []string{},
+		},
+		{
+			text: "phở 100.000 thứ 2 tuần trước tiền mặt", txType: "expense", amount: 100000, date: "2025-03-03",
+			accountID: "cash", categoryID: "food", tags: []string{}, description: "phở", missing: []string{},
+		},
+		{
+			text: "ăn trưa 50,000 15/3", txType: "expense", amount: 50000, date: "2024-03-15",
+			categoryID: "food", tags: []string{}, description: "ăn trưa", missing: []string{"account"},
+		},
+		{
+			text: "2m from vcb to momo", txType: "transfer", amount: 2000000, date: "2025-03-12",
+			accountID: "vcb", toAccountID: "momo", tags: []string{}, missing: []string{},
+		},
+		{
+			text: "+500k momo", txType: "income", amount: 500000, date: "2025-03-12",
+			accountID: "momo", tags: []string{}, missing: []string{"category"},
+		},
+		{
+			text: "cafe", txType: "expense", date: "2025-03-12",
+			categoryID: "food", tags: []string{}, description: "cafe", missing: []string{"amount", "account"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			parsed := ParseQuickAdd(tt.text, now, accounts, categories)
+			draft := parsed.Draft
+
+			if draft.Type != tt.txType {
+				t.Errorf("type = %s, want %s", draft.Type, tt.txType)
+			}
+			if draft.Amount != tt.amount {
+				t.Errorf("amount = %v, want %v", draft.Amount, tt.amount)
+			}
+			if date := draft.TransactionDate.Format("2006-01-02"); date != tt.date {
+				t.Errorf("date = %s, want %s", date, tt.date)
+			}
+			if draft.AccountID != tt.accountID {
+				t.Errorf("account = %q, want %q", draft.AccountID, tt.accountID)
+			}
+			if got := stringValue(draft.ToAccountID); got != tt.toAccountID {
+				t.Errorf("to account = %q, want %q", got, tt.toAccountID)
+			}
+			if got := stringValue(draft.CategoryID); got != tt.categoryID {
+				t.Errorf("category = %q, want %q", got, tt.categoryID)
+			}
+			if !reflect.DeepEqual(draft.Tags, tt.tags) {
+				t.Errorf("tags = %v, want %v", draft.Tags, tt.tags)
+			}
+			if got := stringValue(draft.Description); got != tt.description {
+				t.Errorf("description = %q, want %q", got, tt.description)
+			}
+			if !reflect.DeepEqual(parsed.Missing, tt.missing) {
+				t.Errorf("missing = %v, want %v", parsed.Missing, tt.missing)
+			}
+		})
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -5,26 +5,26 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"mime/multipart"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/google/uuid"
 )
 
+// R2Storage stores files in a private bucket; they are read and uploaded through signed URLs that expire
 type R2Storage struct {
-	client        *s3.S3
-	bucket        string
-	publicBaseURL string
+	client          *s3.S3
+	bucket          string
+	publicBaseURL   string
+	signedURLExpiry time.Duration
 }
 
 // NewR2Storage creates a new R2 storage client
-func NewR2Storage(endpoint, accessKeyID, secretAccessKey, bucket, publicBaseURL string) (*R2Storage, error) {
+func NewR2Storage(endpoint, accessKeyID, secretAccessKey, bucket, publicBaseURL string, signedURLExpiry time.Duration) (*R2Storage, error) {
 	// Create AWS session for R2 (R2 is S3-compatible)
 	sess, err := session.NewSession(&aws.Config{
 		Region:      aws.String("auto"), // R2 uses "auto" region
@@ -37,61 +37,53 @@ func NewR2Storage(endpoint, accessKeyID, secretAccessKey, bucket, publicBaseURL
 	}
 
 	return &R2Storage{
-		client:        s3.New(sess),
-		bucket:        bucket,
-		publicBaseURL: publicBaseURL,
+		client:          s3.New(sess),
+		bucket:          bucket,
+		publicBaseURL:   publicBaseURL,
+		signedURLExpiry: signedURLExpiry,
 	}, nil
 }
 
-// UploadFile uploads a file to R2 and returns the public URL
-func (r *R2Storage) UploadFile(ctx context.Context, file multipart.File, header *multipart.FileHeader, folder string) (string, error) {
-	// Read file content
-	fileBytes, err := io.ReadAll(file)
+// PutObject uploads content under the given key and returns its URL
+func (r *R2Storage) PutObject(ctx context.Context, key string, content []byte, contentType string) (string, error) {
+	_, err := r.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(r.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(content),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(content))),
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read file: %w", err)
+		return "", fmt.Errorf("failed to upload to R2: %w", err)
 	}
 
-	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	filename := fmt.Sprintf("%s-%s%s", uuid.New().String(), time.Now().Format("20060102150405"), ext)
-	
-	// Create full path
-	key := filename
-	if folder != "" {
-		key = fmt.Sprintf("%s/%s", strings.Trim(folder, "/"), filename)
-	}
+	return r.URLForKey(key), nil
+}
 
-	// Determine content type
-	contentType := header.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
+// GetObject downloads the content stored under a key
+func (r *R2Storage) GetObject(ctx context.Context, key string) ([]byte, string, error) {
+	output, err := r.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, "", ErrObjectNotFound
+		}
+		return nil, "", fmt.Errorf("failed to download from R2: %w", err)
 	}
+	defer output.Body.Close()
 
-	// Upload to R2
-	_, err = r.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
-		Bucket:        aws.String(r.bucket),
-		Key:           aws.String(key),
-		Body:          bytes.NewReader(fileBytes),
-		ContentType:   aws.String(contentType),
-		ContentLength: aws.Int64(header.Size),
-		// Make the object publicly accessible
-		ACL: aws.String("public-read"),
-	})
+	content, err := io.ReadAll(output.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload to R2: %w", err)
+		return nil, "", fmt.Errorf("failed to read R2 object: %w", err)
 	}
 
-	// Generate public URL
-	publicURL := fmt.Sprintf("%s/%s", strings.TrimSuffix(r.publicBaseURL, "/"), key)
-	
-	return publicURL, nil
+	return content, aws.StringValue(output.ContentType), nil
 }
 
-// DeleteFile deletes a file from R2
-func (r *R2Storage) DeleteFile(ctx context.Context, fileURL string) error {
-	// Extract key from URL
-	key := strings.TrimPrefix(fileURL, r.publicBaseURL+"/")
-	
+// DeleteObject deletes the object stored under a key
+func (r *R2Storage) DeleteObject(ctx context.Context, key string) error {
 	_, err := r.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(r.bucket),
 		Key:    aws.String(key),
@@ -103,28 +95,118 @@ func (r *R2Storage) DeleteFile(ctx context.Context, fileURL string) error {
 	return nil
 }
 
-// ValidateFile validates file size and type
-func ValidateFile(file multipart.File, header *multipart.FileHeader, maxSize int64, allowedTypes []string) error {
+// ListObjects lists every object whose key starts with the prefix
+func (r *R2Storage) ListObjects(ctx context.Context, prefix string) ([]StoredObject, error) {
+	var objects []StoredObject
+	err := r.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(r.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			objects = append(objects, StoredObject{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list R2 objects: %w", err)
+	}
+
+	return objects, nil
+}
+
+// URLForKey returns the unsigned URL of a key
+// The bucket is private, so the URL identifies the object but only opens once signed
+func (r *R2Storage) URLForKey(key string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(r.publicBaseURL, "/"), key)
+}
+
+// KeyFromURL returns the key of a URL, signed or not, or false when the URL is not in this bucket
+func (r *R2Storage) KeyFromURL(fileURL string) (string, bool) {
+	prefixes := []string{
+		strings.TrimSuffix(r.publicBaseURL, "/") + "/",
+		// Signed URLs address the bucket path-style on the R2 endpoint
+		fmt.Sprintf("%s/%s/", strings.TrimSuffix(r.client.Endpoint, "/"), r.bucket),
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(fileURL, prefix) {
+			key, _, _ := strings.Cut(strings.TrimPrefix(fileURL, prefix), "?")
+			return key, key != ""
+		}
+	}
+	return "", false
+}
+
+// PresignGet returns a download URL for a key that expires after the signed URL expiry
+func (r *R2Storage) PresignGet(key string) (string, time.Time, error) {
+	req, _ := r.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+	})
+
+	expiresAt := time.Now().Add(r.signedURLExpiry)
+	signedURL, err := req.Presign(r.signedURLExpiry)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign R2 download URL: %w", err)
+	}
+
+	return signedURL, expiresAt, nil
+}
+
+// PresignPut returns a URL to upload one object directly to the bucket
+// The client must send the same Content-Type and Content-Length that were signed.
+func (r *R2Storage) PresignPut(key, contentType string, size int64) (string, time.Time, error) {
+	req, _ := r.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:        aws.String(r.bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	})
+
+	expiresAt := time.Now().Add(r.signedURLExpiry)
+	signedURL, err := req.Presign(r.signedURLExpiry)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign R2 upload URL: %w", err)
+	}
+
+	return signedURL, expiresAt, nil
+}
+
+// SignURL turns the URL of a stored file into a download URL that expires
+// URLs outside the bucket, such as Google profile pictures, are returned unchanged
+func (r *R2Storage) SignURL(fileURL string) string {
+	key, ok := r.KeyFromURL(fileURL)
+	if !ok {
+		return fileURL
+	}
+
+	signedURL, _, err := r.PresignGet(key)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return fileURL
+	}
+	return signedURL
+}
+
+// ValidateUpload validates the declared size and type of a file before it is uploaded directly
+// Files sent through the API are checked against their content with ReadUpload instead.
+func ValidateUpload(size int64, contentType string, maxSize int64, allowedTypes []string) error {
 	// Check file size
-	if header.Size > maxSize {
+	if size > maxSize {
 		return fmt.Errorf("file size exceeds limit of %d bytes", maxSize)
 	}
 
 	// Check file type
-	contentType := header.Header.Get("Content-Type")
+	contentType = normalizeContentType(contentType)
 	if contentType == "" {
 		return fmt.Errorf("content type not specified")
 	}
 
 	// Validate against allowed types
-	allowed := false
-	for _, t := range allowedTypes {
-		if strings.HasPrefix(contentType, t) || contentType == t {
-			allowed = true
-			break
-		}
-	}
-	if !allowed {
+	if !typeAllowed(contentType, allowedTypes) {
 		return fmt.Errorf("file type %s not allowed", contentType)
 	}
 
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"finance-hub-api/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// vietnameseBaseLetters maps every accented Vietnamese letter to its unaccented base letter
+var vietnameseBaseLetters = func() map[rune]rune {
+	groups := map[rune]string{
+		'a': "àáảãạăằắẳẵặâầấẩẫậ",
+		'e': "èéẻẽẹêềếểễệ",
+		'i': "ìíỉĩị",
+		'o': "òóỏõọôồốổỗộơờớởỡợ",
+		'u': "ùúủũụưừứửữự",
+		'y': "ỳýỷỹỵ",
+		'd': "đ",
+	}
+	letters := make(map[rune]rune)
+	for base, accented := range groups {
+		for _, r := range accented {
+			letters[r] = base
+		}
+	}
+	return letters
+}()
+
+// searchFieldAliases maps the field names accepted in search queries to their canonical names
+var searchFieldAliases = map[string]string{
+	"merchant":    models.SearchFieldMerchant,
+	"description": models.SearchFieldDescription,
+	"desc":        models.SearchFieldDescription,
+	"notes":       models.SearchFieldNotes,
+	"note":        models.SearchFieldNotes,
+	"tag":         models.SearchFieldTag,
+	"tags":        models.SearchFieldTag,
+	"category":    models.SearchFieldCategory,
+	"cat":         models.SearchFieldCategory,
+	"account":     models.SearchFieldAccount,
+	"type":        models.SearchFieldType,
+	"amount":      models.SearchFieldAmount,
+	"date":        models.SearchFieldDate,
+}
+
+// NormalizeSearchText lower-cases text and strips Vietnamese diacritics so "Phở Hà Nội" matches "pho ha noi"
+func NormalizeSearchText(text string) string {
+	var builder strings.Builder
+	builder.Grow(len(text))
+
+	for _, r := range strings.ToLower(text) {
+		if base, ok := vietnameseBaseLetters[r]; ok {
+			r = base
+		}
+		if unicode.Is(unicode.Mn, r) {
+			// Combining marks left over from decomposed input
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// ParseSearchQuery parses a transaction search query such as
+// `merchant:grab amount>100000 tag:work -category:food "bun bo"` into search terms
+//
+// Words without a field prefix are matched against merchant, description, notes and tags.
+// A leading "-" excludes matches and double quotes group words into a phrase or a value with spaces.
+func ParseSearchQuery(query string) ([]models.SearchTerm, error) {
+	tokens, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make([]models.SearchTerm, 0, len(tokens))
+	for _, token := range tokens {
+		term := models.SearchTerm{}
+		if strings.HasPrefix(token, "-") && len(token) > 1 {
+			term.Negated = true
+			token = token[1:]
+		}
+
+		field, operator, value := splitSearchToken(token)
+		canonical, known := searchFieldAliases[strings.ToLower(field)]
+		if !known {
+			// Not a field filter, so the whole token is free text
+			canonical, operator, value = "", ":", token
+		}
+
+		value = strings.ReplaceAll(value, "\"", "")
+		if strings.TrimSpace(value) == "" {
+			if canonical == "" {
+				continue
+			}
+			return nil, fmt.Errorf("missing value for %s in search query", field)
+		}
+
+		term.Field = canonical
+		term.Operator = operator
+		term.Value, err = normalizeSearchValue(canonical, operator, value)
+		if err != nil {
+			return nil, err
+		}
+		if term.Value == "" {
+			continue
+		}
+
+		terms = append(terms, term)
+	}
+
+	return terms, nil
+}
+
+// SearchDateRange returns the start and exclusive end of a search date value (YYYY-MM-DD or YYYY-MM)
+func SearchDateRange(value string) (time.Time, time.Time, error) {
+	if day, err := time.Parse("2006-01-02", value); err == nil {
+		return day, day.AddDate(0, 0, 1), nil
+	}
+	if month, err := time.Parse("2006-01", value); err == nil {
+		return month, month.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q in search query, expected YYYY-MM-DD or YYYY-MM", value)
+}
+
+// tokenizeSearchQuery splits a query on whitespace while keeping quoted sections together
+func tokenizeSearchQuery(query string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in search query")
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, nil
+}
+
+// splitSearchToken splits "field>=value" into its field, operator and value
+// Tokens starting with a quote are phrases and never carry a field
+func splitSearchToken(token string) (string, string, string) {
+	if strings.HasPrefix(token, "\"") {
+		return "", ":", token
+	}
+
+	index := strings.IndexAny(token, ":<>=")
+	if index <= 0 {
+		return "", ":", token
+	}
+
+	field, rest := token[:index], token[index:]
+	for _, operator := range []string{">=", "<=", ">", "<", "=", ":"} {
+		if strings.HasPrefix(rest, operator) {
+			return field, operator, rest[len(operator):]
+		}
+	}
+	return "", ":", token
+}
+
+// normalizeSearchValue validates a term value and brings it into the form stored in the search index
+func normalizeSearchValue(field, operator, value string) (string, error) {
+	comparison := operator != ":" && operator != "="
+
+	switch field {
+	case models.SearchFieldAmount:
+		cleaned := strings.NewReplacer(",", "", "_", "").Replace(value)
+		amount, err := strconv.ParseFloat(cleaned, 64)
+		if err != nil || amount < 0 {
+			return "", fmt.Errorf("invalid amount %q in search query", value)
+		}
+		return cleaned, nil
+
+	case models.SearchFieldDate:
+		if _, _, err := SearchDateRange(value); err != nil {
+			return "", err
+		}
+		return value, nil
+
+	case models.SearchFieldType:
+		if comparison {
+			return "", fmt.Errorf("type only supports the : operator in search query")
+		}
+		value = strings.ToLower(value)
+		if value != "income" && value != "expense" && value != "transfer" {
+			return "", fmt.Errorf("invalid type %q in search query, expected income, expense or transfer", value)
+		}
+		return value, nil
+
+	default:
+		if comparison {
+			return "", fmt.Errorf("%s only supports the : operator in search query", field)
+		}
+		return NormalizeSearchText(value), nil
+	}
+}
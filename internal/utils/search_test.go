@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"finance-hub-api/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []models.SearchTerm
+	}{
+		{
+			name:  "fields, comparison, negation and phrase",
+			query: `merchant:grab amount>100000 tag:work -category:food "bun bo"`,
+			want: []models.SearchTerm{
+				{Field: models.SearchFieldMerchant, Operator: ":", Value: "grab"},
+				{Field: models.SearchFieldAmount, Operator: ">", Value: "100000"},
+				{Field: models.SearchFieldTag, Operator: ":", Value: "work"},
+				{Field: models.SearchFieldCategory, Operator: ":", Value: "food", Negated: true},
+				{Operator: ":", Value: "bun bo"},
+			},
+		},
+		{
+			name:  "free text loses diacritics",
+			query: "Phở  Hà Nội",
+			want: []models.SearchTerm{
+				{Operator: ":", Value: "pho"},
+				{Operator: ":", Value: "ha"},
+				{Operator: ":", Value: "noi"},
+			},
+		},
+		{
+			name:  "amount with thousands separators",
+			query: "amount>=1,500,000",
+			want:  []models.SearchTerm{{Field: models.SearchFieldAmount, Operator: ">=", Value: "1500000"}},
+		},
+		{
+			name:  "month date",
+			query: "date:2024-05",
+			want:  []models.SearchTerm{{Field: models.SearchFieldDate, Operator: ":", Value: "2024-05"}},
+		},
+		{
+			name:  "alias with quoted value",
+			query: `note:"Tiền nhà"`,
+			want:  []models.SearchTerm{{Field: models.SearchFieldNotes, Operator: ":", Value: "tien nha"}},
+		},
+		{
+			name:  "unknown field is free text",
+			query: "foo:bar",
+			want:  []models.SearchTerm{{Operator: ":", Value: "foo:bar"}},
+		},
+		{
+			name:  "empty phrase is skipped",
+			query: `""`,
+			want:  []models.SearchTerm{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseSearchQuery(%q) returned error: %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{name: "unterminated quote", query: `merchant:grab "bun bo`, wantErr: "unterminated quote"},
+		{name: "amount is not a number", query: "amount>abc", wantErr: "invalid amount"},
+		{name: "negative amount", query: "amount:-5", wantErr: "invalid amount"},
+		{name: "month out of range", query: "date:2024-13-01", wantErr: "invalid date"},
+		{name: "date in another format", query: "date:05/2024", wantErr: "invalid date"},
+		{name: "missing value", query: "merchant:", wantErr: "missing value for merchant"},
+		{name: "unknown type", query: "type:refund", wantErr: "invalid type"},
+		{name: "comparison on a text field", query: "merchant>x", wantErr: "only supports the : operator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSearchQuery(tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseSearchQuery(%q) error = %v, want it to contain %q", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
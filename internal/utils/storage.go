@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"finance-hub-api/internal/config"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrObjectNotFound is returned when no object is stored under a key
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage keeps uploaded files
+// Files are private: a file's URL identifies it and only opens once signed, and signed URLs expire.
+type Storage interface {
+	// PutObject stores content under a key and returns its URL
+	PutObject(ctx context.Context, key string, content []byte, contentType string) (string, error)
+	// GetObject returns the content and content type stored under a key
+	GetObject(ctx context.Context, key string) ([]byte, string, error)
+	// DeleteObject deletes the object stored under a key; deleting a missing object is not an error
+	DeleteObject(ctx context.Context, key string) error
+	// ListObjects lists every object whose key starts with the prefix
+	ListObjects(ctx context.Context, prefix string) ([]StoredObject, error)
+	// PresignGet returns a download URL for a key and when it expires
+	PresignGet(key string) (string, time.Time, error)
+	// PresignPut returns a URL to upload one object directly, with the Content-Type and Content-Length the client must send
+	PresignPut(key, contentType string, size int64) (string, time.Time, error)
+	// URLForKey returns the unsigned URL of a key
+	URLForKey(key string) string
+	// KeyFromURL returns the key of a URL, signed or not, or false when the URL is not in this storage
+	KeyFromURL(fileURL string) (string, bool)
+	// SignURL turns the URL of a stored file into a download URL; other URLs are returned unchanged
+	SignURL(fileURL string) string
+}
+
+// Every backend implements Storage
+var (
+	_ Storage = (*R2Storage)(nil)
+	_ Storage = (*LocalStorage)(nil)
+	_ Storage = (*MemoryStorage)(nil)
+)
+
+// StoredObject describes a stored file
+type StoredObject struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// NewStorage creates the storage backend chosen in the configuration
+func NewStorage(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Backend {
+	case config.StorageBackendR2:
+		return NewR2Storage(
+			cfg.R2.Endpoint,
+			cfg.R2.AccessKeyID,
+			cfg.R2.SecretAccessKey,
+			cfg.R2.BucketName,
+			cfg.R2.PublicBaseURL,
+			cfg.Storage.SignedURLExpiry,
+		)
+	case config.StorageBackendLocal:
+		return NewLocalStorage(cfg.Storage.LocalDir, NewURLSigner(cfg.Storage.FileBaseURL, cfg.JWT.Secret, cfg.Storage.SignedURLExpiry))
+	case config.StorageBackendMemory:
+		return NewMemoryStorage(NewURLSigner(cfg.Storage.FileBaseURL, cfg.JWT.Secret, cfg.Storage.SignedURLExpiry)), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+// contentTypeExts maps the content types files are stored as to the extension of their keys
+var contentTypeExts = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+}
+
+// ObjectKey builds a unique key for a file of the given content type in a folder
+// The extension follows the content type, never the client's file name, so a key cannot make a file
+// look like another type; unknown types get ".bin".
+func ObjectKey(folder, contentType string) string {
+	ext, ok := contentTypeExts[normalizeContentType(contentType)]
+	if !ok {
+		ext = ".bin"
+	}
+	return fmt.Sprintf("%s/%s%s", strings.Trim(folder, "/"), uuid.New().String(), ext)
+}
+
+// validObjectKey reports whether a key is a clean relative path that cannot escape the storage root
+func validObjectKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}